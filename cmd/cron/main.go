@@ -0,0 +1,204 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zpkg/blend-go-sdk/cron"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:   "cron",
+		Short: "cron validates and previews cron schedules and job configs",
+	}
+	root.AddCommand(NewParseCommand(), NewLintCommand())
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// NewParseCommand returns a new parse command.
+func NewParseCommand() *cobra.Command {
+	var count *int
+	var zone *string
+	var after *string
+	parse := &cobra.Command{
+		Use:   "parse [schedule]",
+		Short: "parse parses a schedule string, and prints its full form and next firing times",
+		Example: strings.Join([]string{
+			`  cron parse "0 0 9 * * MON-FRI"`,
+			`  cron parse "*/15 * * * *" --count 10 --zone America/New_York`,
+			`  cron parse "CRON_TZ=Europe/London 0 30 1 * * *" --after 2024-03-30T00:00:00Z`,
+		}, "\n"),
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			location := time.UTC
+			if *zone != "" {
+				var err error
+				location, err = time.LoadLocation(*zone)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+			start := time.Now()
+			if *after != "" {
+				var err error
+				start, err = time.Parse(time.RFC3339, *after)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+			if err := printSchedule(os.Stdout, strings.Join(args, " "), location, start, *count); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		},
+	}
+	count = parse.Flags().IntP("count", "n", 5, "the number of next firing times to print")
+	zone = parse.Flags().StringP("zone", "z", "", "the IANA time zone to compute firing times in, e.g. America/New_York (defaults to UTC)")
+	after = parse.Flags().StringP("after", "a", "", "the RFC3339 time to compute firing times after (defaults to now)")
+	return parse
+}
+
+// NewLintCommand returns a new lint command.
+func NewLintCommand() *cobra.Command {
+	lint := &cobra.Command{
+		Use:   "lint [file...]",
+		Short: "lint validates yaml files of job configs, keyed by job name, with an optional schedule per job",
+		Example: strings.Join([]string{
+			`  cron lint jobs.yml`,
+			``,
+			`  # jobs.yml`,
+			`  reconcile:`,
+			`    schedule: "CRON_TZ=America/New_York 0 0 2 * * *"`,
+			`    timeout: 30m`,
+			`    misfirePolicy: run_once`,
+			`  report:`,
+			`    dependsOn: [ reconcile ]`,
+		}, "\n"),
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var failed bool
+			for _, path := range args {
+				for _, err := range lintFile(path) {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+					failed = true
+				}
+			}
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	return lint
+}
+
+// printSchedule prints the full form of a schedule and its next firing times.
+func printSchedule(wr io.Writer, value string, location *time.Location, after time.Time, count int) error {
+	schedule, err := cron.ParseSchedule(value)
+	if err != nil {
+		return err
+	}
+
+	inner := schedule
+	if typed, ok := schedule.(*cron.LocationSchedule); ok {
+		inner = typed.Schedule
+		location = typed.LocationOrDefault()
+	}
+	switch inner.(type) {
+	case cron.NeverSchedule, *cron.NeverSchedule:
+		fmt.Fprintln(wr, "schedule is never; the job only runs on demand")
+		return nil
+	}
+	if typed, ok := inner.(*cron.StringSchedule); ok {
+		fields := strings.Fields(typed.FullString())
+		labels := []string{"seconds", "minutes", "hours", "day of month", "month", "day of week", "year"}
+		fmt.Fprintf(wr, "full:\t%s\n", typed.FullString())
+		for index, field := range fields {
+			fmt.Fprintf(wr, "\t%-14s%s\n", labels[index], field)
+		}
+	} else if typed, ok := inner.(fmt.Stringer); ok {
+		fmt.Fprintf(wr, "schedule:\t%s\n", typed.String())
+	}
+	fmt.Fprintf(wr, "zone:\t%s\n", location.String())
+
+	fmt.Fprintln(wr, "next:")
+	next := after.In(location)
+	for x := 0; x < count; x++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			fmt.Fprintln(wr, "\t(none)")
+			break
+		}
+		fmt.Fprintf(wr, "\t%s\t%s\n", next.Format(time.RFC3339), next.Format("Mon MST"))
+	}
+	return nil
+}
+
+// lintJob is a job config as it appears in a file, with an optional schedule.
+type lintJob struct {
+	Schedule       string `yaml:"schedule"`
+	cron.JobConfig `yaml:",inline"`
+}
+
+// lintFile returns the errors found in a yaml file of job configs.
+func lintFile(path string) (errs []error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+
+	jobs := make(map[string]lintJob)
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&jobs); err != nil && err != io.EOF {
+		return []error{err}
+	}
+
+	jobNames := make([]string, 0, len(jobs))
+	for jobName := range jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	loaded := make([]cron.Job, 0, len(jobs))
+	for _, jobName := range jobNames {
+		job := jobs[jobName]
+		if job.Schedule != "" {
+			if _, err := cron.ParseSchedule(job.Schedule); err != nil {
+				errs = append(errs, fmt.Errorf("%s: schedule: %v", jobName, err))
+			}
+		}
+		if err := job.JobConfig.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", jobName, err))
+		}
+		loaded = append(loaded, cron.NewJob(cron.OptJobName(jobName), cron.OptJobConfig(job.JobConfig)))
+	}
+	if len(errs) > 0 {
+		return
+	}
+	// loading the jobs checks their dependencies exist and do not form a cycle.
+	if err := cron.New().LoadJobs(loaded...); err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
@@ -0,0 +1,93 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+func TestPrintSchedule(t *testing.T) {
+	its := assert.New(t)
+
+	after := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	buffer := new(bytes.Buffer)
+	its.Nil(printSchedule(buffer, "CRON_TZ=America/New_York 0 0 9 * * *", time.UTC, after, 2))
+	output := buffer.String()
+	its.Contains(output, "full:\t0 0 9 * * * *")
+	its.Contains(output, "zone:\tAmerica/New_York")
+	its.Contains(output, "\t2024-01-02T09:00:00-05:00\tTue EST\n\t2024-01-03T09:00:00-05:00\tWed EST\n")
+	its.Equal(2, strings.Count(output, "2024-01-0"))
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	its.Nil(err)
+	buffer.Reset()
+	its.Nil(printSchedule(buffer, "0 30 1 * * *", tokyo, after, 1))
+	its.Contains(buffer.String(), "zone:\tAsia/Tokyo")
+	its.Contains(buffer.String(), "\t2024-01-03T01:30:00+09:00\tWed JST\n")
+
+	buffer.Reset()
+	its.Nil(printSchedule(buffer, "@never", time.UTC, after, 1))
+	its.Equal("schedule is never; the job only runs on demand\n", buffer.String())
+
+	buffer.Reset()
+	its.NotNil(printSchedule(buffer, "not a schedule", time.UTC, after, 1))
+}
+
+func TestLintFile(t *testing.T) {
+	its := assert.New(t)
+
+	path := writeLintFile(t, `
+reconcile:
+  schedule: "CRON_TZ=America/New_York 0 0 2 * * *"
+  timeout: 30m
+  misfirePolicy: run_once
+report:
+  dependsOn: [ reconcile ]
+`)
+	its.Empty(lintFile(path))
+
+	path = writeLintFile(t, `
+reconcile:
+  schedule: "0 0 2 * * *"
+  notAField: true
+`)
+	errs := lintFile(path)
+	its.Len(errs, 1)
+	its.Contains(errs[0].Error(), "notAField")
+
+	path = writeLintFile(t, `
+reconcile:
+  dependsOn: [ report ]
+report:
+  dependsOn: [ reconcile ]
+`)
+	errs = lintFile(path)
+	its.Len(errs, 1)
+	its.True(ex.Is(errs[0], cron.ErrJobDependencyCycle))
+
+	errs = lintFile(filepath.Join(t.TempDir(), "missing.yml"))
+	its.Len(errs, 1)
+}
+
+func writeLintFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jobs.yml")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
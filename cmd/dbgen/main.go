@@ -0,0 +1,122 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/db/dbutil"
+)
+
+var (
+	flagDSN     string
+	flagSchema  string
+	flagPackage string
+	flagOutput  string
+	flagTables  flagStrings
+)
+
+func init() {
+	flag.StringVar(&flagDSN, "dsn", "", "The database connection string; if unset the connection is read from the environment (e.g. DATABASE_URL or DB_HOST etc.)")
+	flag.StringVar(&flagSchema, "schema", db.DefaultSchema, "The schema to read tables from")
+	flag.StringVar(&flagPackage, "package", "model", "The package name of the generated source")
+	flag.StringVar(&flagOutput, "output", "-", "The file to write the generated source to, or - for stdout")
+	flag.Var(&flagTables, "table", "A table to generate a struct for, can be multiple; if unset structs are generated for every table in the schema")
+
+	oldUsage := flag.Usage
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), `database mapped struct generator
+
+> dbgen [--dsn=DSN] [--schema=SCHEMA] [--table=TABLE...] [--package=PACKAGE] [--output=FILE]
+
+Reads the tables of a schema and generates a struct with db tags and a TableName method for each.
+
+`)
+		oldUsage()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	if err := run(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	options := []db.Option{db.OptConfigFromEnv()}
+	if flagDSN != "" {
+		cfg, err := db.NewConfigFromDSN(flagDSN)
+		if err != nil {
+			return err
+		}
+		options = append(options, db.OptConfig(cfg))
+	}
+	conn, err := db.Open(db.New(options...))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	tables, err := dbutil.IntrospectSchema(ctx, conn, flagSchema)
+	if err != nil {
+		return err
+	}
+	if len(flagTables) > 0 {
+		tables = filterTables(tables, flagTables)
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("no tables found in schema: %s", flagSchema)
+	}
+
+	var output io.Writer = os.Stdout
+	if flagOutput != "-" {
+		file, err := os.Create(flagOutput)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+	return dbutil.GenerateStructs(output, flagPackage, tables)
+}
+
+func filterTables(tables []dbutil.Table, names []string) (output []dbutil.Table) {
+	included := map[string]bool{}
+	for _, name := range names {
+		included[name] = true
+	}
+	for _, table := range tables {
+		if included[table.Name] {
+			output = append(output, table)
+		}
+	}
+	return
+}
+
+type flagStrings []string
+
+func (fs flagStrings) String() string {
+	return strings.Join(fs, ", ")
+}
+
+func (fs *flagStrings) Set(flagValue string) error {
+	if flagValue == "" {
+		return fmt.Errorf("invalid flag value; is empty")
+	}
+	*fs = append(*fs, flagValue)
+	return nil
+}
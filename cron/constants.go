@@ -16,8 +16,31 @@ const (
 	DefaultTimeout               time.Duration = 0
 	DefaultHistoryRestoreTimeout               = 5 * time.Second
 	DefaultShutdownGracePeriod   time.Duration = 0
+	DefaultLockAtLeastFor                      = 30 * time.Second
 )
 
+// Retry defaults.
+const (
+	// DefaultRetryMaxAttempts is the default number of attempts per invocation, i.e. no retries.
+	DefaultRetryMaxAttempts = 1
+	// DefaultRetryDelay is the default base delay of the exponential backoff between attempts.
+	DefaultRetryDelay = time.Second
+)
+
+// DefaultOverlapPolicy is the default overlap policy.
+const DefaultOverlapPolicy = OverlapPolicySkip
+
+// Misfire defaults.
+const (
+	// DefaultMisfirePolicy is the default misfire policy.
+	DefaultMisfirePolicy = MisfirePolicySkip
+	// DefaultMisfireMaxRuns is the default maximum number of missed ticks run by `MisfirePolicyRunAll`.
+	DefaultMisfireMaxRuns = 10
+)
+
+// DefaultDependencyFailurePolicy is the default dependency failure policy.
+const DefaultDependencyFailurePolicy = DependencyFailurePolicySkip
+
 const (
 	// DefaultDisabled is a default.
 	DefaultDisabled = false
@@ -46,6 +69,61 @@ const (
 	FlagEnabled = "cron.enabled"
 	// FlagDisabled is an event flag.
 	FlagDisabled = "cron.disabled"
+	// FlagLockHeld is an event flag.
+	FlagLockHeld = "cron.lock_held"
+	// FlagLockLost is an event flag.
+	FlagLockLost = "cron.lock_lost"
+	// FlagDependencySkipped is an event flag.
+	FlagDependencySkipped = "cron.dependency_skipped"
+	// FlagMisfire is an event flag.
+	FlagMisfire = "cron.misfire"
+	// FlagSkipped is an event flag.
+	FlagSkipped = "cron.skipped"
+	// FlagQueued is an event flag.
+	FlagQueued = "cron.queued"
+	// FlagReplaced is an event flag.
+	FlagReplaced = "cron.replaced"
+	// FlagRetry is an event flag.
+	FlagRetry = "cron.retry"
+)
+
+// OverlapPolicy determines what happens when a scheduled tick fires while the job is still running.
+type OverlapPolicy string
+
+// OverlapPolicy values.
+const (
+	// OverlapPolicySkip skips the tick.
+	OverlapPolicySkip OverlapPolicy = "skip"
+	// OverlapPolicyQueue runs the tick once the running invocation completes.
+	// At most one tick is queued; ticks that fire while one is queued are skipped.
+	OverlapPolicyQueue OverlapPolicy = "queue"
+	// OverlapPolicyReplace cancels the running invocation and runs the tick once it completes.
+	OverlapPolicyReplace OverlapPolicy = "replace"
+)
+
+// MisfirePolicy determines what happens to the scheduled ticks a job missed
+// while no scheduler was running it.
+type MisfirePolicy string
+
+// MisfirePolicy values.
+const (
+	// MisfirePolicySkip drops missed ticks.
+	MisfirePolicySkip MisfirePolicy = "skip"
+	// MisfirePolicyRunOnce runs the job once for the most recent missed tick.
+	MisfirePolicyRunOnce MisfirePolicy = "run_once"
+	// MisfirePolicyRunAll runs the job for each of the most recent missed ticks, up to a cap, oldest first.
+	MisfirePolicyRunAll MisfirePolicy = "run_all"
+)
+
+// DependencyFailurePolicy determines what happens to a job when one of its dependencies fails.
+type DependencyFailurePolicy string
+
+// DependencyFailurePolicy values.
+const (
+	// DependencyFailurePolicySkip skips the job if any of its dependencies did not succeed.
+	DependencyFailurePolicySkip DependencyFailurePolicy = "skip"
+	// DependencyFailurePolicyRun runs the job once its dependencies complete, whether or not they succeeded.
+	DependencyFailurePolicyRun DependencyFailurePolicy = "run"
 )
 
 // JobManagerState is a job manager status.
@@ -78,4 +156,6 @@ const (
 	JobInvocationStatusCanceled JobInvocationStatus = "canceled"
 	JobInvocationStatusErrored  JobInvocationStatus = "errored"
 	JobInvocationStatusSuccess  JobInvocationStatus = "success"
+	// JobInvocationStatusSkipped is reported to dependent jobs for a job skipped because a dependency failed.
+	JobInvocationStatusSkipped JobInvocationStatus = "skipped"
 )
@@ -0,0 +1,236 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// Interface assertions.
+var (
+	_ cron.Locker = (*AdvisoryLocker)(nil)
+	_ cron.Lease  = (*AdvisoryLease)(nil)
+)
+
+// NewAdvisoryLocker returns a new advisory locker.
+func NewAdvisoryLocker(conn *db.Connection, opts ...AdvisoryLockerOption) *AdvisoryLocker {
+	al := &AdvisoryLocker{
+		Conn: conn,
+	}
+	for _, opt := range opts {
+		opt(al)
+	}
+	return al
+}
+
+// AdvisoryLockerOption is an option for advisory lockers.
+type AdvisoryLockerOption func(*AdvisoryLocker)
+
+// OptAdvisoryLockerCheckInterval sets the interval the session holding the locks is checked on.
+func OptAdvisoryLockerCheckInterval(d time.Duration) AdvisoryLockerOption {
+	return func(al *AdvisoryLocker) { al.CheckInterval = d }
+}
+
+// AdvisoryLocker is a cron locker that uses postgres session level advisory locks.
+//
+// Locks are held on a single connection (the session) taken from the connection pool
+// while any lock is held. If the session fails a check, the server has released
+// its locks, and every lease acquired on it is lost.
+type AdvisoryLocker struct {
+	Conn          *db.Connection
+	CheckInterval time.Duration
+
+	mu      sync.Mutex
+	session *advisorySession
+}
+
+// CheckIntervalOrDefault returns the check interval or a default.
+func (al *AdvisoryLocker) CheckIntervalOrDefault() time.Duration {
+	if al.CheckInterval > 0 {
+		return al.CheckInterval
+	}
+	return DefaultAdvisoryLockerCheckInterval
+}
+
+// Lock implements cron.Locker.
+func (al *AdvisoryLocker) Lock(ctx context.Context, key string) (cron.Lease, error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	session, err := al.sessionLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockID := AdvisoryLockID(key)
+	var acquired bool
+	if _, err = al.invoke(ctx, session).Query("SELECT pg_try_advisory_lock($1)", lockID).Scan(&acquired); err != nil {
+		al.closeSessionLocked(true)
+		return nil, err
+	}
+	if !acquired {
+		if session.leases == 0 {
+			al.closeSessionLocked(false)
+		}
+		return nil, ex.New(cron.ErrJobLockHeld, ex.OptMessagef("key: %s", key))
+	}
+	session.leases++
+	return &AdvisoryLease{
+		Key:     key,
+		LockID:  lockID,
+		locker:  al,
+		session: session,
+	}, nil
+}
+
+// sessionLocked returns the current session, opening one if there isn't one.
+// It assumes the locker mutex is held.
+func (al *AdvisoryLocker) sessionLocked(ctx context.Context) (*advisorySession, error) {
+	if al.session != nil {
+		return al.session, nil
+	}
+	if al.Conn == nil || al.Conn.Connection == nil {
+		return nil, ex.New(db.ErrConnectionClosed)
+	}
+	conn, err := al.Conn.Connection.Conn(ctx)
+	if err != nil {
+		return nil, db.Error(err)
+	}
+	al.session = &advisorySession{
+		conn: conn,
+		lost: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go al.check(al.session)
+	return al.session, nil
+}
+
+// check periodically checks a session is still alive until it is closed.
+func (al *AdvisoryLocker) check(session *advisorySession) {
+	ticker := time.NewTicker(al.CheckIntervalOrDefault())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-session.done:
+			return
+		case <-ticker.C:
+		}
+
+		al.mu.Lock()
+		if al.session != session {
+			al.mu.Unlock()
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), al.CheckIntervalOrDefault())
+		if _, err := al.invoke(ctx, session).Exec("SELECT 1"); err != nil {
+			al.closeSessionLocked(true)
+		}
+		cancel()
+		al.mu.Unlock()
+	}
+}
+
+// closeSessionLocked closes the current session, marking its leases as lost if the session was lost.
+// It assumes the locker mutex is held.
+func (al *AdvisoryLocker) closeSessionLocked(lost bool) {
+	session := al.session
+	if session == nil {
+		return
+	}
+	al.session = nil
+	close(session.done)
+	if lost {
+		close(session.lost)
+		// discard the connection rather than returning it to the pool
+		_ = session.conn.Raw(func(_ interface{}) error { return driver.ErrBadConn })
+		_ = session.conn.Close()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), al.CheckIntervalOrDefault())
+	defer cancel()
+	if _, err := al.invoke(ctx, session).Exec("SELECT pg_advisory_unlock_all()"); err != nil {
+		_ = session.conn.Raw(func(_ interface{}) error { return driver.ErrBadConn })
+	}
+	_ = session.conn.Close()
+}
+
+func (al *AdvisoryLocker) invoke(ctx context.Context, session *advisorySession) *db.Invocation {
+	return al.Conn.Invoke(
+		db.OptContext(ctx),
+		db.OptInvocationDB(session.conn),
+		db.OptLabel("cron_advisory_lock"),
+	)
+}
+
+// advisorySession is the connection advisory locks are held on.
+type advisorySession struct {
+	conn   *sql.Conn
+	leases int
+	lost   chan struct{}
+	done   chan struct{}
+}
+
+// AdvisoryLease is a lock acquired from an AdvisoryLocker.
+type AdvisoryLease struct {
+	Key    string
+	LockID int64
+
+	locker   *AdvisoryLocker
+	session  *advisorySession
+	released bool
+}
+
+// Lost implements cron.Lease.
+func (al *AdvisoryLease) Lost() <-chan struct{} {
+	return al.session.lost
+}
+
+// Release implements cron.Lease.
+func (al *AdvisoryLease) Release(ctx context.Context) error {
+	al.locker.mu.Lock()
+	defer al.locker.mu.Unlock()
+
+	if al.released {
+		return nil
+	}
+	al.released = true
+
+	// if the session was lost the server has already released the lock.
+	if al.locker.session != al.session {
+		return nil
+	}
+
+	var released bool
+	_, err := al.locker.invoke(ctx, al.session).Query("SELECT pg_advisory_unlock($1)", al.LockID).Scan(&released)
+	if err != nil {
+		al.locker.closeSessionLocked(true)
+		return err
+	}
+	al.session.leases--
+	if al.session.leases == 0 {
+		al.locker.closeSessionLocked(false)
+	}
+	return nil
+}
+
+// AdvisoryLockID returns the advisory lock identifier for a given key.
+func AdvisoryLockID(key string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	return int64(hash.Sum64())
+}
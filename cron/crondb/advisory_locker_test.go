@@ -0,0 +1,86 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/uuid"
+)
+
+func Test_AdvisoryLocker(t *testing.T) {
+	its := assert.New(t)
+
+	ctx := context.Background()
+	key := cron.LockKey(uuid.V4().String(), time.Now())
+
+	replica0 := NewAdvisoryLocker(defaultDB())
+	replica1 := NewAdvisoryLocker(defaultDB())
+
+	lease, err := replica0.Lock(ctx, key)
+	its.Nil(err)
+	its.NotNil(lease)
+
+	held, err := replica1.Lock(ctx, key)
+	its.True(cron.IsJobLockHeld(err))
+	its.Nil(held)
+
+	its.Nil(lease.Release(ctx))
+	its.Nil(lease.Release(ctx), "releasing twice should be a no-op")
+
+	lease, err = replica1.Lock(ctx, key)
+	its.Nil(err)
+	its.NotNil(lease)
+	its.Nil(lease.Release(ctx))
+
+	replica0.mu.Lock()
+	its.Nil(replica0.session, "the session should be closed once no locks are held")
+	replica0.mu.Unlock()
+}
+
+func Test_AdvisoryLocker_lost(t *testing.T) {
+	its := assert.New(t)
+
+	ctx := context.Background()
+	key := cron.LockKey(uuid.V4().String(), time.Now())
+
+	locker := NewAdvisoryLocker(defaultDB(), OptAdvisoryLockerCheckInterval(10*time.Millisecond))
+	lease, err := locker.Lock(ctx, key)
+	its.Nil(err)
+
+	// kill the session out from under the locker
+	var pid int
+	locker.mu.Lock()
+	_, err = locker.invoke(ctx, locker.session).Query("SELECT pg_backend_pid()").Scan(&pid)
+	locker.mu.Unlock()
+	its.Nil(err)
+	_, err = defaultDB().ExecContext(ctx, "SELECT pg_terminate_backend($1)", pid)
+	its.Nil(err)
+
+	select {
+	case <-lease.Lost():
+	case <-time.After(5 * time.Second):
+		its.FailNow("lease should be lost")
+	}
+	its.Nil(lease.Release(ctx))
+
+	lease, err = NewAdvisoryLocker(defaultDB()).Lock(ctx, key)
+	its.Nil(err)
+	its.Nil(lease.Release(ctx))
+}
+
+func Test_AdvisoryLockID(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal(AdvisoryLockID("test-job@1"), AdvisoryLockID("test-job@1"))
+	its.NotEqual(AdvisoryLockID("test-job@1"), AdvisoryLockID("test-job@2"))
+}
@@ -0,0 +1,21 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import "time"
+
+// Defaults
+const (
+	DefaultAdvisoryLockerCheckInterval = 5 * time.Second
+)
+
+// HistoryTableName is the table job invocations are stored in by `History`.
+const HistoryTableName = "cron_job_invocation_history"
+
+// LastFiredTableName is the table last fired ticks are stored in by `LastFiredStore`.
+const LastFiredTableName = "cron_job_last_fired"
@@ -0,0 +1,11 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+/*
+Package crondb contains database backed implementations of cron extension points.
+*/
+package crondb // import "github.com/zpkg/blend-go-sdk/cron/crondb"
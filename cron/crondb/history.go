@@ -0,0 +1,188 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// Interface assertions.
+var (
+	_ cron.HistoryProvider = (*History)(nil)
+	_ db.TableNameProvider = (*HistoryEntry)(nil)
+)
+
+// NewHistory returns a new database backed history provider.
+func NewHistory(conn *db.Connection) *History {
+	return &History{
+		Conn: conn,
+	}
+}
+
+// History is a cron history provider that stores invocations in a postgres table.
+//
+// Call `Initialize` once before use to create the table if it does not exist.
+type History struct {
+	Conn *db.Connection
+}
+
+// Initialize creates the history table and its indexes if they do not exist.
+func (h *History) Initialize(ctx context.Context) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id text NOT NULL PRIMARY KEY,
+			job_name text NOT NULL,
+			started_utc timestamp NOT NULL,
+			complete_utc timestamp,
+			status text NOT NULL,
+			error text,
+			parameters jsonb,
+			attempt integer NOT NULL DEFAULT 1
+		)`, HistoryTableName),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS ix_%s_job_name_started_utc ON %s (job_name, started_utc DESC)`, HistoryTableName, HistoryTableName),
+	}
+	for _, statement := range statements {
+		if _, err := h.invoke(ctx).Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record implements cron.HistoryProvider.
+func (h *History) Record(ctx context.Context, ji cron.JobInvocation) error {
+	return h.invoke(ctx, db.OptLabel("cron_history_record")).Upsert(NewHistoryEntry(ji))
+}
+
+// Last implements cron.HistoryProvider.
+//
+// It reads from the primary so that invocations recorded by this node are always returned.
+func (h *History) Last(ctx context.Context, jobName string, count int) ([]cron.JobInvocation, error) {
+	var entries []HistoryEntry
+	err := h.invoke(ctx, db.OptPrimary(), db.OptLabel("cron_history_last")).Query(
+		fmt.Sprintf("SELECT %s FROM %s WHERE job_name = $1 ORDER BY started_utc DESC LIMIT $2", db.ColumnNamesCSV(HistoryEntry{}), HistoryTableName),
+		jobName, count,
+	).OutMany(&entries)
+	if err != nil {
+		return nil, err
+	}
+	return historyEntriesToInvocations(entries), nil
+}
+
+// FailuresSince implements cron.HistoryProvider.
+//
+// It reads from the primary so that invocations recorded by this node are always returned.
+func (h *History) FailuresSince(ctx context.Context, jobName string, since time.Time) ([]cron.JobInvocation, error) {
+	var entries []HistoryEntry
+	err := h.invoke(ctx, db.OptPrimary(), db.OptLabel("cron_history_failures_since")).Query(
+		fmt.Sprintf("SELECT %s FROM %s WHERE job_name = $1 AND status = $2 AND started_utc >= $3 ORDER BY started_utc DESC", db.ColumnNamesCSV(HistoryEntry{}), HistoryTableName),
+		jobName, string(cron.JobInvocationStatusErrored), since.UTC(),
+	).OutMany(&entries)
+	if err != nil {
+		return nil, err
+	}
+	return historyEntriesToInvocations(entries), nil
+}
+
+// Cull implements cron.HistoryProvider.
+func (h *History) Cull(ctx context.Context, jobName string, maxCount int, maxAge time.Duration) error {
+	if maxAge > 0 {
+		_, err := h.invoke(ctx, db.OptLabel("cron_history_cull_age")).Exec(
+			fmt.Sprintf("DELETE FROM %s WHERE job_name = $1 AND started_utc < $2", HistoryTableName),
+			jobName, cron.Now().Add(-maxAge).UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if maxCount > 0 {
+		_, err := h.invoke(ctx, db.OptLabel("cron_history_cull_count")).Exec(
+			fmt.Sprintf("DELETE FROM %s WHERE job_name = $1 AND id NOT IN (SELECT id FROM %s WHERE job_name = $1 ORDER BY started_utc DESC LIMIT $2)", HistoryTableName, HistoryTableName),
+			jobName, maxCount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *History) invoke(ctx context.Context, opts ...db.InvocationOption) *db.Invocation {
+	return h.Conn.Invoke(append([]db.InvocationOption{db.OptContext(ctx)}, opts...)...)
+}
+
+func historyEntriesToInvocations(entries []HistoryEntry) []cron.JobInvocation {
+	output := make([]cron.JobInvocation, 0, len(entries))
+	for _, entry := range entries {
+		output = append(output, entry.JobInvocation())
+	}
+	return output
+}
+
+// NewHistoryEntry returns a new history entry for a job invocation.
+func NewHistoryEntry(ji cron.JobInvocation) *HistoryEntry {
+	entry := &HistoryEntry{
+		ID:         ji.ID,
+		JobName:    ji.JobName,
+		StartedUTC: ji.Started.UTC(),
+		Status:     string(ji.Status),
+		Parameters: ji.Parameters,
+		Attempt:    ji.Attempt,
+	}
+	if !ji.Complete.IsZero() {
+		completeUTC := ji.Complete.UTC()
+		entry.CompleteUTC = &completeUTC
+	}
+	if ji.Err != nil {
+		errString := ji.Err.Error()
+		entry.Error = &errString
+	}
+	return entry
+}
+
+// HistoryEntry is a job invocation as stored in the history table.
+type HistoryEntry struct {
+	ID          string             `db:"id,pk"`
+	JobName     string             `db:"job_name"`
+	StartedUTC  time.Time          `db:"started_utc"`
+	CompleteUTC *time.Time         `db:"complete_utc"`
+	Status      string             `db:"status"`
+	Error       *string            `db:"error"`
+	Parameters  cron.JobParameters `db:"parameters,json"`
+	Attempt     int                `db:"attempt"`
+}
+
+// TableName implements db.TableNameProvider.
+func (HistoryEntry) TableName() string { return HistoryTableName }
+
+// JobInvocation returns the job invocation for the entry.
+//
+// Errors are restored as plain errors with the original message.
+func (he HistoryEntry) JobInvocation() cron.JobInvocation {
+	ji := cron.JobInvocation{
+		ID:         he.ID,
+		JobName:    he.JobName,
+		Started:    he.StartedUTC.UTC(),
+		Status:     cron.JobInvocationStatus(he.Status),
+		Parameters: he.Parameters,
+		Attempt:    he.Attempt,
+	}
+	if he.CompleteUTC != nil {
+		ji.Complete = he.CompleteUTC.UTC()
+	}
+	if he.Error != nil {
+		ji.Err = ex.New(*he.Error)
+	}
+	return ji
+}
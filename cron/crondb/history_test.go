@@ -0,0 +1,65 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/uuid"
+)
+
+func Test_History(t *testing.T) {
+	its := assert.New(t)
+
+	ctx := context.Background()
+	history := NewHistory(defaultDB())
+	its.Nil(history.Initialize(ctx))
+
+	jobName := uuid.V4().String()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for x := 0; x < 5; x++ {
+		ji := cron.JobInvocation{
+			ID:         uuid.V4().String(),
+			JobName:    jobName,
+			Started:    now.Add(time.Duration(x-5) * time.Hour),
+			Complete:   now.Add(time.Duration(x-5)*time.Hour + time.Minute),
+			Status:     cron.JobInvocationStatusSuccess,
+			Parameters: cron.JobParameters{"index": fmt.Sprint(x)},
+		}
+		if x%2 == 0 {
+			ji.Status = cron.JobInvocationStatusErrored
+			ji.Err = fmt.Errorf("failure %d", x)
+		}
+		its.Nil(history.Record(ctx, ji))
+	}
+
+	last, err := history.Last(ctx, jobName, 2)
+	its.Nil(err)
+	its.Len(last, 2)
+	its.Equal("4", last[0].Parameters["index"])
+	its.Equal("3", last[1].Parameters["index"])
+	its.Equal(now.Add(-time.Hour), last[0].Started)
+	its.Equal("failure 4", last[0].Err.Error())
+	its.Nil(last[1].Err)
+
+	failures, err := history.FailuresSince(ctx, jobName, now.Add(-4*time.Hour))
+	its.Nil(err)
+	its.Len(failures, 2)
+	its.Equal("4", failures[0].Parameters["index"])
+	its.Equal("2", failures[1].Parameters["index"])
+
+	its.Nil(history.Cull(ctx, jobName, 4, 150*time.Minute))
+	last, err = history.Last(ctx, jobName, 10)
+	its.Nil(err)
+	its.Len(last, 2)
+}
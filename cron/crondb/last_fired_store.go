@@ -0,0 +1,77 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/db"
+)
+
+// Interface assertions.
+var (
+	_ cron.LastFiredStore = (*LastFiredStore)(nil)
+)
+
+// NewLastFiredStore returns a new database backed last fired store.
+func NewLastFiredStore(conn *db.Connection) *LastFiredStore {
+	return &LastFiredStore{
+		Conn: conn,
+	}
+}
+
+// LastFiredStore is a cron last fired store that stores ticks in a postgres table.
+//
+// Call `Initialize` once before use to create the table if it does not exist.
+type LastFiredStore struct {
+	Conn *db.Connection
+}
+
+// Initialize creates the last fired table if it does not exist.
+func (lfs *LastFiredStore) Initialize(ctx context.Context) error {
+	_, err := lfs.Conn.Invoke(db.OptContext(ctx)).Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		job_name text NOT NULL PRIMARY KEY,
+		last_fired_utc timestamp NOT NULL
+	)`, LastFiredTableName))
+	return err
+}
+
+// LastFired implements cron.LastFiredStore.
+//
+// It reads from the primary, as a lagging read replica could return a tick that another node has since fired past.
+func (lfs *LastFiredStore) LastFired(ctx context.Context, jobName string) (lastFired time.Time, err error) {
+	var value sql.NullTime
+	_, err = lfs.Conn.Invoke(db.OptContext(ctx), db.OptPrimary(), db.OptLabel("cron_last_fired")).Query(
+		fmt.Sprintf("SELECT last_fired_utc FROM %s WHERE job_name = $1", LastFiredTableName),
+		jobName,
+	).Scan(&value)
+	if err != nil {
+		return
+	}
+	if value.Valid {
+		lastFired = value.Time.UTC()
+	}
+	return
+}
+
+// SetLastFired implements cron.LastFiredStore.
+//
+// The stored tick only moves forward, so that replicas firing the same
+// schedule out of order cannot move it back.
+func (lfs *LastFiredStore) SetLastFired(ctx context.Context, jobName string, tick time.Time) error {
+	_, err := lfs.Conn.Invoke(db.OptContext(ctx), db.OptLabel("cron_set_last_fired")).Exec(
+		fmt.Sprintf(`INSERT INTO %[1]s (job_name, last_fired_utc) VALUES ($1, $2)
+		ON CONFLICT (job_name) DO UPDATE SET last_fired_utc = GREATEST(%[1]s.last_fired_utc, EXCLUDED.last_fired_utc)`, LastFiredTableName),
+		jobName, tick.UTC(),
+	)
+	return err
+}
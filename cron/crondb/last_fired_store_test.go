@@ -0,0 +1,38 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package crondb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/uuid"
+)
+
+func Test_LastFiredStore(t *testing.T) {
+	its := assert.New(t)
+
+	ctx := context.Background()
+	store := NewLastFiredStore(defaultDB())
+	its.Nil(store.Initialize(ctx))
+
+	jobName := uuid.V4().String()
+	lastFired, err := store.LastFired(ctx, jobName)
+	its.Nil(err)
+	its.True(lastFired.IsZero())
+
+	tick := time.Date(2022, 01, 02, 03, 04, 05, 0, time.UTC)
+	its.Nil(store.SetLastFired(ctx, jobName, tick))
+	its.Nil(store.SetLastFired(ctx, jobName, tick.Add(-time.Hour)), "an earlier tick should not move the last fired tick back")
+
+	lastFired, err = store.LastFired(ctx, jobName)
+	its.Nil(err)
+	its.Equal(tick, lastFired)
+}
@@ -0,0 +1,16 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cronweb
+
+// Defaults
+const (
+	DefaultPathPrefix = "/cron"
+)
+
+// RouteParameterJobName is the route parameter that holds the job name.
+const RouteParameterJobName = "jobName"
@@ -0,0 +1,159 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cronweb
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/web"
+)
+
+// Interface assertions.
+var (
+	_ web.Controller = (*Controller)(nil)
+)
+
+// NewController returns a new controller for a given job manager.
+func NewController(jm *cron.JobManager, opts ...ControllerOption) *Controller {
+	controller := Controller{
+		JobManager: jm,
+		PathPrefix: DefaultPathPrefix,
+	}
+	for _, opt := range opts {
+		opt(&controller)
+	}
+	return &controller
+}
+
+// ControllerOption mutates a controller.
+type ControllerOption func(c *Controller)
+
+// OptPathPrefix returns an option that sets the path prefix the routes are registered under.
+func OptPathPrefix(pathPrefix string) ControllerOption {
+	return func(c *Controller) {
+		c.PathPrefix = pathPrefix
+	}
+}
+
+// OptMiddleware adds middleware for the controller routes, e.g. to authorize requests.
+//
+// Middleware must be set _before_ you register the controller.
+func OptMiddleware(middleware ...web.Middleware) ControllerOption {
+	return func(c *Controller) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// Controller exposes a job manager over JSON endpoints.
+//
+// It will register the following routes under the path prefix (`/cron` by default):
+//
+//	GET  /cron                        the job manager status and the status of each job
+//	GET  /cron/jobs/:jobName          the status of a job
+//	POST /cron/jobs/:jobName/run      runs a job on demand
+//	POST /cron/jobs/:jobName/cancel   cancels a running job
+//	POST /cron/jobs/:jobName/enable   enables a job
+//	POST /cron/jobs/:jobName/disable  disables a job
+type Controller struct {
+	JobManager *cron.JobManager
+	PathPrefix string
+	Middleware []web.Middleware
+}
+
+// Register adds the controller's routes to the app.
+func (c Controller) Register(app *web.App) {
+	app.GET(c.PathPrefix, c.getStatus, c.Middleware...)
+	app.GET(c.PathPrefix+"/jobs/:jobName", c.getJob, c.Middleware...)
+	app.POST(c.PathPrefix+"/jobs/:jobName/run", c.postJobRun, c.Middleware...)
+	app.POST(c.PathPrefix+"/jobs/:jobName/cancel", c.postJobCancel, c.Middleware...)
+	app.POST(c.PathPrefix+"/jobs/:jobName/enable", c.postJobEnable, c.Middleware...)
+	app.POST(c.PathPrefix+"/jobs/:jobName/disable", c.postJobDisable, c.Middleware...)
+}
+
+// GET /cron
+func (c Controller) getStatus(r *web.Ctx) web.Result {
+	c.JobManager.Lock()
+	output := JobManagerStatus{
+		State:   c.JobManager.State(),
+		Started: c.JobManager.Started,
+		Stopped: c.JobManager.Stopped,
+	}
+	jobSchedulers := make([]*cron.JobScheduler, 0, len(c.JobManager.Jobs))
+	for _, js := range c.JobManager.Jobs {
+		jobSchedulers = append(jobSchedulers, js)
+	}
+	c.JobManager.Unlock()
+
+	sort.Sort(cron.JobSchedulersByJobNameAsc(jobSchedulers))
+	for _, js := range jobSchedulers {
+		output.Jobs = append(output.Jobs, NewJobStatus(js))
+	}
+	return web.JSON.Result(output)
+}
+
+// GET /cron/jobs/:jobName
+func (c Controller) getJob(r *web.Ctx) web.Result {
+	js, err := c.JobManager.Job(r.RouteParams.Get(RouteParameterJobName))
+	if err != nil {
+		return c.errorResult(err)
+	}
+	return web.JSON.Result(NewJobStatus(js))
+}
+
+// POST /cron/jobs/:jobName/run
+func (c Controller) postJobRun(r *web.Ctx) web.Result {
+	ji, _, err := c.JobManager.RunJob(r.RouteParams.Get(RouteParameterJobName))
+	if err != nil {
+		return c.errorResult(err)
+	}
+	// the invocation is updated by the job as it runs, so
+	// only the fields that are fixed at creation are read here.
+	return web.JSON.Status(http.StatusAccepted, JobInvocationStatus{
+		ID:         ji.ID,
+		JobName:    ji.JobName,
+		Parameters: ji.Parameters,
+	})
+}
+
+// POST /cron/jobs/:jobName/cancel
+func (c Controller) postJobCancel(r *web.Ctx) web.Result {
+	if err := c.JobManager.CancelJob(r.RouteParams.Get(RouteParameterJobName)); err != nil {
+		return c.errorResult(err)
+	}
+	return web.JSON.OK()
+}
+
+// POST /cron/jobs/:jobName/enable
+func (c Controller) postJobEnable(r *web.Ctx) web.Result {
+	if err := c.JobManager.EnableJobs(r.RouteParams.Get(RouteParameterJobName)); err != nil {
+		return c.errorResult(err)
+	}
+	return web.JSON.OK()
+}
+
+// POST /cron/jobs/:jobName/disable
+func (c Controller) postJobDisable(r *web.Ctx) web.Result {
+	if err := c.JobManager.DisableJobs(r.RouteParams.Get(RouteParameterJobName)); err != nil {
+		return c.errorResult(err)
+	}
+	return web.JSON.OK()
+}
+
+// errorResult maps job manager errors to results.
+func (c Controller) errorResult(err error) web.Result {
+	switch {
+	case cron.IsJobNotLoaded(err), cron.IsJobNotFound(err):
+		return web.JSON.NotFound()
+	case cron.IsJobAlreadyRunning(err):
+		return web.JSON.Status(http.StatusConflict, err.Error())
+	default:
+		return web.JSON.InternalError(err)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cronweb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/cron"
+	"github.com/zpkg/blend-go-sdk/web"
+)
+
+func newTestApp(its *assert.Assertions, opts ...ControllerOption) (*web.App, *cron.JobManager, chan struct{}) {
+	proceed := make(chan struct{})
+	jm := cron.New()
+	its.Nil(jm.LoadJobs(
+		cron.NewJob(
+			cron.OptJobName("blocking"),
+			cron.OptJobAction(func(ctx context.Context) error {
+				select {
+				case <-proceed:
+					return fmt.Errorf("this is only a test")
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}),
+		),
+		cron.NewJob(
+			cron.OptJobName("scheduled"),
+			cron.OptJobSchedule(cron.Every(time.Hour)),
+			cron.OptJobAction(func(_ context.Context) error { return nil }),
+		),
+	))
+	app := web.MustNew()
+	app.Register(NewController(jm, opts...))
+	return app, jm, proceed
+}
+
+func Test_Controller_getStatus(t *testing.T) {
+	its := assert.New(t)
+
+	app, _, _ := newTestApp(its)
+
+	var output JobManagerStatus
+	meta, err := web.MockGet(app, "/cron").JSON(&output)
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	its.Len(output.Jobs, 2)
+	its.Equal("blocking", output.Jobs[0].Name)
+	its.Equal("scheduled", output.Jobs[1].Name)
+	its.NotEmpty(output.Jobs[1].Schedule)
+}
+
+func Test_Controller_runJob(t *testing.T) {
+	its := assert.New(t)
+
+	app, jm, proceed := newTestApp(its)
+
+	var invocation JobInvocationStatus
+	meta, err := web.MockPost(app, "/cron/jobs/blocking/run", nil).JSON(&invocation)
+	its.Nil(err)
+	its.Equal(http.StatusAccepted, meta.StatusCode)
+	its.Equal("blocking", invocation.JobName)
+	its.NotEmpty(invocation.ID)
+
+	var job JobStatus
+	meta, err = web.MockGet(app, "/cron/jobs/blocking").JSON(&job)
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	its.True(job.Running)
+	its.NotNil(job.Current)
+	its.Equal(invocation.ID, job.Current.ID)
+
+	meta, err = web.MockPost(app, "/cron/jobs/blocking/run", nil).Discard()
+	its.Nil(err)
+	its.Equal(http.StatusConflict, meta.StatusCode)
+
+	close(proceed)
+	for jm.IsJobRunning("blocking") {
+		time.Sleep(time.Millisecond)
+	}
+
+	var complete JobStatus
+	meta, err = web.MockGet(app, "/cron/jobs/blocking").JSON(&complete)
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	its.False(complete.Running)
+	its.Nil(complete.Current)
+	its.NotNil(complete.Last)
+	its.Equal(invocation.ID, complete.Last.ID)
+	its.Equal(cron.JobInvocationStatusErrored, complete.Last.Status)
+	its.Equal("this is only a test", complete.Last.Err)
+}
+
+func Test_Controller_cancelJob(t *testing.T) {
+	its := assert.New(t)
+
+	app, jm, _ := newTestApp(its)
+
+	meta, err := web.MockPost(app, "/cron/jobs/blocking/run", nil).Discard()
+	its.Nil(err)
+	its.Equal(http.StatusAccepted, meta.StatusCode)
+
+	meta, err = web.MockPost(app, "/cron/jobs/blocking/cancel", nil).Discard()
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	for jm.IsJobRunning("blocking") {
+		time.Sleep(time.Millisecond)
+	}
+
+	js, err := jm.Job("blocking")
+	its.Nil(err)
+	its.Equal(cron.JobInvocationStatusCanceled, js.Last().Status)
+}
+
+func Test_Controller_enableDisableJob(t *testing.T) {
+	its := assert.New(t)
+
+	app, jm, _ := newTestApp(its)
+
+	meta, err := web.MockPost(app, "/cron/jobs/scheduled/disable", nil).Discard()
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	its.True(jm.IsJobDisabled("scheduled"))
+
+	meta, err = web.MockPost(app, "/cron/jobs/scheduled/enable", nil).Discard()
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	its.False(jm.IsJobDisabled("scheduled"))
+}
+
+func Test_Controller_notFound(t *testing.T) {
+	its := assert.New(t)
+
+	app, _, _ := newTestApp(its)
+
+	for _, path := range []string{"/cron/jobs/missing/run", "/cron/jobs/missing/cancel", "/cron/jobs/missing/enable", "/cron/jobs/missing/disable"} {
+		meta, err := web.MockPost(app, path, nil).Discard()
+		its.Nil(err)
+		its.Equal(http.StatusNotFound, meta.StatusCode, path)
+	}
+	meta, err := web.MockGet(app, "/cron/jobs/missing").Discard()
+	its.Nil(err)
+	its.Equal(http.StatusNotFound, meta.StatusCode)
+}
+
+func Test_Controller_middleware(t *testing.T) {
+	its := assert.New(t)
+
+	notAuthorized := func(action web.Action) web.Action {
+		return func(r *web.Ctx) web.Result {
+			if r.Request.Header.Get("X-Authorized") == "" {
+				return web.JSON.NotAuthorized()
+			}
+			return action(r)
+		}
+	}
+	app, jm, _ := newTestApp(its, OptPathPrefix("/admin/cron"), OptMiddleware(notAuthorized))
+
+	meta, err := web.MockPost(app, "/admin/cron/jobs/scheduled/disable", nil).Discard()
+	its.Nil(err)
+	its.Equal(http.StatusUnauthorized, meta.StatusCode)
+	its.False(jm.IsJobDisabled("scheduled"))
+
+	meta, err = web.MockGet(app, "/admin/cron").Discard()
+	its.Nil(err)
+	its.Equal(http.StatusUnauthorized, meta.StatusCode)
+}
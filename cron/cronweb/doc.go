@@ -0,0 +1,11 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+/*
+Package cronweb contains a web controller that exposes a cron job manager over JSON endpoints.
+*/
+package cronweb // import "github.com/zpkg/blend-go-sdk/cron/cronweb"
@@ -0,0 +1,95 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cronweb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/cron"
+)
+
+// JobManagerStatus is the status of a job manager.
+type JobManagerStatus struct {
+	State   cron.JobManagerState `json:"state"`
+	Started time.Time            `json:"started"`
+	Stopped time.Time            `json:"stopped"`
+	Jobs    []JobStatus          `json:"jobs"`
+}
+
+// NewJobStatus returns the status of a job scheduler.
+func NewJobStatus(js *cron.JobScheduler) JobStatus {
+	output := JobStatus{
+		Name:        js.Name(),
+		Description: js.Description(),
+		Labels:      js.Labels(),
+		State:       js.State(),
+		Disabled:    js.Disabled(),
+		Running:     !js.IsIdle(),
+		NextRuntime: js.NextRuntimeUTC(),
+	}
+	if js.JobSchedule != nil {
+		if typed, ok := js.JobSchedule.(fmt.Stringer); ok {
+			output.Schedule = typed.String()
+		}
+	}
+	if current := js.Current(); current != nil {
+		output.Current = NewJobInvocationStatus(current)
+	}
+	if last := js.Last(); last != nil {
+		output.Last = NewJobInvocationStatus(last)
+	}
+	return output
+}
+
+// JobStatus is the status of a job scheduler.
+type JobStatus struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Schedule    string                 `json:"schedule,omitempty"`
+	State       cron.JobSchedulerState `json:"state"`
+	Disabled    bool                   `json:"disabled"`
+	Running     bool                   `json:"running"`
+	NextRuntime time.Time              `json:"nextRuntime"`
+	Current     *JobInvocationStatus   `json:"current,omitempty"`
+	Last        *JobInvocationStatus   `json:"last,omitempty"`
+}
+
+// NewJobInvocationStatus returns the status of a job invocation.
+func NewJobInvocationStatus(ji *cron.JobInvocation) *JobInvocationStatus {
+	output := &JobInvocationStatus{
+		ID:         ji.ID,
+		JobName:    ji.JobName,
+		Started:    ji.Started,
+		Complete:   ji.Complete,
+		Elapsed:    ji.Elapsed(),
+		Status:     ji.Status,
+		Attempt:    ji.Attempt,
+		Parameters: ji.Parameters,
+	}
+	if ji.Err != nil {
+		output.Err = ji.Err.Error()
+	}
+	return output
+}
+
+// JobInvocationStatus is the status of a job invocation.
+//
+// It differs from a job invocation in that the error is serialized as its message.
+type JobInvocationStatus struct {
+	ID         string                   `json:"id"`
+	JobName    string                   `json:"jobName"`
+	Started    time.Time                `json:"started"`
+	Complete   time.Time                `json:"complete"`
+	Elapsed    time.Duration            `json:"elapsed"`
+	Status     cron.JobInvocationStatus `json:"status"`
+	Attempt    int                      `json:"attempt,omitempty"`
+	Err        string                   `json:"err,omitempty"`
+	Parameters cron.JobParameters       `json:"parameters,omitempty"`
+}
@@ -44,6 +44,8 @@ func WeekendsAtUTC(hour, minute, second int) Schedule {
 }
 
 // DailySchedule is a schedule that fires every day that satisfies the DayOfWeekMask at the given TimeOfDayUTC.
+//
+// The time of day is in UTC unless the schedule is given times in another location, e.g. with `InLocation`.
 type DailySchedule struct {
 	DayOfWeekMask uint
 	TimeOfDayUTC  time.Time
@@ -69,11 +71,17 @@ func (ds DailySchedule) checkDayOfWeekMask(day time.Weekday) bool {
 }
 
 // Next implements Schedule.
+//
+// The time of day is compared with the wall clock of the location of the given time.
+// See `LocationSchedule` for how daylight saving time transitions are handled.
 func (ds DailySchedule) Next(after time.Time) time.Time {
 	if after.IsZero() {
-		after = Now()
+		after = Now().In(after.Location())
 	}
+	return nextWallClock(after, ds.nextWallClock)
+}
 
+func (ds DailySchedule) nextWallClock(after time.Time) time.Time {
 	todayInstance := time.Date(after.Year(), after.Month(), after.Day(), ds.TimeOfDayUTC.Hour(), ds.TimeOfDayUTC.Minute(), ds.TimeOfDayUTC.Second(), 0, time.UTC)
 	for day := 0; day < 8; day++ {
 		next := todayInstance.AddDate(0, 0, day) //the first run here it should be adding nothing, i.e. returning todayInstance ...
@@ -0,0 +1,146 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// validateDependencies returns an error if a job depends on a job
+// that is not loaded, or if the dependencies between jobs form a cycle.
+func validateDependencies(jobs map[string]*JobScheduler) error {
+	dependsOn := make(map[string][]string, len(jobs))
+	jobNames := make([]string, 0, len(jobs))
+	for jobName, jobScheduler := range jobs {
+		dependsOn[jobName] = jobScheduler.Config().DependsOn
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	for _, jobName := range jobNames {
+		for _, dependency := range dependsOn[jobName] {
+			if _, ok := jobs[dependency]; !ok {
+				return ex.New(ErrJobDependencyNotLoaded, ex.OptMessagef("job: %s, dependency: %s", jobName, dependency))
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(jobs))
+	var path []string
+	var visit func(string) error
+	visit = func(jobName string) error {
+		switch state[jobName] {
+		case visited:
+			return nil
+		case visiting:
+			for index := range path {
+				if path[index] == jobName {
+					return ex.New(ErrJobDependencyCycle, ex.OptMessage(strings.Join(append(path[index:], jobName), " -> ")))
+				}
+			}
+		}
+		state[jobName] = visiting
+		path = append(path, jobName)
+		for _, dependency := range dependsOn[jobName] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[jobName] = visited
+		return nil
+	}
+	for _, jobName := range jobNames {
+		if err := visit(jobName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onJobComplete records a completed invocation against the jobs that depend on its job,
+// and triggers (or skips) each of them once all of their dependencies have completed.
+func (jm *JobManager) onJobComplete(_ context.Context, ji *JobInvocation) {
+	var trigger, skip []*JobScheduler
+
+	jm.Lock()
+	for _, jobScheduler := range jm.Jobs {
+		config := jobScheduler.Config()
+		if !containsString(config.DependsOn, ji.JobName) {
+			continue
+		}
+		if jm.dependencyResults == nil {
+			jm.dependencyResults = make(map[string]map[string]JobInvocationStatus)
+		}
+		results, ok := jm.dependencyResults[jobScheduler.Name()]
+		if !ok {
+			results = make(map[string]JobInvocationStatus)
+			jm.dependencyResults[jobScheduler.Name()] = results
+		}
+		results[ji.JobName] = ji.Status
+		if !hasAllDependencies(results, config.DependsOn) {
+			continue
+		}
+		delete(jm.dependencyResults, jobScheduler.Name())
+
+		if allSucceeded(results) || config.DependencyFailurePolicyOrDefault() == DependencyFailurePolicyRun {
+			trigger = append(trigger, jobScheduler)
+		} else {
+			skip = append(skip, jobScheduler)
+		}
+	}
+	jm.Unlock()
+
+	for _, jobScheduler := range skip {
+		jobScheduler.onDependencySkipped(jobScheduler.withBaseContext(jobScheduler.Background()))
+	}
+	for _, jobScheduler := range trigger {
+		if jobScheduler.Disabled() {
+			continue
+		}
+		if _, _, err := jobScheduler.RunAsyncContext(jm.Background()); err != nil {
+			jm.error(err)
+		}
+	}
+}
+
+func hasAllDependencies(results map[string]JobInvocationStatus, dependsOn []string) bool {
+	for _, dependency := range dependsOn {
+		if _, ok := results[dependency]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func allSucceeded(results map[string]JobInvocationStatus) bool {
+	for _, status := range results {
+		if status != JobInvocationStatusSuccess {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
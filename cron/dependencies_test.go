@@ -0,0 +1,147 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+func Test_JobManager_LoadJobs_dependencyCycle(t *testing.T) {
+	its := assert.New(t)
+
+	jm := New()
+	err := jm.LoadJobs(
+		NewJob(OptJobName("a"), OptJobDependsOn("c")),
+		NewJob(OptJobName("b"), OptJobDependsOn("a")),
+		NewJob(OptJobName("c"), OptJobDependsOn("b")),
+	)
+	its.True(IsJobDependencyCycle(err))
+	its.Equal("a -> c -> b -> a", ex.ErrMessage(err))
+	its.Empty(jm.Jobs, "no jobs should be loaded if the dependencies are invalid")
+
+	err = jm.LoadJobs(NewJob(OptJobName("self"), OptJobDependsOn("self")))
+	its.True(IsJobDependencyCycle(err))
+}
+
+func Test_JobManager_LoadJobs_dependencyNotLoaded(t *testing.T) {
+	its := assert.New(t)
+
+	jm := New()
+	err := jm.LoadJobs(NewJob(OptJobName("b"), OptJobDependsOn("a")))
+	its.True(ex.Is(err, ErrJobDependencyNotLoaded))
+
+	its.Nil(jm.LoadJobs(NewJob(OptJobName("a"))))
+	its.Nil(jm.LoadJobs(NewJob(OptJobName("b"), OptJobDependsOn("a"))))
+}
+
+func Test_JobManager_dependencies(t *testing.T) {
+	its := assert.New(t)
+
+	ran := make(chan string, 8)
+	action := func(name string) JobBuilderOption {
+		return OptJobAction(func(_ context.Context) error {
+			ran <- name
+			return nil
+		})
+	}
+
+	jm := New()
+	its.Nil(jm.LoadJobs(
+		NewJob(OptJobName("a"), action("a")),
+		NewJob(OptJobName("b"), action("b"), OptJobDependsOn("a")),
+		NewJob(OptJobName("c"), action("c"), OptJobDependsOn("a", "b")),
+	))
+
+	_, _, err := jm.RunJob("a")
+	its.Nil(err)
+	its.Equal("a", receiveJobName(its, ran))
+	its.Equal("b", receiveJobName(its, ran))
+	its.Equal("c", receiveJobName(its, ran), "c should run once both a and b have completed")
+	its.Empty(ran)
+}
+
+func Test_JobManager_dependencies_failurePolicy(t *testing.T) {
+	its := assert.New(t)
+
+	ran := make(chan string, 8)
+	action := func(name string) JobBuilderOption {
+		return OptJobAction(func(_ context.Context) error {
+			ran <- name
+			return nil
+		})
+	}
+
+	jm := New()
+	its.Nil(jm.LoadJobs(
+		NewJob(OptJobName("a"), OptJobAction(func(_ context.Context) error {
+			ran <- "a"
+			return fmt.Errorf("this is only a test")
+		})),
+		NewJob(OptJobName("skipped"), action("skipped"), OptJobDependsOn("a")),
+		NewJob(OptJobName("run"), action("run"), OptJobDependsOn("a"), OptJobDependencyFailurePolicy(DependencyFailurePolicyRun)),
+	))
+
+	_, _, err := jm.RunJob("a")
+	its.Nil(err)
+	its.Equal("a", receiveJobName(its, ran))
+	its.Equal("run", receiveJobName(its, ran))
+
+	skipped, err := jm.Job("skipped")
+	its.Nil(err)
+	its.Nil(skipped.Last())
+	its.Empty(ran)
+}
+
+func Test_JobManager_dependencies_failurePolicyChain(t *testing.T) {
+	its := assert.New(t)
+
+	ran := make(chan string, 8)
+	action := func(name string) JobBuilderOption {
+		return OptJobAction(func(_ context.Context) error {
+			ran <- name
+			return nil
+		})
+	}
+
+	jm := New()
+	its.Nil(jm.LoadJobs(
+		NewJob(OptJobName("a"), OptJobAction(func(_ context.Context) error {
+			ran <- "a"
+			return fmt.Errorf("this is only a test")
+		})),
+		NewJob(OptJobName("b"), action("b"), OptJobDependsOn("a")),
+		NewJob(OptJobName("c"), action("c"), OptJobDependsOn("b"), OptJobDependencyFailurePolicy(DependencyFailurePolicyRun)),
+		NewJob(OptJobName("d"), action("d"), OptJobDependsOn("b")),
+	))
+
+	_, _, err := jm.RunJob("a")
+	its.Nil(err)
+	its.Equal("a", receiveJobName(its, ran))
+	its.Equal("c", receiveJobName(its, ran), "c should run once b is skipped")
+	its.Empty(ran)
+
+	jm.Lock()
+	its.Empty(jm.dependencyResults, "skipped jobs should not leave partial results")
+	jm.Unlock()
+}
+
+func receiveJobName(its *assert.Assertions, ran <-chan string) string {
+	select {
+	case name := <-ran:
+		return name
+	case <-time.After(5 * time.Second):
+		its.FailNow("timed out waiting for a job to run")
+		return ""
+	}
+}
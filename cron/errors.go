@@ -20,6 +20,18 @@ const (
 	ErrJobCanceled ex.Class = "job canceled"
 	// ErrJobAlreadyRunning is a common error.
 	ErrJobAlreadyRunning ex.Class = "job already running"
+	// ErrJobLockHeld is a common error.
+	ErrJobLockHeld ex.Class = "job lock held"
+	// ErrJobLockLost is a common error.
+	ErrJobLockLost ex.Class = "job lock lost"
+	// ErrJobTimeZoneInvalid is a common error.
+	ErrJobTimeZoneInvalid ex.Class = "job time zone invalid"
+	// ErrJobDependencyNotLoaded is a common error.
+	ErrJobDependencyNotLoaded ex.Class = "job dependency not loaded"
+	// ErrJobDependencyCycle is a common error.
+	ErrJobDependencyCycle ex.Class = "job dependency cycle"
+	// ErrJobConfigInvalid is a common error.
+	ErrJobConfigInvalid ex.Class = "job config invalid"
 )
 
 // IsJobNotLoaded returns if the error is a job not loaded error.
@@ -46,3 +58,13 @@ func IsJobCanceled(err error) bool {
 func IsJobAlreadyRunning(err error) bool {
 	return ex.Is(err, ErrJobAlreadyRunning)
 }
+
+// IsJobLockHeld returns if the error is a job lock held error.
+func IsJobLockHeld(err error) bool {
+	return ex.Is(err, ErrJobLockHeld)
+}
+
+// IsJobDependencyCycle returns if the error is a job dependency cycle error.
+func IsJobDependencyCycle(err error) bool {
+	return ex.Is(err, ErrJobDependencyCycle)
+}
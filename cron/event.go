@@ -59,6 +59,11 @@ func OptEventErr(err error) EventOption {
 	return func(e *Event) { e.Err = err }
 }
 
+// OptEventAttempt sets a field.
+func OptEventAttempt(attempt int) EventOption {
+	return func(e *Event) { e.Attempt = attempt }
+}
+
 // OptEventElapsed sets a field.
 func OptEventElapsed(elapsed time.Duration) EventOption {
 	return func(e *Event) { e.Elapsed = elapsed }
@@ -71,6 +76,7 @@ type Event struct {
 	JobInvocation string
 	Err           error
 	Elapsed       time.Duration
+	Attempt       int
 }
 
 // GetFlag implements logger.Event.
@@ -83,6 +89,10 @@ func (e Event) Complete() bool {
 
 // WriteText implements logger.TextWritable.
 func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	if e.Attempt > 1 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprintf(wr, "attempt %d", e.Attempt)
+	}
 	if e.Elapsed > 0 {
 		fmt.Fprint(wr, logger.Space)
 		fmt.Fprintf(wr, "(%v)", e.Elapsed)
@@ -91,9 +101,13 @@ func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 
 // Decompose implements logger.JSONWritable.
 func (e Event) Decompose() map[string]interface{} {
-	return map[string]interface{}{
+	output := map[string]interface{}{
 		"jobName": e.JobName,
 		"err":     e.Err,
 		"elapsed": timeutil.Milliseconds(e.Elapsed),
 	}
+	if e.Attempt > 0 {
+		output["attempt"] = e.Attempt
+	}
+	return output
 }
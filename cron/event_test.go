@@ -8,9 +8,11 @@ Use of this source code is governed by a MIT license that can be found in the LI
 package cron
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/logger"
 )
 
 func TestNewEvent(t *testing.T) {
@@ -21,3 +23,19 @@ func TestNewEvent(t *testing.T) {
 	its.Equal(FlagComplete, e.GetFlag())
 	its.Equal("test_task", e.JobName)
 }
+
+func TestEvent_attempt(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	NewEvent(FlagRetry, "test_task", OptEventAttempt(2)).WriteText(logger.NewTextOutputFormatter(logger.OptTextNoColor()), buffer)
+	its.Equal(" attempt 2", buffer.String())
+	its.Equal(2, NewEvent(FlagRetry, "test_task", OptEventAttempt(2)).Decompose()["attempt"])
+
+	buffer.Reset()
+	NewEvent(FlagComplete, "test_task", OptEventAttempt(1)).WriteText(logger.NewTextOutputFormatter(logger.OptTextNoColor()), buffer)
+	its.Empty(buffer.String(), "the first attempt should not be written")
+	_, hasAttempt := NewEvent(FlagComplete, "test_task").Decompose()["attempt"]
+	its.False(hasAttempt)
+}
@@ -0,0 +1,29 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryProvider is a type that persists job invocations.
+//
+// When a job scheduler has a history provider, it records each invocation
+// once it completes, and restores the last invocation when the job is loaded.
+type HistoryProvider interface {
+	// Record records a completed job invocation.
+	Record(ctx context.Context, ji JobInvocation) error
+	// Last returns up to `count` of the most recent invocations of a job, most recent first.
+	Last(ctx context.Context, jobName string, count int) ([]JobInvocation, error)
+	// FailuresSince returns the errored invocations of a job started on or after a given time, most recent first.
+	FailuresSince(ctx context.Context, jobName string, since time.Time) ([]JobInvocation, error)
+	// Cull removes the invocations of a job beyond the most recent `maxCount`, or started before `maxAge` ago.
+	// A limit that is zero is not applied.
+	Cull(ctx context.Context, jobName string, maxCount int, maxAge time.Duration) error
+}
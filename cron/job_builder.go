@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/zpkg/blend-go-sdk/ref"
+	"github.com/zpkg/blend-go-sdk/retry"
 	"github.com/zpkg/blend-go-sdk/stringutil"
 )
 
@@ -74,6 +75,49 @@ func OptJobShutdownGracePeriod(d time.Duration) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.JobConfig.ShutdownGracePeriod = d }
 }
 
+// OptJobTimeZone is a job builder sets the job time zone.
+func OptJobTimeZone(timeZone string) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.TimeZone = timeZone }
+}
+
+// OptJobRetry is a job builder sets the job retry policy.
+func OptJobRetry(maxAttempts int, delayProvider retry.DelayProvider) JobBuilderOption {
+	return func(jb *JobBuilder) {
+		jb.JobConfig.RetryMaxAttempts = maxAttempts
+		jb.JobConfig.RetryDelayProvider = delayProvider
+	}
+}
+
+// OptJobOverlapPolicy is a job builder sets the job overlap policy.
+func OptJobOverlapPolicy(policy OverlapPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.OverlapPolicy = policy }
+}
+
+// OptJobJitter is a job builder sets the maximum random delay added to each scheduled runtime.
+func OptJobJitter(jitter time.Duration) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.Jitter = jitter }
+}
+
+// OptJobMisfirePolicy is a job builder sets the job misfire policy.
+func OptJobMisfirePolicy(policy MisfirePolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.MisfirePolicy = policy }
+}
+
+// OptJobMisfireMaxRuns is a job builder sets the maximum number of missed ticks run by the `run_all` misfire policy.
+func OptJobMisfireMaxRuns(maxRuns int) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.MisfireMaxRuns = maxRuns }
+}
+
+// OptJobDependsOn is a job builder sets the names of the jobs the job depends on.
+func OptJobDependsOn(jobNames ...string) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.DependsOn = jobNames }
+}
+
+// OptJobDependencyFailurePolicy is a job builder sets the job dependency failure policy.
+func OptJobDependencyFailurePolicy(policy DependencyFailurePolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.DependencyFailurePolicy = policy }
+}
+
 // OptJobDisabled is a job builder sets the job timeout provder.
 func OptJobDisabled(disabled bool) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.JobConfig.Disabled = ref.Bool(disabled) }
@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"github.com/zpkg/blend-go-sdk/configutil"
+	"github.com/zpkg/blend-go-sdk/ex"
 	"github.com/zpkg/blend-go-sdk/ref"
+	"github.com/zpkg/blend-go-sdk/retry"
 )
 
 var (
@@ -35,6 +37,45 @@ type JobConfig struct {
 	ShutdownGracePeriod time.Duration `json:"shutdownGracePeriod" yaml:"shutdownGracePeriod"`
 	// SkipLoggerTrigger skips triggering logger events if it is set to true.
 	SkipLoggerTrigger bool `json:"skipLoggerTrigger" yaml:"skipLoggerTrigger"`
+	// LockAtLeastFor is the minimum time the lock for a scheduled tick is held once acquired,
+	// so that processes whose clocks lag can't run the tick again after it completes.
+	// It only applies if the job scheduler has a Locker.
+	LockAtLeastFor time.Duration `json:"lockAtLeastFor" yaml:"lockAtLeastFor"`
+	// HistoryMaxCount is the number of invocations kept by the history provider; zero keeps every invocation.
+	// It only applies if the job scheduler has a HistoryProvider.
+	HistoryMaxCount int `json:"historyMaxCount" yaml:"historyMaxCount"`
+	// HistoryMaxAge is how long invocations are kept by the history provider; zero keeps every invocation.
+	// It only applies if the job scheduler has a HistoryProvider.
+	HistoryMaxAge time.Duration `json:"historyMaxAge" yaml:"historyMaxAge"`
+	// TimeZone is the IANA time zone name, e.g. `America/New_York`, the job's schedule is computed in.
+	// It is overridden by a `CRON_TZ=` prefix on a string schedule.
+	TimeZone string `json:"timeZone" yaml:"timeZone"`
+	// RetryMaxAttempts is the maximum number of attempts of an invocation whose job returns an error.
+	// It defaults to 1, i.e. failed invocations are not retried.
+	RetryMaxAttempts int `json:"retryMaxAttempts" yaml:"retryMaxAttempts"`
+	// RetryDelay is the base delay of the exponential backoff between attempts; it defaults to a second.
+	// It is ignored if `RetryDelayProvider` is set.
+	RetryDelay time.Duration `json:"retryDelay" yaml:"retryDelay"`
+	// RetryDelayProvider returns the delay before the next attempt, given the zero based index of the failed attempt.
+	RetryDelayProvider retry.DelayProvider `json:"-" yaml:"-"`
+	// OverlapPolicy determines if a scheduled tick that fires while the job is still running is skipped,
+	// queued, or replaces the running invocation. It defaults to `skip`.
+	OverlapPolicy OverlapPolicy `json:"overlapPolicy" yaml:"overlapPolicy"`
+	// Jitter is the maximum random delay added to each scheduled runtime,
+	// to spread out jobs that share a schedule. The tick the job runs for,
+	// e.g. for locking, and `NextRuntime` do not include the delay.
+	Jitter time.Duration `json:"jitter" yaml:"jitter"`
+	// MisfirePolicy determines if the ticks missed while no scheduler was running the job are skipped,
+	// run once, or each run. It defaults to `skip`, and only applies if the job scheduler has a LastFiredStore.
+	MisfirePolicy MisfirePolicy `json:"misfirePolicy" yaml:"misfirePolicy"`
+	// MisfireMaxRuns is the maximum number of missed ticks run by the `run_all` misfire policy.
+	MisfireMaxRuns int `json:"misfireMaxRuns" yaml:"misfireMaxRuns"`
+	// DependsOn are the names of the jobs that must complete before the job is triggered.
+	// The job is triggered each time every one of them has completed since it was last triggered.
+	DependsOn []string `json:"dependsOn" yaml:"dependsOn"`
+	// DependencyFailurePolicy determines if the job is skipped or still run when a dependency fails.
+	// It defaults to `skip`.
+	DependencyFailurePolicy DependencyFailurePolicy `json:"dependencyFailurePolicy" yaml:"dependencyFailurePolicy"`
 }
 
 // Resolve implements configutil.Resolver.
@@ -43,6 +84,7 @@ func (jc *JobConfig) Resolve(ctx context.Context) error {
 		configutil.SetBoolPtr(&jc.Disabled, configutil.Bool(jc.Disabled), configutil.Bool(ref.Bool(DefaultDisabled))),
 		configutil.SetDuration(&jc.Timeout, configutil.Duration(jc.Timeout), configutil.Duration(DefaultTimeout)),
 		configutil.SetDuration(&jc.ShutdownGracePeriod, configutil.Duration(jc.ShutdownGracePeriod), configutil.Duration(DefaultShutdownGracePeriod)),
+		configutil.SetDuration(&jc.LockAtLeastFor, configutil.Duration(jc.LockAtLeastFor), configutil.Duration(DefaultLockAtLeastFor)),
 	)
 }
 
@@ -69,3 +111,125 @@ func (jc JobConfig) ShutdownGracePeriodOrDefault() time.Duration {
 	}
 	return DefaultShutdownGracePeriod
 }
+
+// LockAtLeastForOrDefault returns a value or a default.
+func (jc JobConfig) LockAtLeastForOrDefault() time.Duration {
+	if jc.LockAtLeastFor > 0 {
+		return jc.LockAtLeastFor
+	}
+	return DefaultLockAtLeastFor
+}
+
+// RetryMaxAttemptsOrDefault returns a value or a default.
+func (jc JobConfig) RetryMaxAttemptsOrDefault() int {
+	if jc.RetryMaxAttempts > 0 {
+		return jc.RetryMaxAttempts
+	}
+	return DefaultRetryMaxAttempts
+}
+
+// RetryDelayOrDefault returns a value or a default.
+func (jc JobConfig) RetryDelayOrDefault() time.Duration {
+	if jc.RetryDelay > 0 {
+		return jc.RetryDelay
+	}
+	return DefaultRetryDelay
+}
+
+// RetryDelayProviderOrDefault returns the retry delay provider, or an
+// exponential backoff from the retry delay.
+func (jc JobConfig) RetryDelayProviderOrDefault() retry.DelayProvider {
+	if jc.RetryDelayProvider != nil {
+		return jc.RetryDelayProvider
+	}
+	return retry.ExponentialBackoff(jc.RetryDelayOrDefault())
+}
+
+// OverlapPolicyOrDefault returns a value or a default.
+func (jc JobConfig) OverlapPolicyOrDefault() OverlapPolicy {
+	if jc.OverlapPolicy != "" {
+		return jc.OverlapPolicy
+	}
+	return DefaultOverlapPolicy
+}
+
+// MisfirePolicyOrDefault returns a value or a default.
+func (jc JobConfig) MisfirePolicyOrDefault() MisfirePolicy {
+	if jc.MisfirePolicy != "" {
+		return jc.MisfirePolicy
+	}
+	return DefaultMisfirePolicy
+}
+
+// MisfireMaxRunsOrDefault returns a value or a default.
+func (jc JobConfig) MisfireMaxRunsOrDefault() int {
+	if jc.MisfireMaxRuns > 0 {
+		return jc.MisfireMaxRuns
+	}
+	return DefaultMisfireMaxRuns
+}
+
+// DependencyFailurePolicyOrDefault returns a value or a default.
+func (jc JobConfig) DependencyFailurePolicyOrDefault() DependencyFailurePolicy {
+	if jc.DependencyFailurePolicy != "" {
+		return jc.DependencyFailurePolicy
+	}
+	return DefaultDependencyFailurePolicy
+}
+
+// Location returns the time zone location or UTC if it's unset.
+func (jc JobConfig) Location() (*time.Location, error) {
+	if jc.TimeZone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(jc.TimeZone)
+	if err != nil {
+		return nil, ex.New(ErrJobTimeZoneInvalid, ex.OptInner(err), ex.OptMessagef("time zone: %s", jc.TimeZone))
+	}
+	return location, nil
+}
+
+// Validate returns an error if the config has an invalid time zone,
+// an unknown policy, or a negative duration or count.
+func (jc JobConfig) Validate() error {
+	if _, err := jc.Location(); err != nil {
+		return err
+	}
+	switch jc.OverlapPolicy {
+	case "", OverlapPolicySkip, OverlapPolicyQueue, OverlapPolicyReplace:
+	default:
+		return ex.New(ErrJobConfigInvalid, ex.OptMessagef("unknown overlap policy: %s", jc.OverlapPolicy))
+	}
+	switch jc.MisfirePolicy {
+	case "", MisfirePolicySkip, MisfirePolicyRunOnce, MisfirePolicyRunAll:
+	default:
+		return ex.New(ErrJobConfigInvalid, ex.OptMessagef("unknown misfire policy: %s", jc.MisfirePolicy))
+	}
+	switch jc.DependencyFailurePolicy {
+	case "", DependencyFailurePolicySkip, DependencyFailurePolicyRun:
+	default:
+		return ex.New(ErrJobConfigInvalid, ex.OptMessagef("unknown dependency failure policy: %s", jc.DependencyFailurePolicy))
+	}
+	for name, value := range map[string]time.Duration{
+		"timeout":             jc.Timeout,
+		"shutdownGracePeriod": jc.ShutdownGracePeriod,
+		"lockAtLeastFor":      jc.LockAtLeastFor,
+		"historyMaxAge":       jc.HistoryMaxAge,
+		"retryDelay":          jc.RetryDelay,
+		"jitter":              jc.Jitter,
+	} {
+		if value < 0 {
+			return ex.New(ErrJobConfigInvalid, ex.OptMessagef("%s must not be negative: %v", name, value))
+		}
+	}
+	for name, value := range map[string]int{
+		"historyMaxCount":  jc.HistoryMaxCount,
+		"retryMaxAttempts": jc.RetryMaxAttempts,
+		"misfireMaxRuns":   jc.MisfireMaxRuns,
+	} {
+		if value < 0 {
+			return ex.New(ErrJobConfigInvalid, ex.OptMessagef("%s must not be negative: %d", name, value))
+		}
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
 )
 
 func TestJobConfig(t *testing.T) {
@@ -27,3 +28,42 @@ func TestJobConfig(t *testing.T) {
 	assert.Equal(jc.Timeout, jc.TimeoutOrDefault())
 	assert.Equal(jc.ShutdownGracePeriod, jc.ShutdownGracePeriodOrDefault())
 }
+
+func TestJobConfig_Location(t *testing.T) {
+	assert := assert.New(t)
+
+	var jc JobConfig
+	location, err := jc.Location()
+	assert.Nil(err)
+	assert.Equal(time.UTC, location)
+
+	jc.TimeZone = "America/New_York"
+	location, err = jc.Location()
+	assert.Nil(err)
+	assert.Equal("America/New_York", location.String())
+
+	jc.TimeZone = "Not/A_Zone"
+	location, err = jc.Location()
+	assert.True(ex.Is(err, ErrJobTimeZoneInvalid))
+	assert.Nil(location)
+}
+
+func TestJobConfig_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(JobConfig{}.Validate())
+	assert.Nil(JobConfig{
+		TimeZone:                "America/New_York",
+		OverlapPolicy:           OverlapPolicyQueue,
+		MisfirePolicy:           MisfirePolicyRunAll,
+		DependencyFailurePolicy: DependencyFailurePolicyRun,
+		RetryMaxAttempts:        3,
+	}.Validate())
+
+	assert.True(ex.Is(JobConfig{TimeZone: "Not/A_Zone"}.Validate(), ErrJobTimeZoneInvalid))
+	assert.True(ex.Is(JobConfig{OverlapPolicy: "wait"}.Validate(), ErrJobConfigInvalid))
+	assert.True(ex.Is(JobConfig{MisfirePolicy: "run_twice"}.Validate(), ErrJobConfigInvalid))
+	assert.True(ex.Is(JobConfig{DependencyFailurePolicy: "retry"}.Validate(), ErrJobConfigInvalid))
+	assert.True(ex.Is(JobConfig{Timeout: -time.Second}.Validate(), ErrJobConfigInvalid))
+	assert.True(ex.Is(JobConfig{RetryMaxAttempts: -1}.Validate(), ErrJobConfigInvalid))
+}
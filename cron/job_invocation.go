@@ -57,6 +57,8 @@ type JobInvocation struct {
 	Parameters JobParameters       `json:"parameters"`
 	Status     JobInvocationStatus `json:"status"`
 	State      interface{}         `json:"-"`
+	// Attempt is the current attempt of the invocation, starting at 1, if the job is retried.
+	Attempt int `json:"attempt"`
 
 	Cancel context.CancelFunc `json:"-"`
 }
@@ -85,6 +87,7 @@ func (ji *JobInvocation) Clone() *JobInvocation {
 		Parameters: ji.Parameters,
 		Status:     ji.Status,
 		State:      ji.State,
+		Attempt:    ji.Attempt,
 
 		Cancel: ji.Cancel,
 	}
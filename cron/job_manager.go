@@ -35,13 +35,20 @@ func New(options ...JobManagerOption) *JobManager {
 // JobManager is the main orchestration and job management object.
 type JobManager struct {
 	sync.Mutex
-	Latch       *async.Latch
-	BaseContext context.Context
-	Tracer      Tracer
-	Log         logger.Log
-	Started     time.Time
-	Stopped     time.Time
-	Jobs        map[string]*JobScheduler
+	Latch          *async.Latch
+	BaseContext    context.Context
+	Tracer         Tracer
+	Log            logger.Log
+	Locker         Locker
+	History        HistoryProvider
+	LastFiredStore LastFiredStore
+	Started        time.Time
+	Stopped        time.Time
+	Jobs           map[string]*JobScheduler
+
+	// dependencyResults holds, for each job with dependencies, the status of each
+	// dependency that has completed since the job was last triggered.
+	dependencyResults map[string]map[string]JobInvocationStatus
 }
 
 // Background returns the BaseContext or context.Background().
@@ -127,9 +134,14 @@ func (jm *JobManager) LoadJobs(jobs ...Job) error {
 	jm.Lock()
 	defer jm.Unlock()
 
+	jobSchedulers := make([]*JobScheduler, 0, len(jobs))
+	loaded := make(map[string]*JobScheduler, len(jm.Jobs)+len(jobs))
+	for jobName, jobScheduler := range jm.Jobs {
+		loaded[jobName] = jobScheduler
+	}
 	for _, job := range jobs {
 		jobName := job.Name()
-		if _, hasJob := jm.Jobs[jobName]; hasJob {
+		if _, hasJob := loaded[jobName]; hasJob {
 			return ex.New(ErrJobAlreadyLoaded, ex.OptMessagef("job: %s", job.Name()))
 		}
 
@@ -138,11 +150,26 @@ func (jm *JobManager) LoadJobs(jobs ...Job) error {
 			OptJobSchedulerLog(jm.Log),
 			OptJobSchedulerTracer(jm.Tracer),
 			OptJobSchedulerBaseContext(jm.Background()),
+			OptJobSchedulerLocker(jm.Locker),
+			OptJobSchedulerHistory(jm.History),
+			OptJobSchedulerLastFiredStore(jm.LastFiredStore),
 		)
+		if err := jobScheduler.Config().Validate(); err != nil {
+			return err
+		}
+		jobScheduler.completeHandler = jm.onJobComplete
+		jobSchedulers = append(jobSchedulers, jobScheduler)
+		loaded[jobName] = jobScheduler
+	}
+	if err := validateDependencies(loaded); err != nil {
+		return err
+	}
+
+	for _, jobScheduler := range jobSchedulers {
 		if err := jobScheduler.OnLoad(jobScheduler.Background()); err != nil {
 			return err
 		}
-		jm.Jobs[jobName] = jobScheduler
+		jm.Jobs[jobScheduler.Name()] = jobScheduler
 	}
 	return nil
 }
@@ -161,6 +188,7 @@ func (jm *JobManager) UnloadJobs(jobNames ...string) error {
 				jm.error(err)
 			}
 			delete(jm.Jobs, jobName)
+			delete(jm.dependencyResults, jobName)
 		} else {
 			return ex.New(ErrJobNotFound, ex.OptMessagef("job: %s", jobName))
 		}
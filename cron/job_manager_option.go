@@ -30,3 +30,19 @@ func OptTracer(tracer Tracer) JobManagerOption {
 func OptBaseContext(ctx context.Context) JobManagerOption {
 	return func(jm *JobManager) { jm.BaseContext = ctx }
 }
+
+// OptLocker sets the job manager locker, which is given to the job schedulers
+// so that jobs run once per tick across every job manager that shares it.
+func OptLocker(locker Locker) JobManagerOption {
+	return func(jm *JobManager) { jm.Locker = locker }
+}
+
+// OptHistory sets the job manager history provider, which is given to the job schedulers.
+func OptHistory(history HistoryProvider) JobManagerOption {
+	return func(jm *JobManager) { jm.History = history }
+}
+
+// OptLastFiredStore sets the job manager last fired store, which is given to the job schedulers.
+func OptLastFiredStore(store LastFiredStore) JobManagerOption {
+	return func(jm *JobManager) { jm.LastFiredStore = store }
+}
@@ -10,6 +10,7 @@ package cron
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -47,15 +48,28 @@ type JobScheduler struct {
 
 	BaseContext context.Context
 
-	Tracer Tracer
-	Log    logger.Log
+	Tracer         Tracer
+	Log            logger.Log
+	Locker         Locker
+	History        HistoryProvider
+	LastFiredStore LastFiredStore
 
+	// NextRuntime is the next scheduled runtime; it is written by the run loop, and
+	// should be read with `NextRuntimeUTC` while the scheduler is running.
 	NextRuntime time.Time
 
-	currentLock sync.Mutex
-	current     *JobInvocation
-	lastLock    sync.Mutex
-	last        *JobInvocation
+	nextRuntimeLock sync.Mutex
+	currentLock     sync.Mutex
+	current         *JobInvocation
+	lastLock        sync.Mutex
+	last            *JobInvocation
+	queueLock       sync.Mutex
+	queued          time.Time
+	locationLock    sync.Mutex
+	loadedLocation  *time.Location
+
+	// completeHandler is called with the invocation once a job completes, to trigger dependent jobs.
+	completeHandler func(context.Context, *JobInvocation)
 }
 
 // Name returns the job name.
@@ -150,6 +164,7 @@ func (js *JobScheduler) Stop() error {
 
 	ctx := js.withBaseContext(js.Background())
 	js.Latch.Stopping()
+	js.dequeue() // drop any queued tick so it does not run once the current invocation is canceled
 
 	if current := js.Current(); current != nil {
 		gracePeriod := js.Config().ShutdownGracePeriodOrDefault()
@@ -166,13 +181,27 @@ func (js *JobScheduler) Stop() error {
 
 	<-js.Latch.NotifyStopped()
 	js.Latch.Reset()
-	js.NextRuntime = Zero
+	js.setNextRuntime(Zero)
 	return nil
 }
 
 // OnLoad triggers the on load even on the job lifecycle handler.
+//
+// It resolves the job's time zone, returning the same error as the config's `Validate` if it is invalid.
+// If the scheduler has a history provider, it also restores the last invocation.
 func (js *JobScheduler) OnLoad(ctx context.Context) error {
+	location, err := js.Config().Location()
+	if err != nil {
+		return err
+	}
+	js.locationLock.Lock()
+	js.loadedLocation = location
+	js.locationLock.Unlock()
+
 	ctx = js.withBaseContext(ctx)
+	if js.History != nil {
+		js.restoreHistory(ctx)
+	}
 	if js.Lifecycle().OnLoad != nil {
 		if err := js.Lifecycle().OnLoad(ctx); err != nil {
 			return err
@@ -259,36 +288,36 @@ func (js *JobScheduler) RunLoop() {
 	}()
 
 	if js.JobSchedule != nil {
-		js.NextRuntime = js.JobSchedule.Next(js.NextRuntime)
+		if js.LastFiredStore != nil {
+			js.catchUp()
+		}
+		js.setNextRuntime(js.next(js.NextRuntimeUTC()))
 	}
 
 	// if the schedule returns a zero timestamp
 	// it should be interpretted as *not* to automatically
 	// schedule the job to be run.
 	// The run loop will return and the job scheduler will be interpretted as stopped.
-	if js.NextRuntime.IsZero() {
+	if js.NextRuntimeUTC().IsZero() {
 		return
 	}
 
 	for {
-		if js.NextRuntime.IsZero() {
+		nextRuntime := js.NextRuntimeUTC()
+		if nextRuntime.IsZero() {
 			return
 		}
 
-		runAt := time.After(js.NextRuntime.UTC().Sub(Now()))
+		runAt := time.After(nextRuntime.Sub(Now()) + js.jitter())
 		select {
 		case <-runAt:
-			if js.CanBeScheduled() {
-				if _, _, err := js.RunAsyncContext(js.Background()); err != nil {
-					_ = js.error(js.Background(), err)
-				}
-			}
+			js.onTick(nextRuntime)
 
 			// set up the next runtime.
 			if js.JobSchedule != nil {
-				js.NextRuntime = js.JobSchedule.Next(js.NextRuntime)
+				js.setNextRuntime(js.next(nextRuntime))
 			} else {
-				js.NextRuntime = Zero
+				js.setNextRuntime(Zero)
 			}
 
 		case <-js.Latch.NotifyStopping():
@@ -306,7 +335,15 @@ func (js *JobScheduler) RunAsync() (*JobInvocation, <-chan struct{}, error) {
 }
 
 // RunAsyncContext starts a job invocation with a given context.
+//
+// It does not acquire a lock from the Locker; only scheduled runs do.
 func (js *JobScheduler) RunAsyncContext(ctx context.Context) (*JobInvocation, <-chan struct{}, error) {
+	return js.runAsyncContext(ctx, nil)
+}
+
+// runAsyncContext starts a job invocation with a given context and an optional lease,
+// canceling the invocation if the lease is lost.
+func (js *JobScheduler) runAsyncContext(ctx context.Context, lease Lease) (*JobInvocation, <-chan struct{}, error) {
 	if !js.IsIdle() {
 		return nil, nil, ex.New(ErrJobAlreadyRunning, ex.OptMessagef("job: %s", js.Name()))
 	}
@@ -318,6 +355,11 @@ func (js *JobScheduler) RunAsyncContext(ctx context.Context) (*JobInvocation, <-
 
 	var err error
 	var tracer TraceFinisher
+	var leaseLost <-chan struct{}
+	if lease != nil {
+		leaseLost = lease.Lost()
+	}
+	leaseAcquired := Now()
 	go func() {
 		defer func() {
 			switch {
@@ -333,9 +375,16 @@ func (js *JobScheduler) RunAsyncContext(ctx context.Context) (*JobInvocation, <-
 				tracer.Finish(ctx, err) // call the trace finisher if one was started
 			}
 			ji.Cancel() // if the job was created with a timeout, end the timeout
+			if js.History != nil {
+				js.recordHistory() // persist the completed invocation
+			}
+			if lease != nil {
+				js.release(lease, leaseAcquired) // release the lock once it has been held long enough
+			}
 
 			close(done)              // signal callers the job is done
 			js.assignCurrentToLast() // rotate in the current to the last result
+			js.runQueued()           // run a tick queued by the overlap policy
 		}()
 
 		if js.Tracer != nil {
@@ -347,7 +396,11 @@ func (js *JobScheduler) RunAsyncContext(ctx context.Context) (*JobInvocation, <-
 		case <-ctx.Done(): // if the timeout or cancel is triggered
 			err = ErrJobCanceled // set the error to a known error
 			return
-		case err = <-js.safeBackgroundExec(ctx): // run the job in a background routine and catch pancis
+		case <-leaseLost: // if the lock is lost, another process may run the job
+			js.onLockLost(ctx)
+			err = ErrJobCanceled
+			return
+		case err = <-js.execute(ctx, ji): // run the job in a background routine, retrying failed attempts
 			return
 		}
 	}()
@@ -393,6 +446,23 @@ func (js *JobScheduler) IsIdle() (isIdle bool) {
 // utility functions
 //
 
+// NextRuntimeUTC returns the next scheduled runtime in UTC, or a zero time if none is scheduled.
+func (js *JobScheduler) NextRuntimeUTC() time.Time {
+	js.nextRuntimeLock.Lock()
+	defer js.nextRuntimeLock.Unlock()
+	if js.NextRuntime.IsZero() {
+		return Zero
+	}
+	return js.NextRuntime.UTC()
+}
+
+// setNextRuntime sets the next scheduled runtime.
+func (js *JobScheduler) setNextRuntime(nextRuntime time.Time) {
+	js.nextRuntimeLock.Lock()
+	js.NextRuntime = nextRuntime
+	js.nextRuntimeLock.Unlock()
+}
+
 // Current returns the current job invocation.
 func (js *JobScheduler) Current() (current *JobInvocation) {
 	js.currentLock.Lock()
@@ -427,6 +497,262 @@ func (js *JobScheduler) SetLast(ji *JobInvocation) {
 	js.lastLock.Unlock()
 }
 
+// onTick runs the job for a scheduled tick, applying the job's
+// overlap policy if the previous invocation is still running.
+func (js *JobScheduler) onTick(tick time.Time) {
+	if js.Disabled() {
+		return
+	}
+	if js.IsIdle() {
+		js.runScheduled(tick)
+		return
+	}
+
+	ctx := js.withBaseContext(js.Background())
+	config := js.Config()
+	flag := FlagSkipped
+	switch config.OverlapPolicyOrDefault() {
+	case OverlapPolicyQueue:
+		if js.enqueue(tick, false) {
+			flag = FlagQueued
+		}
+	case OverlapPolicyReplace:
+		js.enqueue(tick, true)
+		flag = FlagReplaced
+	}
+	if js.Log != nil && !config.SkipLoggerTrigger {
+		js.logTrigger(ctx, NewEvent(flag, js.Name()))
+	}
+	if flag == FlagReplaced {
+		// cancel the running invocation; the queued tick runs once it completes.
+		if current := js.Current(); current != nil && current.Cancel != nil {
+			current.Cancel()
+		}
+	}
+	// the running invocation may have completed before the tick was queued
+	if js.IsIdle() {
+		js.runQueued()
+	}
+}
+
+// enqueue queues a tick to run once the current invocation completes,
+// returning false if a tick is already queued and `replace` is not set.
+func (js *JobScheduler) enqueue(tick time.Time, replace bool) bool {
+	js.queueLock.Lock()
+	defer js.queueLock.Unlock()
+	if !js.queued.IsZero() && !replace {
+		return false
+	}
+	js.queued = tick
+	return true
+}
+
+// dequeue removes and returns the queued tick, or a zero time if none is queued.
+func (js *JobScheduler) dequeue() (tick time.Time) {
+	js.queueLock.Lock()
+	tick, js.queued = js.queued, Zero
+	js.queueLock.Unlock()
+	return
+}
+
+// runQueued runs the queued tick, if any.
+func (js *JobScheduler) runQueued() {
+	if tick := js.dequeue(); !tick.IsZero() && !js.Disabled() {
+		js.runScheduled(tick)
+	}
+}
+
+// jitter returns a random delay up to the job's `Jitter`.
+func (js *JobScheduler) jitter() time.Duration {
+	if jitter := js.Config().Jitter; jitter > 0 {
+		return time.Duration(rand.Int63n(int64(jitter)))
+	}
+	return 0
+}
+
+// runScheduled runs the job for a scheduled tick, acquiring
+// the lock for the tick first if the scheduler has a Locker.
+//
+// It returns a channel that is closed when the invocation completes,
+// or nil if the job was not run.
+func (js *JobScheduler) runScheduled(tick time.Time) <-chan struct{} {
+	ctx := js.Background()
+	var lease Lease
+	if js.Locker != nil {
+		var err error
+		lease, err = js.Locker.Lock(js.withBaseContext(ctx), LockKey(js.Name(), tick))
+		if IsJobLockHeld(err) {
+			if js.Log != nil && !js.Config().SkipLoggerTrigger {
+				js.logTrigger(js.withBaseContext(ctx), NewEvent(FlagLockHeld, js.Name()))
+			}
+			return nil
+		}
+		if err != nil {
+			_ = js.error(ctx, err)
+			return nil
+		}
+	}
+	_, done, err := js.runAsyncContext(ctx, lease)
+	if err != nil {
+		if lease != nil {
+			if releaseErr := lease.Release(ctx); releaseErr != nil {
+				_ = js.error(ctx, releaseErr)
+			}
+		}
+		_ = js.error(ctx, err)
+		return nil
+	}
+	if js.LastFiredStore != nil {
+		if err := js.LastFiredStore.SetLastFired(js.withBaseContext(ctx), js.Name(), tick); err != nil {
+			_ = js.error(ctx, err)
+		}
+	}
+	return done
+}
+
+// catchUp runs the job for the ticks missed since it last fired, according to its misfire policy.
+//
+// Missed ticks are run one at a time, each once the previous invocation completes.
+func (js *JobScheduler) catchUp() {
+	ctx := js.withBaseContext(js.Background())
+	lastFired, err := js.LastFiredStore.LastFired(ctx, js.Name())
+	if err != nil {
+		_ = js.error(ctx, err)
+		return
+	}
+	if lastFired.IsZero() {
+		return
+	}
+
+	config := js.Config()
+	policy := config.MisfirePolicyOrDefault()
+	missed, total, more := js.missedTicks(lastFired, Now(), config.MisfireMaxRunsOrDefault())
+	if total == 0 {
+		return
+	}
+	if more {
+		js.debugf(ctx, "missed more than %d tick(s) since %v; misfire policy: %s", total, lastFired.UTC(), policy)
+	} else {
+		js.debugf(ctx, "missed %d tick(s) since %v; misfire policy: %s", total, lastFired.UTC(), policy)
+	}
+	if js.Log != nil && !config.SkipLoggerTrigger {
+		js.logTrigger(ctx, NewEvent(FlagMisfire, js.Name()))
+	}
+
+	switch policy {
+	case MisfirePolicyRunOnce:
+		missed = missed[len(missed)-1:]
+	case MisfirePolicyRunAll:
+	default:
+		return
+	}
+	for _, tick := range missed {
+		if !js.CanBeScheduled() {
+			return
+		}
+		done := js.runScheduled(tick)
+		if done == nil {
+			continue
+		}
+		select {
+		case <-done:
+		case <-js.Latch.NotifyStopping():
+			return
+		}
+	}
+}
+
+// maxCountedMissedTicks is the number of ticks missedTicks walks from a given start before
+// it gives up counting and restarts closer to `now`.
+const maxCountedMissedTicks = 1024
+
+// missedTicks returns up to `max` of the most recent ticks after `lastFired` and on or before `now`,
+// oldest first, and the number of ticks missed.
+// If more ticks were missed than can be counted, `total` is a lower bound and `more` is set.
+func (js *JobScheduler) missedTicks(lastFired, now time.Time, max int) (missed []time.Time, total int, more bool) {
+	limit := maxCountedMissedTicks
+	if max >= limit {
+		limit = max + 1
+	}
+	location := js.location()
+	start := lastFired
+	for {
+		missed, total = nil, 0
+		tick := js.nextIn(location, start)
+		for ; !tick.IsZero() && !tick.After(now) && total < limit; tick = js.nextIn(location, tick) {
+			total++
+			missed = append(missed, tick)
+			if len(missed) > max {
+				missed = missed[1:]
+			}
+		}
+		if tick.IsZero() || tick.After(now) {
+			return
+		}
+		// too many ticks to count since `start`; the most recent ones are after the midpoint.
+		more = true
+		start = start.Add(now.Sub(start) / 2)
+	}
+}
+
+// release releases a lease once it has been held for the job's `LockAtLeastFor`.
+func (js *JobScheduler) release(lease Lease, acquired time.Time) {
+	ctx := js.withBaseContext(js.Background())
+	remaining := js.Config().LockAtLeastForOrDefault() - Since(acquired)
+	if remaining <= 0 {
+		if err := lease.Release(ctx); err != nil {
+			_ = js.error(ctx, err)
+		}
+		return
+	}
+	go func() {
+		alarm := time.NewTimer(remaining)
+		defer alarm.Stop()
+		select {
+		case <-alarm.C:
+		case <-lease.Lost():
+			return
+		}
+		if err := lease.Release(ctx); err != nil {
+			_ = js.error(ctx, err)
+		}
+	}()
+}
+
+// restoreHistory sets the last invocation from the history provider.
+func (js *JobScheduler) restoreHistory(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultHistoryRestoreTimeout)
+	defer cancel()
+	last, err := js.History.Last(ctx, js.Name(), 1)
+	if err != nil {
+		_ = js.error(ctx, err)
+		return
+	}
+	if len(last) > 0 {
+		js.SetLast(&last[0])
+	}
+}
+
+// recordHistory records the current invocation with the history provider
+// and culls invocations beyond the job's retention limits.
+func (js *JobScheduler) recordHistory() {
+	current := js.Current()
+	if current == nil {
+		return
+	}
+	ctx := js.withBaseContext(js.Background())
+	if err := js.History.Record(ctx, *current); err != nil {
+		_ = js.error(ctx, err)
+		return
+	}
+	config := js.Config()
+	if config.HistoryMaxCount > 0 || config.HistoryMaxAge > 0 {
+		if err := js.History.Cull(ctx, js.Name(), config.HistoryMaxCount, config.HistoryMaxAge); err != nil {
+			_ = js.error(ctx, err)
+		}
+	}
+}
+
 func (js *JobScheduler) assignCurrentToLast() {
 	js.lastLock.Lock()
 	js.currentLock.Lock()
@@ -453,6 +779,56 @@ func (js *JobScheduler) waitCurrentComplete(ctx context.Context) {
 	}
 }
 
+// execute runs the job in a background routine, retrying failed
+// attempts according to the job's retry policy.
+//
+// Retries are part of the same invocation; they count toward its elapsed
+// time and timeout, and the invocation's `Attempt` is incremented before each.
+func (js *JobScheduler) execute(ctx context.Context, ji *JobInvocation) chan error {
+	config := js.Config()
+	maxAttempts := config.RetryMaxAttemptsOrDefault()
+	if maxAttempts <= 1 {
+		return js.safeBackgroundExec(ctx)
+	}
+
+	errors := make(chan error, 1)
+	go func() {
+		delay := config.RetryDelayProviderOrDefault()
+		for attempt := 1; ; attempt++ {
+			err := <-js.safeBackgroundExec(ctx)
+			if err != nil && ctx.Err() != nil {
+				// the attempt failed because the invocation was canceled.
+				errors <- ex.New(ErrJobCanceled)
+				return
+			}
+			if err == nil || attempt >= maxAttempts {
+				errors <- err
+				return
+			}
+			js.onJobRetry(ctx, ji, attempt+1, err)
+
+			alarm := time.NewTimer(delay(ctx, uint(attempt-1)))
+			select {
+			case <-ctx.Done():
+				alarm.Stop()
+				errors <- ex.New(ErrJobCanceled)
+				return
+			case <-alarm.C:
+			}
+			// the invocation may have been canceled as the alarm fired.
+			if ctx.Err() != nil {
+				errors <- ex.New(ErrJobCanceled)
+				return
+			}
+
+			js.currentLock.Lock()
+			ji.Attempt = attempt + 1
+			js.currentLock.Unlock()
+		}
+	}()
+	return errors
+}
+
 func (js *JobScheduler) safeBackgroundExec(ctx context.Context) chan error {
 	errors := make(chan error, 2)
 	go func() {
@@ -466,6 +842,33 @@ func (js *JobScheduler) safeBackgroundExec(ctx context.Context) chan error {
 	return errors
 }
 
+// next returns the next runtime from the schedule, computed in the job's time zone.
+func (js *JobScheduler) next(after time.Time) time.Time {
+	return js.nextIn(js.location(), after)
+}
+
+// nextIn returns the next runtime from the schedule, computed in a given time zone.
+func (js *JobScheduler) nextIn(location *time.Location, after time.Time) time.Time {
+	return js.JobSchedule.Next(after.In(location))
+}
+
+// location returns the job's time zone resolved by `OnLoad`.
+// If the job has not been loaded it is resolved from the config, falling back to UTC if it is invalid.
+func (js *JobScheduler) location() *time.Location {
+	js.locationLock.Lock()
+	loaded := js.loadedLocation
+	js.locationLock.Unlock()
+	if loaded != nil {
+		return loaded
+	}
+	location, err := js.Config().Location()
+	if err != nil {
+		_ = js.error(js.Background(), err)
+		return time.UTC
+	}
+	return location
+}
+
 func (js *JobScheduler) withBaseContext(ctx context.Context) context.Context {
 	if typed, ok := js.Job.(BackgroundProvider); ok {
 		ctx = typed.Background(ctx)
@@ -498,6 +901,7 @@ func (js *JobScheduler) onJobBegin(ctx context.Context) {
 	js.currentLock.Lock()
 	js.current.Started = time.Now().UTC()
 	js.current.Status = JobInvocationStatusRunning
+	js.current.Attempt = 1
 	id := js.current.ID
 	js.currentLock.Unlock()
 
@@ -509,11 +913,41 @@ func (js *JobScheduler) onJobBegin(ctx context.Context) {
 	}
 }
 
+func (js *JobScheduler) onLockLost(ctx context.Context) {
+	js.currentLock.Lock()
+	id := js.current.ID
+	js.currentLock.Unlock()
+
+	_ = js.error(ctx, ex.New(ErrJobLockLost, ex.OptMessagef("job: %s", js.Name())))
+	if js.Log != nil && !js.Config().SkipLoggerTrigger {
+		js.logTrigger(ctx, NewEvent(FlagLockLost, js.Name(), OptEventJobInvocation(id)))
+	}
+}
+
+// onJobRetry is called with the number of the attempt that will be made next
+// and the error of the attempt that failed.
+func (js *JobScheduler) onJobRetry(ctx context.Context, ji *JobInvocation, nextAttempt int, err error) {
+	js.currentLock.Lock()
+	id := ji.ID
+	elapsed := ji.Elapsed()
+	js.currentLock.Unlock()
+
+	if js.Log != nil && !js.Config().SkipLoggerTrigger {
+		js.logTrigger(ctx, NewEvent(FlagRetry, js.Name(),
+			OptEventJobInvocation(id),
+			OptEventAttempt(nextAttempt),
+			OptEventErr(err),
+			OptEventElapsed(elapsed),
+		))
+	}
+}
+
 func (js *JobScheduler) onJobCompleteCanceled(ctx context.Context) {
 	js.currentLock.Lock()
 	js.current.Complete = time.Now().UTC()
 	js.current.Status = JobInvocationStatusCanceled
 	id := js.current.ID
+	attempt := js.current.Attempt
 	elapsed := js.current.Elapsed()
 	js.currentLock.Unlock()
 
@@ -522,12 +956,13 @@ func (js *JobScheduler) onJobCompleteCanceled(ctx context.Context) {
 		lifecycle.OnCancellation(ctx)
 	}
 	if js.Log != nil && !js.Config().SkipLoggerTrigger {
-		js.logTrigger(ctx, NewEvent(FlagCanceled, js.Name(), OptEventJobInvocation(id), OptEventElapsed(elapsed)))
-		js.logTrigger(ctx, NewEvent(FlagComplete, js.Name(), OptEventJobInvocation(id), OptEventElapsed(elapsed)))
+		js.logTrigger(ctx, NewEvent(FlagCanceled, js.Name(), OptEventJobInvocation(id), OptEventAttempt(attempt), OptEventElapsed(elapsed)))
+		js.logTrigger(ctx, NewEvent(FlagComplete, js.Name(), OptEventJobInvocation(id), OptEventAttempt(attempt), OptEventElapsed(elapsed)))
 	}
 	if lifecycle.OnComplete != nil {
 		lifecycle.OnComplete(ctx)
 	}
+	js.onComplete(ctx)
 }
 
 func (js *JobScheduler) onJobCompleteSuccess(ctx context.Context) {
@@ -535,6 +970,7 @@ func (js *JobScheduler) onJobCompleteSuccess(ctx context.Context) {
 	js.current.Complete = time.Now().UTC()
 	js.current.Status = JobInvocationStatusSuccess
 	id := js.current.ID
+	attempt := js.current.Attempt
 	elapsed := js.current.Elapsed()
 	js.currentLock.Unlock()
 
@@ -543,20 +979,21 @@ func (js *JobScheduler) onJobCompleteSuccess(ctx context.Context) {
 		lifecycle.OnSuccess(ctx)
 	}
 	if js.Log != nil && !js.Config().SkipLoggerTrigger {
-		js.logTrigger(ctx, NewEvent(FlagSuccess, js.Name(), OptEventJobInvocation(id), OptEventElapsed(elapsed)))
-		js.logTrigger(ctx, NewEvent(FlagComplete, js.Name(), OptEventJobInvocation(id), OptEventElapsed(elapsed)))
+		js.logTrigger(ctx, NewEvent(FlagSuccess, js.Name(), OptEventJobInvocation(id), OptEventAttempt(attempt), OptEventElapsed(elapsed)))
+		js.logTrigger(ctx, NewEvent(FlagComplete, js.Name(), OptEventJobInvocation(id), OptEventAttempt(attempt), OptEventElapsed(elapsed)))
 	}
 	if last := js.Last(); last != nil && last.Status == JobInvocationStatusErrored {
 		if lifecycle.OnFixed != nil {
 			lifecycle.OnFixed(ctx)
 		}
 		if js.Log != nil && !js.Config().SkipLoggerTrigger {
-			js.logTrigger(ctx, NewEvent(FlagFixed, js.Name(), OptEventJobInvocation(id), OptEventElapsed(elapsed)))
+			js.logTrigger(ctx, NewEvent(FlagFixed, js.Name(), OptEventJobInvocation(id), OptEventAttempt(attempt), OptEventElapsed(elapsed)))
 		}
 	}
 	if lifecycle.OnComplete != nil {
 		lifecycle.OnComplete(ctx)
 	}
+	js.onComplete(ctx)
 }
 
 func (js *JobScheduler) onJobCompleteError(ctx context.Context, err error) {
@@ -565,6 +1002,7 @@ func (js *JobScheduler) onJobCompleteError(ctx context.Context, err error) {
 	js.current.Status = JobInvocationStatusErrored
 	js.current.Err = err
 	id := js.current.ID
+	attempt := js.current.Attempt
 	elapsed := js.current.Elapsed()
 	js.currentLock.Unlock()
 
@@ -581,10 +1019,11 @@ func (js *JobScheduler) onJobCompleteError(ctx context.Context, err error) {
 	if js.Log != nil && !js.Config().SkipLoggerTrigger {
 		js.logTrigger(ctx, NewEvent(FlagErrored, js.Name(),
 			OptEventJobInvocation(id),
+			OptEventAttempt(attempt),
 			OptEventErr(err),
 			OptEventElapsed(elapsed),
 		))
-		js.logTrigger(ctx, NewEvent(FlagComplete, js.Name(), OptEventJobInvocation(id), OptEventElapsed(elapsed)))
+		js.logTrigger(ctx, NewEvent(FlagComplete, js.Name(), OptEventJobInvocation(id), OptEventAttempt(attempt), OptEventElapsed(elapsed)))
 	}
 
 	//
@@ -598,6 +1037,7 @@ func (js *JobScheduler) onJobCompleteError(ctx context.Context, err error) {
 		if js.Log != nil && !js.Config().SkipLoggerTrigger {
 			js.logTrigger(ctx, NewEvent(FlagBroken, js.Name(),
 				OptEventJobInvocation(id),
+				OptEventAttempt(attempt),
 				OptEventErr(err),
 				OptEventElapsed(elapsed)),
 			)
@@ -606,12 +1046,36 @@ func (js *JobScheduler) onJobCompleteError(ctx context.Context, err error) {
 	if lifecycle.OnComplete != nil {
 		lifecycle.OnComplete(ctx)
 	}
+	js.onComplete(ctx)
 }
 
 //
 // logging helpers
 //
 
+// onComplete calls the complete handler, if one is set, with the current invocation.
+func (js *JobScheduler) onComplete(ctx context.Context) {
+	if js.completeHandler == nil {
+		return
+	}
+	if current := js.Current(); current != nil {
+		js.completeHandler(ctx, current)
+	}
+}
+
+// onDependencySkipped logs that the job was skipped because a dependency failed, and reports a
+// skipped invocation to the complete handler so the failure policy cascades to dependent jobs.
+func (js *JobScheduler) onDependencySkipped(ctx context.Context) {
+	if js.Log != nil && !js.Config().SkipLoggerTrigger {
+		js.logTrigger(ctx, NewEvent(FlagDependencySkipped, js.Name()))
+	}
+	if js.completeHandler != nil {
+		ji := NewJobInvocation(js.Name())
+		ji.Status = JobInvocationStatusSkipped
+		js.completeHandler(ctx, ji)
+	}
+}
+
 func (js *JobScheduler) logTrigger(ctx context.Context, e logger.Event) {
 	if !logger.IsLoggerSet(js.Log) {
 		return
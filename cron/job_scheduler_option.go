@@ -30,3 +30,18 @@ func OptJobSchedulerLog(log logger.Log) JobSchedulerOption {
 func OptJobSchedulerBaseContext(ctx context.Context) JobSchedulerOption {
 	return func(js *JobScheduler) { js.BaseContext = ctx }
 }
+
+// OptJobSchedulerLocker sets the job scheduler locker.
+func OptJobSchedulerLocker(locker Locker) JobSchedulerOption {
+	return func(js *JobScheduler) { js.Locker = locker }
+}
+
+// OptJobSchedulerLastFiredStore sets the job scheduler last fired store.
+func OptJobSchedulerLastFiredStore(store LastFiredStore) JobSchedulerOption {
+	return func(js *JobScheduler) { js.LastFiredStore = store }
+}
+
+// OptJobSchedulerHistory sets the job scheduler history provider.
+func OptJobSchedulerHistory(history HistoryProvider) JobSchedulerOption {
+	return func(js *JobScheduler) { js.History = history }
+}
@@ -11,11 +11,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/zpkg/blend-go-sdk/assert"
 	"github.com/zpkg/blend-go-sdk/graceful"
 	"github.com/zpkg/blend-go-sdk/logger"
+	"github.com/zpkg/blend-go-sdk/retry"
 )
 
 var (
@@ -330,3 +333,504 @@ func Test_JobScheduler_onJobCompleteError(t *testing.T) {
 	its.Contains(buffer.String(), "[cron.errored]")
 	its.Contains(buffer.String(), "[cron.complete]")
 }
+
+func Test_JobScheduler_runScheduled_locker(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.Memory(
+		buffer,
+		logger.OptText(
+			logger.OptTextHideTimestamp(),
+			logger.OptTextNoColor(),
+		),
+	)
+
+	var executions int32
+	proceed := make(chan struct{})
+	job := NewJob(
+		OptJobName("test-job"),
+		OptJobConfig(JobConfig{LockAtLeastFor: time.Millisecond}),
+		OptJobAction(func(_ context.Context) error {
+			atomic.AddInt32(&executions, 1)
+			<-proceed
+			return nil
+		}),
+	)
+
+	locker := NewMemoryLocker()
+	replica0 := NewJobScheduler(job, OptJobSchedulerLocker(locker), OptJobSchedulerLog(log))
+	replica1 := NewJobScheduler(job, OptJobSchedulerLocker(locker), OptJobSchedulerLog(log))
+
+	tick := time.Date(2024, 01, 02, 03, 04, 05, 0, time.UTC)
+	key := LockKey(job.Name(), tick)
+
+	replica0.runScheduled(tick)
+	its.True(locker.IsHeld(key))
+	replica1.runScheduled(tick)
+
+	close(proceed)
+
+	// wait for the lock to be released
+	deadline := time.After(5 * time.Second)
+	for locker.IsHeld(key) {
+		select {
+		case <-deadline:
+			its.FailNow("lock should be released")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	its.Equal(1, atomic.LoadInt32(&executions))
+
+	// the next tick is a different lock
+	replica1.runScheduled(tick.Add(time.Minute))
+	for atomic.LoadInt32(&executions) < 2 {
+		select {
+		case <-deadline:
+			its.FailNow("job should run for the next tick")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	replica0.waitIdle()
+	replica1.waitIdle()
+	its.Contains(buffer.String(), "[cron.lock_held]")
+}
+
+func Test_JobScheduler_runScheduled_lockAtLeastFor(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	job := NewJob(
+		OptJobName("test-job"),
+		OptJobConfig(JobConfig{LockAtLeastFor: time.Hour}),
+	)
+	locker := NewMemoryLocker()
+	js := NewJobScheduler(job, OptJobSchedulerLocker(locker))
+
+	tick := time.Date(2024, 01, 02, 03, 04, 05, 0, time.UTC)
+	js.runScheduled(tick)
+	js.waitIdle()
+	its.True(locker.IsHeld(LockKey(job.Name(), tick)), "the lock should be held after the job completes")
+}
+
+func Test_JobScheduler_runScheduled_lockLost(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.Memory(
+		buffer,
+		logger.OptText(
+			logger.OptTextHideTimestamp(),
+			logger.OptTextNoColor(),
+		),
+	)
+
+	started := make(chan struct{})
+	job := NewJob(
+		OptJobName("test-job"),
+		OptJobAction(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		}),
+	)
+	locker := NewMemoryLocker()
+	js := NewJobScheduler(job, OptJobSchedulerLocker(locker), OptJobSchedulerLog(log))
+
+	tick := time.Date(2024, 01, 02, 03, 04, 05, 0, time.UTC)
+	js.runScheduled(tick)
+	<-started
+	locker.Lose(LockKey(job.Name(), tick))
+	js.waitIdle()
+
+	its.Equal(JobInvocationStatusCanceled, js.Last().Status)
+	its.Contains(buffer.String(), "[cron.lock_lost]")
+	its.Contains(buffer.String(), "[cron.canceled]")
+}
+
+// waitIdle waits for the current invocation to complete, for tests.
+func (js *JobScheduler) waitIdle() {
+	for !js.IsIdle() {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_JobScheduler_history(t *testing.T) {
+	its := assert.New(t)
+
+	history := NewMemoryHistory()
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobAction(func(_ context.Context) error { return fmt.Errorf("this is only a test") }),
+			OptJobConfig(JobConfig{HistoryMaxCount: 2}),
+		),
+		OptJobSchedulerHistory(history),
+	)
+
+	for x := 0; x < 3; x++ {
+		_, _, err := js.RunAsyncContext(context.Background())
+		its.Nil(err)
+		js.waitIdle()
+	}
+
+	recorded, err := history.Last(context.Background(), "test-job", 10)
+	its.Nil(err)
+	its.Len(recorded, 2)
+	its.Equal(JobInvocationStatusErrored, recorded[0].Status)
+	its.Equal(js.Last().ID, recorded[0].ID)
+
+	failures, err := history.FailuresSince(context.Background(), "test-job", time.Time{})
+	its.Nil(err)
+	its.Len(failures, 2)
+
+	restored := NewJobScheduler(NewJob(OptJobName("test-job")), OptJobSchedulerHistory(history))
+	its.Nil(restored.Last())
+	its.Nil(restored.OnLoad(context.Background()))
+	its.NotNil(restored.Last())
+	its.Equal(recorded[0].ID, restored.Last().ID)
+	its.Equal("this is only a test", restored.Last().Err.Error())
+}
+
+func Test_JobScheduler_catchUp(t *testing.T) {
+	its := assert.New(t)
+
+	lastFired := time.Now().UTC().Add(-(5*time.Hour + 30*time.Minute))
+	testCases := [...]struct {
+		Policy   MisfirePolicy
+		MaxRuns  int
+		Expected int32
+		LastTick time.Time
+	}{
+		{Policy: "", Expected: 0, LastTick: lastFired},
+		{Policy: MisfirePolicySkip, Expected: 0, LastTick: lastFired},
+		{Policy: MisfirePolicyRunOnce, Expected: 1, LastTick: lastFired.Add(5 * time.Hour)},
+		{Policy: MisfirePolicyRunAll, Expected: 5, LastTick: lastFired.Add(5 * time.Hour)},
+		{Policy: MisfirePolicyRunAll, MaxRuns: 3, Expected: 3, LastTick: lastFired.Add(5 * time.Hour)},
+	}
+
+	for _, tc := range testCases {
+		var runs int32
+		store := NewMemoryLastFiredStore()
+		its.Nil(store.SetLastFired(context.Background(), "test-job", lastFired))
+		js := NewJobScheduler(
+			NewJob(
+				OptJobName("test-job"),
+				OptJobSchedule(EveryHour()),
+				OptJobMisfirePolicy(tc.Policy),
+				OptJobMisfireMaxRuns(tc.MaxRuns),
+				OptJobAction(func(_ context.Context) error {
+					atomic.AddInt32(&runs, 1)
+					return nil
+				}),
+			),
+			OptJobSchedulerLastFiredStore(store),
+		)
+		js.catchUp()
+		js.waitIdle()
+
+		its.Equal(tc.Expected, atomic.LoadInt32(&runs), string(tc.Policy))
+		tick, err := store.LastFired(context.Background(), "test-job")
+		its.Nil(err)
+		its.Equal(tc.LastTick, tick, string(tc.Policy))
+	}
+}
+
+func Test_JobScheduler_missedTicks(t *testing.T) {
+	its := assert.New(t)
+
+	js := NewJobScheduler(NewJob(OptJobName("test-job"), OptJobSchedule(mustParseSchedule("* * * * * * *"))))
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+
+	missed, total, more := js.missedTicks(now.Add(-5*time.Second), now, 3)
+	its.False(more)
+	its.Equal(5, total)
+	its.Equal([]time.Time{now.Add(-2 * time.Second), now.Add(-time.Second), now}, missed)
+
+	missed, total, more = js.missedTicks(now.Add(-7*24*time.Hour), now, 3)
+	its.True(more)
+	its.True(total >= maxCountedMissedTicks/2)
+	its.Equal([]time.Time{now.Add(-2 * time.Second), now.Add(-time.Second), now}, missed)
+}
+
+func Test_JobScheduler_catchUp_neverFired(t *testing.T) {
+	its := assert.New(t)
+
+	var runs int32
+	store := NewMemoryLastFiredStore()
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobSchedule(EveryHour()),
+			OptJobMisfirePolicy(MisfirePolicyRunAll),
+			OptJobAction(func(_ context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}),
+		),
+		OptJobSchedulerLastFiredStore(store),
+	)
+	js.catchUp()
+	js.waitIdle()
+	its.Zero(atomic.LoadInt32(&runs))
+}
+
+func Test_JobScheduler_runScheduled_lastFired(t *testing.T) {
+	its := assert.New(t)
+
+	store := NewMemoryLastFiredStore()
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobAction(func(_ context.Context) error { return nil }),
+		),
+		OptJobSchedulerLastFiredStore(store),
+	)
+
+	tick := time.Date(2022, 01, 02, 03, 04, 05, 0, time.UTC)
+	done := js.runScheduled(tick)
+	its.NotNil(done)
+	<-done
+
+	lastFired, err := store.LastFired(context.Background(), "test-job")
+	its.Nil(err)
+	its.Equal(tick, lastFired)
+}
+
+func Test_JobScheduler_onTick_overlapPolicy(t *testing.T) {
+	t.Parallel()
+
+	tick := time.Date(2024, 01, 02, 03, 04, 05, 0, time.UTC)
+	testCases := [...]struct {
+		Policy     OverlapPolicy
+		Executions int32
+		Flags      []string
+		Canceled   bool
+	}{
+		{Policy: "", Executions: 1, Flags: []string{"[cron.skipped]"}},
+		{Policy: OverlapPolicyQueue, Executions: 2, Flags: []string{"[cron.queued]", "[cron.skipped]"}},
+		{Policy: OverlapPolicyReplace, Executions: 2, Flags: []string{"[cron.replaced]"}, Canceled: true},
+	}
+
+	for _, tc := range testCases {
+		its := assert.New(t)
+
+		buffer := new(bytes.Buffer)
+		log := logger.Memory(
+			buffer,
+			logger.OptText(
+				logger.OptTextHideTimestamp(),
+				logger.OptTextNoColor(),
+			),
+		)
+
+		var executions, cancellations int32
+		proceed := make(chan struct{})
+		js := NewJobScheduler(
+			NewJob(
+				OptJobName("test-job"),
+				OptJobOverlapPolicy(tc.Policy),
+				OptJobAction(func(ctx context.Context) error {
+					atomic.AddInt32(&executions, 1)
+					select {
+					case <-proceed:
+						return nil
+					case <-ctx.Done():
+						atomic.AddInt32(&cancellations, 1)
+						return ctx.Err()
+					}
+				}),
+			),
+			OptJobSchedulerLog(log),
+		)
+
+		js.onTick(tick)
+		its.False(js.IsIdle())
+		js.onTick(tick.Add(time.Minute))
+		deadline := time.After(5 * time.Second)
+		if tc.Policy == OverlapPolicyReplace {
+			for atomic.LoadInt32(&cancellations) == 0 {
+				select {
+				case <-deadline:
+					its.FailNow("the running invocation should be canceled")
+				case <-time.After(time.Millisecond):
+				}
+			}
+		} else {
+			js.onTick(tick.Add(2 * time.Minute))
+		}
+		close(proceed)
+
+		for atomic.LoadInt32(&executions) < tc.Executions || !js.IsIdle() {
+			select {
+			case <-deadline:
+				its.FailNow("jobs should complete", string(tc.Policy))
+			case <-time.After(time.Millisecond):
+			}
+		}
+		its.Equal(tc.Executions, atomic.LoadInt32(&executions), string(tc.Policy))
+		its.Equal(tc.Canceled, atomic.LoadInt32(&cancellations) > 0, string(tc.Policy))
+		for _, flag := range tc.Flags {
+			its.Contains(buffer.String(), flag, string(tc.Policy))
+		}
+	}
+}
+
+func Test_JobScheduler_jitter(t *testing.T) {
+	its := assert.New(t)
+
+	js := NewJobScheduler(NewJob(OptJobName("test-job")))
+	its.Zero(js.jitter())
+
+	js = NewJobScheduler(NewJob(OptJobName("test-job"), OptJobJitter(time.Minute)))
+	for x := 0; x < 100; x++ {
+		jitter := js.jitter()
+		its.True(jitter >= 0 && jitter < time.Minute)
+	}
+}
+
+func Test_JobScheduler_retry(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.Memory(
+		buffer,
+		logger.OptText(
+			logger.OptTextHideTimestamp(),
+			logger.OptTextNoColor(),
+		),
+	)
+
+	var attempts int32
+	var delays []uint
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobRetry(5, func(_ context.Context, attempt uint) time.Duration {
+				delays = append(delays, attempt)
+				return time.Millisecond
+			}),
+			OptJobAction(func(ctx context.Context) error {
+				its.Equal(int(atomic.AddInt32(&attempts, 1)), GetJobInvocation(ctx).Clone().Attempt)
+				if atomic.LoadInt32(&attempts) < 3 {
+					return fmt.Errorf("this is only a test")
+				}
+				return nil
+			}),
+		),
+		OptJobSchedulerLog(log),
+	)
+
+	_, done, err := js.RunAsync()
+	its.Nil(err)
+	<-done
+	js.waitIdle()
+
+	its.Equal(3, atomic.LoadInt32(&attempts))
+	its.Equal([]uint{0, 1}, delays)
+	its.Equal(JobInvocationStatusSuccess, js.Last().Status)
+	its.Equal(3, js.Last().Attempt)
+	its.Contains(buffer.String(), "[cron.retry]  attempt 2")
+	its.Contains(buffer.String(), "[cron.retry]  attempt 3")
+	its.Contains(buffer.String(), "[cron.success]  attempt 3")
+}
+
+func Test_JobScheduler_retry_exhausted(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	var attempts int32
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobRetry(3, retry.ConstantDelay(time.Millisecond)),
+			OptJobAction(func(_ context.Context) error {
+				atomic.AddInt32(&attempts, 1)
+				return fmt.Errorf("this is only a test")
+			}),
+		),
+	)
+
+	_, done, err := js.RunAsync()
+	its.Nil(err)
+	<-done
+	js.waitIdle()
+
+	its.Equal(3, atomic.LoadInt32(&attempts))
+	its.Equal(JobInvocationStatusErrored, js.Last().Status)
+	its.Equal(3, js.Last().Attempt)
+}
+
+func Test_JobScheduler_execute_canceledDuringRetryDelay(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var attempts int32
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobRetry(3, func(_ context.Context, _ uint) time.Duration {
+				cancel() // the invocation is canceled as the delay elapses
+				return 0
+			}),
+			OptJobAction(func(_ context.Context) error {
+				atomic.AddInt32(&attempts, 1)
+				return fmt.Errorf("this is only a test")
+			}),
+		),
+	)
+
+	// the invocation is not the current invocation of the scheduler, e.g. it already completed.
+	ji := NewJobInvocation(js.Name())
+	ji.Attempt = 1
+	err := <-js.execute(ctx, ji)
+	its.True(IsJobCanceled(err))
+	its.Equal(1, atomic.LoadInt32(&attempts))
+	its.Equal(1, ji.Attempt)
+	its.Nil(js.Current())
+}
+
+func Test_JobScheduler_execute_canceledDuringAttempt(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var attempts int32
+	js := NewJobScheduler(
+		NewJob(
+			OptJobName("test-job"),
+			OptJobRetry(3, func(_ context.Context, _ uint) time.Duration { return 0 }),
+			OptJobAction(func(_ context.Context) error {
+				atomic.AddInt32(&attempts, 1)
+				cancel() // the invocation is canceled while the attempt runs
+				return fmt.Errorf("this is only a test")
+			}),
+		),
+	)
+
+	ji := NewJobInvocation(js.Name())
+	ji.Attempt = 1
+	err := <-js.execute(ctx, ji)
+	its.True(IsJobCanceled(err))
+	its.Equal(1, atomic.LoadInt32(&attempts))
+}
+
+func Test_JobScheduler_NextRuntimeUTC(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	js := NewJobScheduler(NewJob(OptJobName("test-job")))
+	its.True(js.NextRuntimeUTC().IsZero())
+
+	js.setNextRuntime(time.Date(2022, 01, 02, 03, 04, 05, 0, time.FixedZone("test", 3600)))
+	its.Equal(time.Date(2022, 01, 02, 02, 04, 05, 0, time.UTC), js.NextRuntimeUTC())
+	its.Equal(time.UTC, js.NextRuntimeUTC().Location())
+}
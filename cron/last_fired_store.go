@@ -0,0 +1,24 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"time"
+)
+
+// LastFiredStore is a type that persists the last scheduled tick each job fired for.
+//
+// When a job scheduler has a last fired store, it records each scheduled tick as it fires,
+// and on start applies the job's misfire policy to the ticks missed since the last one.
+type LastFiredStore interface {
+	// LastFired returns the last tick a job fired for, or a zero time if it has never fired.
+	LastFired(ctx context.Context, jobName string) (time.Time, error)
+	// SetLastFired records the tick a job fired for.
+	SetLastFired(ctx context.Context, jobName string, tick time.Time) error
+}
@@ -0,0 +1,132 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interface assertions.
+var (
+	_ Schedule     = (*LocationSchedule)(nil)
+	_ fmt.Stringer = (*LocationSchedule)(nil)
+)
+
+// InLocation returns a schedule that computes the next runtime
+// of a given schedule in a given location.
+func InLocation(location *time.Location, schedule Schedule) *LocationSchedule {
+	return &LocationSchedule{
+		Location: location,
+		Schedule: schedule,
+	}
+}
+
+// LocationSchedule is a schedule that computes the next runtime
+// of a wrapped schedule in a given location, regardless of the location
+// of the time it is given.
+//
+// Wall clock based schedules, i.e. string, daily and on the hour schedules,
+// handle daylight saving time transitions in the location as follows:
+//
+//   - A runtime that falls in a skipped hour fires once, the same time past the transition, e.g. 02:30 becomes 03:30 when clocks go from 02:00 to 03:00.
+//   - A runtime that falls in a repeated hour fires once, at its first occurrence, e.g. 01:30 fires before clocks go from 02:00 back to 01:00.
+type LocationSchedule struct {
+	Location *time.Location
+	Schedule Schedule
+}
+
+// String returns a string representation of the schedule.
+func (ls *LocationSchedule) String() string {
+	return fmt.Sprintf("%s=%s %v", StringScheduleTimezone, ls.LocationOrDefault().String(), ls.Schedule)
+}
+
+// LocationOrDefault returns the location or UTC.
+func (ls *LocationSchedule) LocationOrDefault() *time.Location {
+	if ls.Location != nil {
+		return ls.Location
+	}
+	return time.UTC
+}
+
+// Next implements Schedule.
+func (ls *LocationSchedule) Next(after time.Time) time.Time {
+	if ls.Schedule == nil {
+		return Zero
+	}
+	return ls.Schedule.Next(after.In(ls.LocationOrDefault()))
+}
+
+//
+// wall clock helpers
+//
+
+// maxWallClockAttempts is the number of wall clock times a schedule
+// will try to map to an instant before giving up.
+const maxWallClockAttempts = 8
+
+// toWallClock returns a time in UTC that has the same wall clock
+// reading as a given time in its location.
+func toWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fromWallClock returns the earliest instant after a given time whose
+// wall clock reading in a given location matches a wall clock time
+// as returned by `toWallClock`.
+//
+// If the wall clock time is skipped in the location by a transition, the instant
+// is moved past the transition by the length of the transition.
+//
+// It returns a zero time if no matching instant is after the given time.
+func fromWallClock(wall time.Time, location *time.Location, after time.Time) time.Time {
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(location).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(location).Zone()
+
+	candidates := []time.Time{
+		wall.Add(-time.Duration(offsetBefore) * time.Second).In(location),
+		wall.Add(-time.Duration(offsetAfter) * time.Second).In(location),
+	}
+	if candidates[1].Before(candidates[0]) {
+		candidates[0], candidates[1] = candidates[1], candidates[0]
+	}
+
+	var isSkipped = true
+	for _, candidate := range candidates {
+		if !toWallClock(candidate).Equal(wall) {
+			continue
+		}
+		isSkipped = false
+		if candidate.After(after) {
+			return candidate
+		}
+	}
+	if isSkipped {
+		if shifted := wall.Add(-time.Duration(offsetBefore) * time.Second).In(location); shifted.After(after) {
+			return shifted
+		}
+	}
+	return Zero
+}
+
+// nextWallClock returns the next instant after a given time by searching
+// wall clock times in its location with a given function.
+func nextWallClock(after time.Time, next func(time.Time) time.Time) time.Time {
+	location := after.Location()
+	wall := toWallClock(after)
+	for attempt := 0; attempt < maxWallClockAttempts; attempt++ {
+		wall = next(wall)
+		if wall.IsZero() {
+			return Zero
+		}
+		if instant := fromWallClock(wall, location, after); !instant.IsZero() {
+			return instant
+		}
+	}
+	return Zero
+}
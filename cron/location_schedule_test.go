@@ -0,0 +1,169 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// NOTE: in 2024 new york clocks went from 02:00 EST to 03:00 EDT on march 10th,
+// and from 02:00 EDT back to 01:00 EST on november 3rd.
+var newYork = mustLoadLocation("America/New_York")
+
+type locationScheduleTestCase struct {
+	Name     string
+	Schedule Schedule
+	After    time.Time
+	Expected time.Time
+}
+
+func mustParseSchedule(cronString string) Schedule {
+	schedule, err := ParseSchedule(cronString)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func Test_LocationSchedule_daylightSavingTime(t *testing.T) {
+	its := assert.New(t)
+
+	testCases := []locationScheduleTestCase{
+		// string schedules
+		{Name: "string; skipped hour is shifted", Schedule: mustParseSchedule("0 30 2 * * *"), After: time.Date(2024, 03, 9, 3, 0, 0, 0, newYork), Expected: time.Date(2024, 03, 10, 7, 30, 0, 0, time.UTC)},
+		{Name: "string; after skipped hour", Schedule: mustParseSchedule("0 30 2 * * *"), After: time.Date(2024, 03, 10, 7, 30, 0, 0, time.UTC), Expected: time.Date(2024, 03, 11, 6, 30, 0, 0, time.UTC)},
+		{Name: "string; repeated hour fires first", Schedule: mustParseSchedule("0 30 1 * * *"), After: time.Date(2024, 11, 2, 12, 0, 0, 0, newYork), Expected: time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC)},
+		{Name: "string; repeated hour fires once", Schedule: mustParseSchedule("0 30 1 * * *"), After: time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), Expected: time.Date(2024, 11, 4, 6, 30, 0, 0, time.UTC)},
+		{Name: "string; started in second pass of repeated hour", Schedule: mustParseSchedule("0 30 1 * * *"), After: time.Date(2024, 11, 3, 6, 10, 0, 0, time.UTC), Expected: time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC)},
+		{Name: "string; hourly before spring forward", Schedule: mustParseSchedule("@hourly"), After: time.Date(2024, 03, 10, 0, 0, 0, 0, newYork), Expected: time.Date(2024, 03, 10, 6, 0, 0, 0, time.UTC)},
+		{Name: "string; hourly over spring forward", Schedule: mustParseSchedule("@hourly"), After: time.Date(2024, 03, 10, 6, 0, 0, 0, time.UTC), Expected: time.Date(2024, 03, 10, 7, 0, 0, 0, time.UTC)},
+		{Name: "string; hourly after spring forward", Schedule: mustParseSchedule("@hourly"), After: time.Date(2024, 03, 10, 7, 0, 0, 0, time.UTC), Expected: time.Date(2024, 03, 10, 8, 0, 0, 0, time.UTC)},
+		{Name: "string; hourly over fall back", Schedule: mustParseSchedule("@hourly"), After: time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC), Expected: time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC)},
+		{Name: "string; last day of month", Schedule: mustParseSchedule("0 0 18 L * ?"), After: time.Date(2024, 03, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 03, 31, 22, 0, 0, 0, time.UTC)},
+
+		// daily schedules
+		{Name: "daily; skipped hour is shifted", Schedule: DailyAtUTC(2, 30, 0), After: time.Date(2024, 03, 9, 3, 0, 0, 0, newYork), Expected: time.Date(2024, 03, 10, 7, 30, 0, 0, time.UTC)},
+		{Name: "daily; day is 23 hours long", Schedule: DailyAtUTC(9, 0, 0), After: time.Date(2024, 03, 9, 9, 0, 0, 0, newYork), Expected: time.Date(2024, 03, 10, 13, 0, 0, 0, time.UTC)},
+		{Name: "daily; day is 25 hours long", Schedule: DailyAtUTC(9, 0, 0), After: time.Date(2024, 11, 2, 9, 0, 0, 0, newYork), Expected: time.Date(2024, 11, 3, 14, 0, 0, 0, time.UTC)},
+		{Name: "daily; repeated hour fires once", Schedule: DailyAtUTC(1, 30, 0), After: time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), Expected: time.Date(2024, 11, 4, 6, 30, 0, 0, time.UTC)},
+		{Name: "weekly; monday in new york", Schedule: WeeklyAtUTC(21, 0, 0, time.Monday), After: time.Date(2024, 11, 4, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 11, 5, 2, 0, 0, 0, time.UTC)},
+
+		// on the hour schedules
+		{Name: "on the hour; skipped hour is shifted", Schedule: EveryHourAtUTC(30, 0), After: time.Date(2024, 03, 10, 1, 30, 0, 0, newYork), Expected: time.Date(2024, 03, 10, 7, 30, 0, 0, time.UTC)},
+		{Name: "on the hour; after skipped hour", Schedule: EveryHourAtUTC(30, 0), After: time.Date(2024, 03, 10, 7, 30, 0, 0, time.UTC), Expected: time.Date(2024, 03, 10, 8, 30, 0, 0, time.UTC)},
+		{Name: "on the hour; repeated hour fires once", Schedule: EveryHourAtUTC(30, 0), After: time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), Expected: time.Date(2024, 11, 3, 7, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		next := InLocation(newYork, tc.Schedule).Next(tc.After)
+		its.Equal(tc.Expected, next.UTC(), fmt.Sprintf("%s; %v vs. %v", tc.Name, tc.Expected.Format(time.RFC3339), next.UTC().Format(time.RFC3339)))
+		its.Equal(newYork, next.Location(), tc.Name)
+	}
+}
+
+func Test_LocationSchedule_zero(t *testing.T) {
+	its := assert.New(t)
+
+	next := InLocation(newYork, DailyAtUTC(12, 0, 0)).Next(Zero)
+	its.False(next.IsZero())
+	its.Equal(newYork, next.Location())
+	its.Equal(12, next.Hour())
+	its.True(next.After(Now()))
+
+	its.True(InLocation(newYork, nil).Next(Now()).IsZero())
+	its.True(InLocation(newYork, Never()).Next(Now()).IsZero())
+}
+
+func Test_LocationSchedule_String(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal("CRON_TZ=America/New_York @every 1h0m0s", InLocation(newYork, Every(time.Hour)).String())
+	its.Equal("CRON_TZ=UTC @every 1h0m0s", InLocation(nil, Every(time.Hour)).String())
+}
+
+func Test_ParseSchedule_timezone(t *testing.T) {
+	its := assert.New(t)
+
+	schedule, err := ParseSchedule("CRON_TZ=America/New_York 0 0 9 * * MON-FRI")
+	its.Nil(err)
+	typed, ok := schedule.(*LocationSchedule)
+	its.True(ok)
+	its.Equal(newYork, typed.Location)
+	its.Equal("CRON_TZ=America/New_York 0 0 9 * * MON-FRI", typed.String())
+
+	next := schedule.Next(time.Date(2024, 11, 1, 15, 0, 0, 0, time.UTC))
+	its.Equal(time.Date(2024, 11, 4, 14, 0, 0, 0, time.UTC), next.UTC())
+
+	schedule, err = ParseSchedule("@immediately-then CRON_TZ=America/New_York @daily")
+	its.NotNil(err)
+	its.Nil(schedule)
+
+	schedule, err = ParseSchedule("CRON_TZ=America/New_York @daily")
+	its.Nil(err)
+	next = schedule.Next(time.Date(2024, 11, 1, 15, 0, 0, 0, time.UTC))
+	its.Equal(time.Date(2024, 11, 2, 4, 0, 0, 0, time.UTC), next.UTC())
+
+	schedule, err = ParseSchedule("CRON_TZ=Not/A_Zone 0 0 9 * * MON-FRI")
+	its.True(ex.Is(err, ErrStringScheduleInvalid))
+	its.Nil(schedule)
+}
+
+func Test_JobScheduler_next_timeZone(t *testing.T) {
+	its := assert.New(t)
+
+	js := NewJobScheduler(NewJob(
+		OptJobName("time-zone-test"),
+		OptJobSchedule(DailyAtUTC(9, 0, 0)),
+		OptJobTimeZone("America/New_York"),
+	))
+	next := js.next(time.Date(2024, 01, 02, 0, 0, 0, 0, time.UTC))
+	its.Equal(time.Date(2024, 01, 02, 14, 0, 0, 0, time.UTC), next.UTC())
+
+	// the schedule's own time zone wins
+	js = NewJobScheduler(NewJob(
+		OptJobName("time-zone-test"),
+		OptJobSchedule(mustParseSchedule("CRON_TZ=UTC 0 0 9 * * *")),
+		OptJobTimeZone("America/New_York"),
+	))
+	next = js.next(time.Date(2024, 01, 02, 0, 0, 0, 0, time.UTC))
+	its.Equal(time.Date(2024, 01, 02, 9, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func Test_JobManager_LoadJobs_invalidTimeZone(t *testing.T) {
+	its := assert.New(t)
+
+	jm := New()
+	err := jm.LoadJobs(NewJob(OptJobName("time-zone-test"), OptJobTimeZone("Not/A_Zone")))
+	its.True(ex.Is(err, ErrJobTimeZoneInvalid))
+	its.False(jm.HasJob("time-zone-test"))
+}
+
+func Test_JobScheduler_OnLoad_location(t *testing.T) {
+	its := assert.New(t)
+
+	js := NewJobScheduler(NewJob(OptJobName("time-zone-test"), OptJobTimeZone("America/New_York")))
+	its.Nil(js.OnLoad(context.Background()))
+	its.Equal("America/New_York", js.location().String())
+
+	js = NewJobScheduler(NewJob(OptJobName("time-zone-test"), OptJobTimeZone("Not/A_Zone")))
+	its.True(ex.Is(js.OnLoad(context.Background()), ErrJobTimeZoneInvalid))
+}
@@ -0,0 +1,40 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Locker is a type that provides exclusive locks shared across processes,
+// e.g. across the replicas of a service.
+//
+// When a job scheduler has a locker, it acquires a lock for each scheduled
+// tick before it runs the job, and skips the tick if the lock is held elsewhere.
+// This means a job runs once per tick across every process that shares the locker,
+// provided their schedules produce the same ticks, i.e. they are wall clock based.
+type Locker interface {
+	// Lock acquires the lock for a given key.
+	// It should return an error of class `ErrJobLockHeld` if the lock is held elsewhere.
+	Lock(ctx context.Context, key string) (Lease, error)
+}
+
+// Lease is a lock acquired from a Locker.
+type Lease interface {
+	// Lost returns a channel that is closed if the lock is lost before it is released.
+	Lost() <-chan struct{}
+	// Release releases the lock.
+	Release(ctx context.Context) error
+}
+
+// LockKey returns the lock key for a job's scheduled tick.
+func LockKey(jobName string, tick time.Time) string {
+	return fmt.Sprintf("%s@%d", jobName, tick.UTC().Unix())
+}
@@ -0,0 +1,103 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Interface assertions.
+var (
+	_ HistoryProvider = (*MemoryHistory)(nil)
+)
+
+// NewMemoryHistory returns a new memory history.
+func NewMemoryHistory() *MemoryHistory {
+	return &MemoryHistory{
+		Invocations: make(map[string][]JobInvocation),
+	}
+}
+
+// MemoryHistory is a history provider that holds invocations in memory.
+//
+// It is useful for tests; invocations are lost when the process exits.
+type MemoryHistory struct {
+	sync.Mutex
+	Invocations map[string][]JobInvocation
+}
+
+// Record implements HistoryProvider.
+func (mh *MemoryHistory) Record(_ context.Context, ji JobInvocation) error {
+	mh.Lock()
+	defer mh.Unlock()
+
+	invocations := mh.Invocations[ji.JobName]
+	for index := range invocations {
+		if invocations[index].ID == ji.ID {
+			invocations[index] = ji
+			return nil
+		}
+	}
+	invocations = append(invocations, ji)
+	sort.SliceStable(invocations, func(i, j int) bool {
+		return invocations[i].Started.After(invocations[j].Started)
+	})
+	mh.Invocations[ji.JobName] = invocations
+	return nil
+}
+
+// Last implements HistoryProvider.
+func (mh *MemoryHistory) Last(_ context.Context, jobName string, count int) ([]JobInvocation, error) {
+	mh.Lock()
+	defer mh.Unlock()
+
+	invocations := mh.Invocations[jobName]
+	if count < len(invocations) {
+		invocations = invocations[:count]
+	}
+	return append([]JobInvocation(nil), invocations...), nil
+}
+
+// FailuresSince implements HistoryProvider.
+func (mh *MemoryHistory) FailuresSince(_ context.Context, jobName string, since time.Time) (output []JobInvocation, err error) {
+	mh.Lock()
+	defer mh.Unlock()
+
+	for _, ji := range mh.Invocations[jobName] {
+		if ji.Status == JobInvocationStatusErrored && !ji.Started.Before(since) {
+			output = append(output, ji)
+		}
+	}
+	return
+}
+
+// Cull implements HistoryProvider.
+func (mh *MemoryHistory) Cull(_ context.Context, jobName string, maxCount int, maxAge time.Duration) error {
+	mh.Lock()
+	defer mh.Unlock()
+
+	invocations := mh.Invocations[jobName]
+	if maxCount > 0 && len(invocations) > maxCount {
+		invocations = invocations[:maxCount]
+	}
+	if maxAge > 0 {
+		cutoff := Now().Add(-maxAge)
+		var kept []JobInvocation
+		for _, ji := range invocations {
+			if !ji.Started.Before(cutoff) {
+				kept = append(kept, ji)
+			}
+		}
+		invocations = kept
+	}
+	mh.Invocations[jobName] = invocations
+	return nil
+}
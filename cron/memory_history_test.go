@@ -0,0 +1,70 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+)
+
+func Test_MemoryHistory(t *testing.T) {
+	its := assert.New(t)
+
+	ctx := context.Background()
+	history := NewMemoryHistory()
+
+	now := time.Now().UTC()
+	for x := 0; x < 5; x++ {
+		status := JobInvocationStatusSuccess
+		var err error
+		if x%2 == 0 {
+			status = JobInvocationStatusErrored
+			err = fmt.Errorf("failure %d", x)
+		}
+		its.Nil(history.Record(ctx, JobInvocation{
+			ID:       fmt.Sprintf("invocation-%d", x),
+			JobName:  "test",
+			Started:  now.Add(time.Duration(x-5) * time.Hour),
+			Complete: now.Add(time.Duration(x-5)*time.Hour + time.Minute),
+			Status:   status,
+			Err:      err,
+		}))
+	}
+	its.Nil(history.Record(ctx, JobInvocation{ID: "other", JobName: "other", Started: now}))
+
+	last, err := history.Last(ctx, "test", 2)
+	its.Nil(err)
+	its.Len(last, 2)
+	its.Equal("invocation-4", last[0].ID)
+	its.Equal("invocation-3", last[1].ID)
+
+	failures, err := history.FailuresSince(ctx, "test", now.Add(-4*time.Hour))
+	its.Nil(err)
+	its.Len(failures, 2)
+	its.Equal("invocation-4", failures[0].ID)
+	its.Equal("invocation-2", failures[1].ID)
+
+	its.Nil(history.Cull(ctx, "test", 4, 0))
+	last, err = history.Last(ctx, "test", 10)
+	its.Nil(err)
+	its.Len(last, 4)
+
+	its.Nil(history.Cull(ctx, "test", 0, 150*time.Minute))
+	last, err = history.Last(ctx, "test", 10)
+	its.Nil(err)
+	its.Len(last, 2)
+	its.Equal("invocation-4", last[0].ID)
+
+	last, err = history.Last(ctx, "other", 10)
+	its.Nil(err)
+	its.Len(last, 1, "culling should not affect other jobs")
+}
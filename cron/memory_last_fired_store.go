@@ -0,0 +1,51 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Interface assertions.
+var (
+	_ LastFiredStore = (*MemoryLastFiredStore)(nil)
+)
+
+// NewMemoryLastFiredStore returns a new memory last fired store.
+func NewMemoryLastFiredStore() *MemoryLastFiredStore {
+	return &MemoryLastFiredStore{
+		Ticks: make(map[string]time.Time),
+	}
+}
+
+// MemoryLastFiredStore is a last fired store that holds ticks in memory.
+//
+// It is useful for tests; ticks are lost when the process exits.
+type MemoryLastFiredStore struct {
+	sync.Mutex
+	Ticks map[string]time.Time
+}
+
+// LastFired implements LastFiredStore.
+func (mlfs *MemoryLastFiredStore) LastFired(_ context.Context, jobName string) (time.Time, error) {
+	mlfs.Lock()
+	defer mlfs.Unlock()
+	return mlfs.Ticks[jobName], nil
+}
+
+// SetLastFired implements LastFiredStore.
+func (mlfs *MemoryLastFiredStore) SetLastFired(_ context.Context, jobName string, tick time.Time) error {
+	mlfs.Lock()
+	defer mlfs.Unlock()
+	if tick.After(mlfs.Ticks[jobName]) {
+		mlfs.Ticks[jobName] = tick
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// Interface assertions.
+var (
+	_ Locker = (*MemoryLocker)(nil)
+	_ Lease  = (*MemoryLease)(nil)
+)
+
+// NewMemoryLocker returns a new memory locker.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		Leases: make(map[string]*MemoryLease),
+	}
+}
+
+// MemoryLocker is a locker that holds locks in memory.
+//
+// It is useful for tests, where job managers that share a memory locker
+// stand in for processes that share a database.
+type MemoryLocker struct {
+	mu     sync.Mutex
+	Leases map[string]*MemoryLease
+}
+
+// Lock implements Locker.
+func (ml *MemoryLocker) Lock(_ context.Context, key string) (Lease, error) {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	if _, ok := ml.Leases[key]; ok {
+		return nil, ex.New(ErrJobLockHeld, ex.OptMessagef("key: %s", key))
+	}
+	lease := &MemoryLease{
+		Key:    key,
+		Locker: ml,
+		lost:   make(chan struct{}),
+	}
+	ml.Leases[key] = lease
+	return lease, nil
+}
+
+// IsHeld returns if the lock for a given key is held.
+func (ml *MemoryLocker) IsHeld(key string) (isHeld bool) {
+	ml.mu.Lock()
+	_, isHeld = ml.Leases[key]
+	ml.mu.Unlock()
+	return
+}
+
+// Lose removes the lock for a given key as if it was lost, notifying its holder.
+func (ml *MemoryLocker) Lose(key string) {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	if lease, ok := ml.Leases[key]; ok {
+		close(lease.lost)
+		delete(ml.Leases, key)
+	}
+}
+
+// MemoryLease is a lock acquired from a MemoryLocker.
+type MemoryLease struct {
+	Key    string
+	Locker *MemoryLocker
+	lost   chan struct{}
+}
+
+// Lost implements Lease.
+func (ml *MemoryLease) Lost() <-chan struct{} {
+	return ml.lost
+}
+
+// Release implements Lease.
+func (ml *MemoryLease) Release(_ context.Context) error {
+	ml.Locker.mu.Lock()
+	defer ml.Locker.mu.Unlock()
+	if lease, ok := ml.Locker.Leases[ml.Key]; ok && lease == ml {
+		delete(ml.Locker.Leases, ml.Key)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+)
+
+func Test_MemoryLocker(t *testing.T) {
+	its := assert.New(t)
+
+	ctx := context.Background()
+	locker := NewMemoryLocker()
+
+	lease, err := locker.Lock(ctx, "test-key")
+	its.Nil(err)
+	its.NotNil(lease)
+	its.True(locker.IsHeld("test-key"))
+
+	held, err := locker.Lock(ctx, "test-key")
+	its.True(IsJobLockHeld(err))
+	its.Nil(held)
+
+	other, err := locker.Lock(ctx, "other-key")
+	its.Nil(err)
+	its.NotNil(other)
+
+	its.Nil(lease.Release(ctx))
+	its.False(locker.IsHeld("test-key"))
+	its.True(locker.IsHeld("other-key"))
+
+	relocked, err := locker.Lock(ctx, "test-key")
+	its.Nil(err)
+
+	// releasing a stale lease doesn't release the new holder's lock
+	its.Nil(lease.Release(ctx))
+	its.True(locker.IsHeld("test-key"))
+
+	locker.Lose("test-key")
+	its.False(locker.IsHeld("test-key"))
+	select {
+	case <-relocked.Lost():
+	default:
+		its.Fail("lease should be lost")
+	}
+}
+
+func Test_LockKey(t *testing.T) {
+	its := assert.New(t)
+
+	tick := time.Date(2024, 01, 02, 03, 04, 05, 0, time.UTC)
+	its.Equal("test-job@1704164645", LockKey("test-job", tick))
+	its.Equal(LockKey("test-job", tick), LockKey("test-job", tick.In(time.FixedZone("test", 3600))))
+	its.NotEqual(LockKey("test-job", tick), LockKey("test-job", tick.Add(time.Second)))
+}
@@ -39,19 +39,20 @@ func (o OnTheHourAtUTCSchedule) String() string {
 }
 
 // Next implements the chronometer Schedule api.
+//
+// The minute and second are compared with the wall clock of the location of the given time.
+// See `LocationSchedule` for how daylight saving time transitions are handled.
 func (o OnTheHourAtUTCSchedule) Next(after time.Time) time.Time {
-	var returnValue time.Time
-	now := Now()
 	if after.IsZero() {
-		returnValue = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(now) {
-			returnValue = returnValue.Add(time.Hour)
-		}
-	} else {
-		returnValue = time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(after) {
-			returnValue = returnValue.Add(time.Hour)
-		}
+		after = Now().In(after.Location())
+	}
+	return nextWallClock(after, o.nextWallClock)
+}
+
+func (o OnTheHourAtUTCSchedule) nextWallClock(after time.Time) time.Time {
+	returnValue := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), o.Minute, o.Second, 0, time.UTC)
+	if !returnValue.After(after) {
+		returnValue = returnValue.Add(time.Hour)
 	}
 	return returnValue
 }
@@ -39,11 +39,23 @@ The full list of possible field values:
 	Seconds        No           0-59              * / , -
 	Minutes        Yes          0-59              * / , -
 	Hours          Yes          0-23              * / , -
-	Day of month   Yes          1-31              * / , - L W
+	Day of month   Yes          1-31              * / , - ? L W
 	Month          Yes          1-12 or JAN-DEC   * / , -
-	Day of week    Yes          0-6 or SUN-SAT    * / , - L #
+	Day of week    Yes          0-6 or SUN-SAT    * / , - ? L #
 	Year           No           1970–2099         * / , -
 
+The special characters for the day of month and day of week fields are:
+
+	"?" is equivalent to "*", and is typically used to leave one of the two day fields unset.
+	"L" in the day of month field is the last day of the month, e.g. the 31st of January, or the 29th of February in a leap year.
+	"LW" in the day of month field is the last weekday (monday to friday) of the month.
+	"15W" in the day of month field is the weekday nearest to the 15th of the month, without crossing into another month.
+	"L" alone in the day of week field is the last day of the week, i.e. saturday.
+	"5L" or "FRIL" in the day of week field is the last friday of the month.
+	"1#3" or "MON#3" in the day of week field is the third monday of the month.
+
+If both day fields are set, a day must match both of them.
+
 You can also use shorthands:
 
 	"@yearly" is equivalent to "0 0 0 1 1 * *"
@@ -56,10 +68,28 @@ You can also use shorthands:
 	"@once-at 2021-06-05 13:04" is "cron.OnceAtUTC(time.Date(...))"
 	"@never" is equivalent to an unset schedule (i.e., only on demand) to avoid defaults
 
+Cron-like strings and their shorthands can be prefixed with a time zone, in which case the
+schedule is computed in that zone (see `LocationSchedule`) regardless of the zone of the job:
+
+	"CRON_TZ=America/New_York 0 0 9 * * MON-FRI" is 9am new york time every weekday
+
 */
 func ParseSchedule(cronString string) (schedule Schedule, err error) {
 	cronString = strings.TrimSpace(cronString)
 
+	// check for "CRON_TZ=<location>"
+	var location *time.Location
+	if strings.HasPrefix(cronString, StringScheduleTimezone+"=") {
+		locationPart := strings.Fields(cronString)[0]
+		location, err = time.LoadLocation(strings.TrimPrefix(locationPart, StringScheduleTimezone+"="))
+		if err != nil {
+			err = ex.New(ErrStringScheduleInvalid, ex.OptInner(err))
+			return
+		}
+		cronString = strings.TrimPrefix(cronString, locationPart)
+		cronString = strings.TrimSpace(cronString)
+	}
+
 	// check for "@never"
 	if cronString == StringScheduleNever {
 		schedule = Never()
@@ -186,36 +216,35 @@ func ParseSchedule(cronString string) (schedule Schedule, err error) {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("hours invalid"))
 	}
 
-	days, err := parsePart(parts[3], parseInt, between(1, 32))
-	if err != nil {
-		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("days invalid"))
-	}
-
 	months, err := parsePart(parts[4], parseMonth, between(1, 13))
 	if err != nil {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("months invalid"))
 	}
 
-	daysOfWeek, err := parsePart(parts[5], parseDayOfWeek, between(0, 7))
-	if err != nil {
-		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("days of week invalid"))
-	}
-
 	years, err := parsePart(parts[6], parseInt, between(1970, 2100))
 	if err != nil {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("years invalid"))
 	}
 
-	schedule = &StringSchedule{
-		Original:    cronString,
-		Seconds:     seconds,
-		Minutes:     minutes,
-		Hours:       hours,
-		DaysOfMonth: days,
-		Months:      months,
-		DaysOfWeek:  daysOfWeek,
-		Years:       years,
+	stringSchedule := &StringSchedule{
+		Original: cronString,
+		Seconds:  seconds,
+		Minutes:  minutes,
+		Hours:    hours,
+		Months:   months,
+		Years:    years,
 	}
+	if err = parseDaysOfMonth(parts[3], stringSchedule); err != nil {
+		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("days invalid"))
+	}
+	if err = parseDaysOfWeek(parts[5], stringSchedule); err != nil {
+		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("days of week invalid"))
+	}
+	if location != nil {
+		schedule = InLocation(location, stringSchedule)
+		return
+	}
+	schedule = stringSchedule
 	return
 }
 
@@ -235,6 +264,7 @@ const (
 	StringScheduleEvery           = "@every"
 	StringScheduleOnceAt          = "@once-at"
 	StringScheduleNever           = "@never"
+	StringScheduleTimezone        = "CRON_TZ"
 )
 
 // String schedule shorthands labels
@@ -275,6 +305,24 @@ type StringSchedule struct {
 	Months      []int
 	DaysOfWeek  []int
 	Years       []int
+
+	// DaysOfMonthLast is set by `L` in the day of month field.
+	DaysOfMonthLast bool
+	// DaysOfMonthLastWeekday is set by `LW` in the day of month field.
+	DaysOfMonthLastWeekday bool
+	// DaysOfMonthNearestWeekday are days of the month set with `W`, e.g. `15W`.
+	DaysOfMonthNearestWeekday []int
+	// DaysOfWeekLast are days of the week set with `L`, e.g. `5L`.
+	DaysOfWeekLast []int
+	// DaysOfWeekNth are days of the week set with `#`, e.g. `MON#1`.
+	DaysOfWeekNth []DayOfWeekOccurrence
+}
+
+// DayOfWeekOccurrence is a day of the week and which occurrence
+// of that day within the month it refers to, e.g. the first monday.
+type DayOfWeekOccurrence struct {
+	DayOfWeek  int
+	Occurrence int
 }
 
 // String returns the original string schedule.
@@ -289,176 +337,227 @@ func (ss *StringSchedule) FullString() string {
 		csvOfInts(ss.Seconds, "*"),
 		csvOfInts(ss.Minutes, "*"),
 		csvOfInts(ss.Hours, "*"),
-		csvOfInts(ss.DaysOfMonth, "*"),
+		csvOfStrings(ss.daysOfMonthComponents(), "*"),
 		csvOfInts(ss.Months, "*"),
-		csvOfInts(ss.DaysOfWeek, "*"),
+		csvOfStrings(ss.daysOfWeekComponents(), "*"),
 		csvOfInts(ss.Years, "*"),
 	}
 	return strings.Join(fields, " ")
 }
 
 // Next implements cron.Schedule.
+//
+// The next runtime is computed with the wall clock of the location of the given time.
+// See `LocationSchedule` for how daylight saving time transitions are handled.
 func (ss *StringSchedule) Next(after time.Time) time.Time {
-	working := after
 	if after.IsZero() {
-		working = Now()
+		after = Now().In(after.Location())
 	}
-	original := working
+	return nextWallClock(after, ss.nextWallClock)
+}
 
+// nextWallClock returns the next wall clock time (i.e. in UTC) after a given wall clock time.
+func (ss *StringSchedule) nextWallClock(after time.Time) time.Time {
+	// the next runtime must be strictly after the given time, so start
+	// the search at the next whole second.
+	working := advanceSecond(after)
+
+	yearLimit := working.Year() + stringScheduleMaxSearchYears
 	if len(ss.Years) > 0 {
-		for _, year := range ss.Years {
-			if year >= working.Year() {
-				working = advanceYearTo(working, year)
-				break
-			}
-		}
+		yearLimit = ss.Years[len(ss.Years)-1]
 	}
 
-	if len(ss.Months) > 0 {
-		var didSet bool
-		for _, month := range ss.Months {
-			if time.Month(month) == working.Month() && working.After(original) {
-				didSet = true
-				break
-			}
-			if time.Month(month) > working.Month() {
-				working = advanceMonthTo(working, time.Month(month))
-				didSet = true
-				break
-			}
-		}
-		if !didSet {
+	// each step advances the first field that doesn't match to its next value,
+	// resetting the fields below it, and then re-checks every field from the top.
+	for working.Year() <= yearLimit {
+		if !containsOrEmpty(ss.Years, working.Year()) {
 			working = advanceYear(working)
-			for _, month := range ss.Months {
-				if time.Month(month) >= working.Month() {
-					working = advanceMonthTo(working, time.Month(month))
-					break
-				}
-			}
-		}
-	}
-
-	if len(ss.DaysOfMonth) > 0 {
-		var didSet bool
-		for _, day := range ss.DaysOfMonth {
-			if day == working.Day() && working.After(original) {
-				didSet = true
-				break
-			}
-			if day > working.Day() {
-				working = advanceDayTo(working, day)
-				didSet = true
-				break
-			}
+			continue
 		}
-		if !didSet {
+		if !containsOrEmpty(ss.Months, int(working.Month())) {
 			working = advanceMonth(working)
-			for _, day := range ss.DaysOfMonth {
-				if day >= working.Day() {
-					working = advanceDayTo(working, day)
-					break
-				}
-			}
+			continue
+		}
+		if !ss.matchesDayOfMonth(working) || !ss.matchesDayOfWeek(working) {
+			working = advanceDay(working)
+			continue
 		}
+		if !containsOrEmpty(ss.Hours, working.Hour()) {
+			working = advanceHour(working)
+			continue
+		}
+		if !containsOrEmpty(ss.Minutes, working.Minute()) {
+			working = advanceMinute(working)
+			continue
+		}
+		if !containsOrEmpty(ss.Seconds, working.Second()) {
+			working = advanceSecond(working)
+			continue
+		}
+		return working
 	}
+	return Zero
+}
 
-	if len(ss.DaysOfWeek) > 0 {
-		var didSet bool
-		for _, dow := range ss.DaysOfWeek {
-			if dow == int(working.Weekday()) && working.After(original) {
-				didSet = true
-				break
-			}
-			if dow > int(working.Weekday()) {
-				working = advanceDayBy(working, (dow - int(working.Weekday())))
-				didSet = true
-				break
-			}
+func (ss *StringSchedule) matchesDayOfMonth(t time.Time) bool {
+	if len(ss.DaysOfMonth) == 0 && !ss.DaysOfMonthLast && !ss.DaysOfMonthLastWeekday && len(ss.DaysOfMonthNearestWeekday) == 0 {
+		return true
+	}
+	day := t.Day()
+	lastDay := daysInMonth(t.Year(), t.Month())
+	if containsInt(ss.DaysOfMonth, day) {
+		return true
+	}
+	if ss.DaysOfMonthLast && day == lastDay {
+		return true
+	}
+	if ss.DaysOfMonthLastWeekday && day == nearestWeekday(t.Year(), t.Month(), lastDay) {
+		return true
+	}
+	for _, nearest := range ss.DaysOfMonthNearestWeekday {
+		if nearest > lastDay {
+			continue
 		}
-		if !didSet {
-			working = advanceToNextSunday(working)
-			for _, dow := range ss.DaysOfWeek {
-				if dow >= int(working.Weekday()) {
-					working = advanceDayBy(working, (dow - int(working.Weekday())))
-					break
-				}
-			}
+		if day == nearestWeekday(t.Year(), t.Month(), nearest) {
+			return true
 		}
 	}
+	return false
+}
 
-	if len(ss.Hours) > 0 {
-		var didSet bool
-		for _, hour := range ss.Hours {
-			if hour == working.Hour() && working.After(original) {
-				didSet = true
-				break
-			}
-			if hour > working.Hour() {
-				working = advanceHourTo(working, hour)
-				didSet = true
-				break
-			}
-		}
-		if !didSet {
-			working = advanceDay(working)
-			for _, hour := range ss.Hours {
-				if hour >= working.Hour() {
-					working = advanceHourTo(working, hour)
-					break
-				}
-			}
+func (ss *StringSchedule) matchesDayOfWeek(t time.Time) bool {
+	if len(ss.DaysOfWeek) == 0 && len(ss.DaysOfWeekLast) == 0 && len(ss.DaysOfWeekNth) == 0 {
+		return true
+	}
+	dayOfWeek := int(t.Weekday())
+	if containsInt(ss.DaysOfWeek, dayOfWeek) {
+		return true
+	}
+	if containsInt(ss.DaysOfWeekLast, dayOfWeek) && t.Day()+7 > daysInMonth(t.Year(), t.Month()) {
+		return true
+	}
+	occurrence := ((t.Day() - 1) / 7) + 1
+	for _, nth := range ss.DaysOfWeekNth {
+		if nth.DayOfWeek == dayOfWeek && nth.Occurrence == occurrence {
+			return true
 		}
 	}
+	return false
+}
 
-	if len(ss.Minutes) > 0 {
-		var didSet bool
-		for _, minute := range ss.Minutes {
-			if minute == working.Minute() && working.After(original) {
-				didSet = true
-				break
-			}
-			if minute > working.Minute() {
-				working = advanceMinuteTo(working, minute)
-				didSet = true
-				break
+func (ss *StringSchedule) daysOfMonthComponents() (output []string) {
+	for _, day := range ss.DaysOfMonth {
+		output = append(output, strconv.Itoa(day))
+	}
+	if ss.DaysOfMonthLast {
+		output = append(output, string(cronSpecialLast))
+	}
+	if ss.DaysOfMonthLastWeekday {
+		output = append(output, string(cronSpecialLast)+string(cronSpecialWeekday))
+	}
+	for _, day := range ss.DaysOfMonthNearestWeekday {
+		output = append(output, strconv.Itoa(day)+string(cronSpecialWeekday))
+	}
+	return
+}
+
+func (ss *StringSchedule) daysOfWeekComponents() (output []string) {
+	for _, dayOfWeek := range ss.DaysOfWeek {
+		output = append(output, strconv.Itoa(dayOfWeek))
+	}
+	for _, dayOfWeek := range ss.DaysOfWeekLast {
+		output = append(output, strconv.Itoa(dayOfWeek)+string(cronSpecialLast))
+	}
+	for _, nth := range ss.DaysOfWeekNth {
+		output = append(output, strconv.Itoa(nth.DayOfWeek)+string(cronSpecialNth)+strconv.Itoa(nth.Occurrence))
+	}
+	return
+}
+
+// parseDaysOfMonth parses the day of month field, pulling out the `L`, `LW` and `W`
+// components before handing the rest of the field to `parsePart`.
+func parseDaysOfMonth(values string, ss *StringSchedule) (err error) {
+	if values == string(cronSpecialQuestion) {
+		return nil
+	}
+
+	var remaining []string
+	nearest := map[int]bool{}
+	for _, component := range strings.Split(values, string(cronSpecialComma)) {
+		switch {
+		case component == string(cronSpecialLast):
+			ss.DaysOfMonthLast = true
+		case component == string(cronSpecialLast)+string(cronSpecialWeekday):
+			ss.DaysOfMonthLastWeekday = true
+		case strings.HasSuffix(component, string(cronSpecialWeekday)):
+			day, err := parseInt(strings.TrimSuffix(component, string(cronSpecialWeekday)))
+			if err != nil {
+				return ex.New(err)
 			}
-		}
-		if !didSet {
-			working = advanceHour(working)
-			for _, minute := range ss.Minutes {
-				if minute >= working.Minute() {
-					working = advanceMinuteTo(working, minute)
-					break
-				}
+			if !between(1, 32)(day) {
+				return ex.New(ErrStringScheduleValueOutOfRange, ex.OptMessagef("nearest weekday out of range (1-31): %s", component))
 			}
+			nearest[day] = true
+		default:
+			remaining = append(remaining, component)
 		}
 	}
+	if len(nearest) > 0 {
+		ss.DaysOfMonthNearestWeekday = mapKeysToArray(nearest)
+	}
+	if len(remaining) > 0 {
+		ss.DaysOfMonth, err = parsePart(strings.Join(remaining, string(cronSpecialComma)), parseInt, between(1, 32))
+	}
+	return
+}
 
-	if len(ss.Seconds) > 0 {
-		var didSet bool
-		for _, second := range ss.Seconds {
-			if second == working.Second() && working.After(original) {
-				didSet = true
-				break
+// parseDaysOfWeek parses the day of week field, pulling out the `L` and `#`
+// components before handing the rest of the field to `parsePart`.
+func parseDaysOfWeek(values string, ss *StringSchedule) (err error) {
+	if values == string(cronSpecialQuestion) {
+		return nil
+	}
+
+	var remaining []string
+	last := map[int]bool{}
+	for _, component := range strings.Split(values, string(cronSpecialComma)) {
+		switch {
+		case component == string(cronSpecialLast):
+			last[int(time.Saturday)] = true
+		case strings.HasSuffix(component, string(cronSpecialLast)):
+			dayOfWeek, err := parseDayOfWeek(strings.TrimSuffix(component, string(cronSpecialLast)))
+			if err != nil {
+				return err
 			}
-			if second > working.Second() {
-				working = advanceSecondTo(working, second)
-				didSet = true
-				break
+			last[dayOfWeek] = true
+		case strings.Contains(component, string(cronSpecialNth)):
+			nthParts := strings.Split(component, string(cronSpecialNth))
+			if len(nthParts) != 2 {
+				return ex.New(ErrStringScheduleInvalid, ex.OptMessagef("invalid day of week occurrence: %s", component))
 			}
-		}
-		if !didSet {
-			working = advanceMinute(working)
-			for _, second := range ss.Hours {
-				if second >= working.Second() {
-					working = advanceSecondTo(working, second)
-					break
-				}
+			dayOfWeek, err := parseDayOfWeek(nthParts[0])
+			if err != nil {
+				return err
 			}
+			occurrence, err := parseInt(nthParts[1])
+			if err != nil {
+				return ex.New(err)
+			}
+			if !between(1, 6)(occurrence) {
+				return ex.New(ErrStringScheduleValueOutOfRange, ex.OptMessagef("day of week occurrence out of range (1-5): %s", component))
+			}
+			ss.DaysOfWeekNth = append(ss.DaysOfWeekNth, DayOfWeekOccurrence{DayOfWeek: dayOfWeek, Occurrence: occurrence})
+		default:
+			remaining = append(remaining, component)
 		}
 	}
-
-	return working
+	if len(last) > 0 {
+		ss.DaysOfWeekLast = mapKeysToArray(last)
+	}
+	if len(remaining) > 0 {
+		ss.DaysOfWeek, err = parsePart(strings.Join(remaining, string(cronSpecialComma)), parseDayOfWeek, between(0, 7))
+	}
+	return
 }
 
 func parsePart(values string, parser func(string) (int, error), validator func(int) bool) ([]int, error) {
@@ -478,7 +577,7 @@ func parsePart(values string, parser func(string) (int, error), validator func(i
 	for x := 0; x < len(components); x++ {
 		component = components[x]
 		if strings.Contains(component, string(cronSpecialDash)) {
-			rangeValues, err := parseRange(values, parser, validator)
+			rangeValues, err := parseRange(component, parser, validator)
 			if err != nil {
 				return nil, err
 			}
@@ -494,7 +593,7 @@ func parsePart(values string, parser func(string) (int, error), validator func(i
 			return nil, ex.New(err)
 		}
 		if validator != nil && !validator(part) {
-			return nil, ex.New(err)
+			return nil, ex.New(ErrStringScheduleValueOutOfRange, ex.OptMessagef("value: %s", component))
 		}
 		output[part] = true
 	}
@@ -612,53 +711,61 @@ func advanceYear(t time.Time) time.Time {
 	return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location()).AddDate(1, 0, 0)
 }
 
-func advanceYearTo(t time.Time, year int) time.Time {
-	return time.Date(year, 1, 1, 0, 0, 0, 0, t.Location())
-}
-
 func advanceMonth(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, 1, 0)
 }
 
-func advanceMonthTo(t time.Time, month time.Month) time.Time {
-	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
-}
-
-func advanceDayTo(t time.Time, day int) time.Time {
-	return time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, t.Location())
-}
-
-func advanceToNextSunday(t time.Time) time.Time {
-	daysUntilSunday := 7 - int(t.Weekday())
-	return t.AddDate(0, 0, daysUntilSunday)
-}
-
 func advanceDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
 }
 
-func advanceDayBy(t time.Time, days int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, days)
-}
-
 func advanceHour(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(time.Hour)
 }
 
-func advanceHourTo(t time.Time, hour int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
-}
-
 func advanceMinute(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location()).Add(time.Minute)
 }
 
-func advanceMinuteTo(t time.Time, minute int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, 0, 0, t.Location())
+func advanceSecond(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location()).Add(time.Second)
+}
+
+// daysInMonth returns the number of days in a given month, accounting for leap years.
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
 
-func advanceSecondTo(t time.Time, second int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), second, 0, t.Location())
+// nearestWeekday returns the day of the month of the weekday (monday to friday)
+// nearest to a given day of the month, without leaving the month.
+func nearestWeekday(year int, month time.Month, day int) int {
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == daysInMonth(year, month) {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOrEmpty(values []int, value int) bool {
+	return len(values) == 0 || containsInt(values, value)
 }
 
 func csvOfInts(values []int, placeholder string) string {
@@ -672,23 +779,33 @@ func csvOfInts(values []int, placeholder string) string {
 	return strings.Join(valueStrings, ",")
 }
 
+func csvOfStrings(values []string, placeholder string) string {
+	if len(values) == 0 {
+		return placeholder
+	}
+	return strings.Join(values, ",")
+}
+
 // these are special characters
 const (
-	cronSpecialComma = ',' //
-	cronSpecialDash  = '-'
-	cronSpecialStar  = '*'
+	cronSpecialComma    = ',' //
+	cronSpecialDash     = '-'
+	cronSpecialStar     = '*'
+	cronSpecialQuestion = '?' // equivalent to a * for the day of month and day of week fields
+	cronSpecialLast     = 'L' // last day of the month, or last given day of the week of the month
+	cronSpecialWeekday  = 'W' // nearest weekday to the given day of the month
+	cronSpecialNth      = '#' // nth given day of the week of the month
 
 	// these are unused
 	// cronSpecialSlash = '/'
-	// cronSpecialQuestion = '?' // sometimes used as the startup time, sometimes as a *
-
-	// cronSpecialLast       = 'L'
-	// cronSpecialWeekday    = 'W' // nearest weekday to the given day of the month
-	// cronSpecialDayOfMonth = '#' //
 
 	cronSpecialEvery = "*/"
 )
 
+// stringScheduleMaxSearchYears is how many years ahead `StringSchedule.Next`
+// will search before deciding a schedule will never fire.
+const stringScheduleMaxSearchYears = 100
+
 var (
 	validMonths = map[string]int{
 		"JAN": 1,
@@ -31,6 +31,8 @@ func TestParseSchedule(t *testing.T) {
 	testCases := []stringScheduleTestCase{
 		{Input: "", ExpectedErr: ErrStringScheduleInvalid},
 		{Input: stringutil.Random(stringutil.Letters, 10), ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 99 * * *", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 60 * * * *", ExpectedErr: ErrStringScheduleInvalid},
 		{Input: "*/1 * * * * * *", After: time.Date(2018, 12, 29, 13, 12, 11, 10, time.UTC), Expected: time.Date(2018, 12, 29, 13, 12, 12, 0, time.UTC)},
 		{Input: "*/5 * * * * * *", After: time.Date(2018, 12, 29, 13, 12, 11, 0, time.UTC), Expected: time.Date(2018, 12, 29, 13, 12, 15, 0, time.UTC)},
 		{Input: "* 2 1 * * 1-6 *", After: time.Date(2019, 01, 01, 12, 0, 0, 0, time.UTC), Expected: time.Date(2019, 01, 02, 01, 02, 0, 0, time.UTC)},
@@ -130,3 +132,113 @@ func Test_ParseString_immediately(t *testing.T) {
 	next = parsed.Next(after) // should kick in real schedule
 	its.InTimeDelta(time.Date(2018, 12, 29, 13, 12, 11, 10+int(500*time.Millisecond), time.UTC), next, time.Millisecond)
 }
+
+func Test_ParseSchedule_specialCharacters(t *testing.T) {
+	its := assert.New(t)
+
+	testCases := []stringScheduleTestCase{
+		// last day of the month
+		{Input: "0 0 18 L * ? *", After: time.Date(2023, 01, 15, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 01, 31, 18, 0, 0, 0, time.UTC)},
+		{Input: "0 0 18 L * ? *", After: time.Date(2023, 01, 31, 18, 0, 0, 0, time.UTC), Expected: time.Date(2023, 02, 28, 18, 0, 0, 0, time.UTC)},
+		{Input: "0 0 18 L * ? *", After: time.Date(2024, 02, 10, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 02, 29, 18, 0, 0, 0, time.UTC)},  // leap year
+		{Input: "0 0 18 L * ? *", After: time.Date(2023, 04, 05, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 04, 30, 18, 0, 0, 0, time.UTC)},  // 30 day month
+		{Input: "0 0 18 L * ? *", After: time.Date(2023, 12, 31, 19, 0, 0, 0, time.UTC), Expected: time.Date(2024, 01, 31, 18, 0, 0, 0, time.UTC)}, // year boundary
+		{Input: "0 0 0 L 2 ? *", After: time.Date(1999, 03, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2000, 02, 29, 0, 0, 0, 0, time.UTC)},    // divisible by 400 is a leap year
+		{Input: "0 0 0 L,15 * ? *", After: time.Date(2023, 02, 16, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 02, 28, 0, 0, 0, 0, time.UTC)}, // mixed with a day
+
+		// month lengths with plain days
+		{Input: "0 0 9 31 * ? *", After: time.Date(2023, 04, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 05, 31, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 29 2 ? *", After: time.Date(2023, 03, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 02, 29, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 30 2 ? *", After: time.Date(2023, 03, 01, 0, 0, 0, 0, time.UTC), Expected: time.Time{}}, // never fires
+
+		// nearest weekday
+		{Input: "0 0 9 LW * ?", After: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 9, 29, 9, 0, 0, 0, time.UTC)},    // the 30th is a saturday
+		{Input: "0 0 9 LW * ?", After: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 12, 29, 9, 0, 0, 0, time.UTC)},  // the 31st is a sunday
+		{Input: "0 0 9 15W * ?", After: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 7, 14, 9, 0, 0, 0, time.UTC)},   // the 15th is a saturday
+		{Input: "0 0 9 15W * ?", After: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 10, 16, 9, 0, 0, 0, time.UTC)}, // the 15th is a sunday
+		{Input: "0 0 9 15W * ?", After: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 11, 15, 9, 0, 0, 0, time.UTC)}, // the 15th is a wednesday
+		{Input: "0 0 9 1W * ?", After: time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 7, 3, 9, 0, 0, 0, time.UTC)},     // the 1st is a saturday, don't go back a month
+		{Input: "0 0 9 31W * ?", After: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 12, 29, 9, 0, 0, 0, time.UTC)}, // the 31st is a sunday, don't go forward a month
+		{Input: "0 0 9 31W * ?", After: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 5, 31, 9, 0, 0, 0, time.UTC)},   // april has no 31st
+
+		// last day of the week of the month
+		{Input: "0 0 9 ? * 5L", After: time.Date(2023, 01, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 01, 27, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 ? * FRIL", After: time.Date(2023, 01, 27, 9, 0, 0, 0, time.UTC), Expected: time.Date(2023, 02, 24, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 ? * L", After: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 9, 30, 9, 0, 0, 0, time.UTC)},
+
+		// nth day of the week of the month
+		{Input: "0 0 9 ? * MON#1", After: time.Date(2023, 01, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 01, 02, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 ? * MON#1", After: time.Date(2023, 01, 02, 9, 0, 0, 0, time.UTC), Expected: time.Date(2023, 02, 06, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 ? * 1#5", After: time.Date(2024, 01, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 01, 29, 9, 0, 0, 0, time.UTC)},
+		{Input: "0 0 9 ? * 1#5", After: time.Date(2024, 01, 29, 9, 0, 0, 0, time.UTC), Expected: time.Date(2024, 04, 29, 9, 0, 0, 0, time.UTC)}, // skips months without a 5th monday
+		{Input: "0 9 * * MON#2,FRI#4", After: time.Date(2023, 01, 10, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 01, 27, 9, 0, 0, 0, time.UTC)},
+
+		// ranges mixed with values
+		{Input: "0 0 9 1-3,10 * ? *", After: time.Date(2023, 01, 04, 0, 0, 0, 0, time.UTC), Expected: time.Date(2023, 01, 10, 9, 0, 0, 0, time.UTC)},
+
+		// invalid
+		{Input: "0 0 9 32W * ?", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 9 XW * ?", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 9 ? * MON#6", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 9 ? * MON#", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 9 ? * MON#1#2", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 9 ? * XYZL", ExpectedErr: ErrStringScheduleInvalid},
+	}
+
+	for _, tc := range testCases {
+		parsed, err := ParseSchedule(tc.Input)
+		if tc.ExpectedErr != nil {
+			its.NotNil(err, tc.Input)
+			its.True(ex.Is(err, tc.ExpectedErr), tc.Input)
+			continue
+		}
+		its.Nil(err, tc.Input)
+		next := parsed.Next(tc.After)
+		its.Equal(tc.Expected, next, fmt.Sprintf("%s\n%v vs. %v", tc.Input, tc.Expected.Format(time.RFC3339), next.Format(time.RFC3339)))
+	}
+}
+
+func Test_StringSchedule_FullString(t *testing.T) {
+	its := assert.New(t)
+
+	testCases := [...]struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0 * * * *", Expected: "0 0 * * * * *"},
+		{Input: "0 0 9 ? * MON-FRI", Expected: "0 0 9 * * 1,2,3,4,5 *"},
+		{Input: "0 0 18 L * ? *", Expected: "0 0 18 L * * *"},
+		{Input: "0 0 18 1,L,LW,15W * ? *", Expected: "0 0 18 1,L,LW,15W * * *"},
+		{Input: "0 0 9 ? * MON#1,FRIL,0", Expected: "0 0 9 * * 0,5L,1#1 *"},
+		{Input: "0 0 9 ? * L", Expected: "0 0 9 * * 6L *"},
+	}
+
+	for _, tc := range testCases {
+		parsed, err := ParseSchedule(tc.Input)
+		its.Nil(err, tc.Input)
+		typed, ok := parsed.(*StringSchedule)
+		its.True(ok, tc.Input)
+		its.Equal(tc.Expected, typed.FullString())
+	}
+}
+
+func Test_daysInMonth(t *testing.T) {
+	its := assert.New(t)
+
+	testCases := [...]struct {
+		Year     int
+		Month    time.Month
+		Expected int
+	}{
+		{2023, time.January, 31},
+		{2023, time.February, 28},
+		{2024, time.February, 29},
+		{2000, time.February, 29},
+		{2100, time.February, 28},
+		{2023, time.April, 30},
+		{2023, time.December, 31},
+	}
+	for _, tc := range testCases {
+		its.Equal(tc.Expected, daysInMonth(tc.Year, tc.Month), fmt.Sprintf("%d-%d", tc.Year, tc.Month))
+	}
+}
@@ -44,6 +44,8 @@ func NewColumnFromFieldTag(field reflect.StructField) *Column {
 				col.IsReadOnly = strings.Contains(args, "readonly")
 				col.Inline = strings.Contains(args, "inline")
 				col.IsJSON = strings.Contains(args, "json")
+				col.IsVersion = strings.Contains(args, "version")
+				col.IsSoftDelete = strings.Contains(args, "deleted_at")
 			}
 		}
 		return &col
@@ -66,6 +68,11 @@ type Column struct {
 	IsReadOnly   bool
 	IsJSON       bool
 	Inline       bool
+	// IsVersion marks an integer column used for optimistic concurrency by `Update` and `Upsert`.
+	IsVersion bool
+	// IsSoftDelete marks a `time.Time` or `*time.Time` column set by `Delete` instead of deleting the row.
+	// It is not written by inserts or updates, so it must default to null in the table.
+	IsSoftDelete bool
 }
 
 // SetValue sets the field on a database mapped object to the instance of `value`.
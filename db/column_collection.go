@@ -157,26 +157,65 @@ func (cc ColumnCollection) CopyWithColumnPrefix(prefix string) *ColumnCollection
 	return NewColumnCollectionWithPrefix(prefix, cc.columns...)
 }
 
-// InsertColumns are non-auto, non-readonly columns.
+// InsertColumns are non-auto, non-readonly, non-soft delete columns.
+//
+// The soft delete column is left to the database default, i.e. null, so that a
+// zero `time.Time` is not written as a deleted time.
 func (cc *ColumnCollection) InsertColumns() *ColumnCollection {
 	if cc.insertColumns != nil {
 		return cc.insertColumns
 	}
 
-	cc.insertColumns = cc.NotReadOnly().NotAutos()
+	newCC := NewColumnCollectionWithPrefix(cc.columnPrefix)
+	for _, c := range cc.NotReadOnly().NotAutos().Columns() {
+		if !c.IsSoftDelete {
+			newCC.Add(c)
+		}
+	}
+
+	cc.insertColumns = newCC
 	return cc.insertColumns
 }
 
-// UpdateColumns are non-primary key, non-readonly columns.
+// UpdateColumns are non-primary key, non-readonly, non-version, non-soft delete columns.
+//
+// The soft delete column is only written by `Delete`.
 func (cc *ColumnCollection) UpdateColumns() *ColumnCollection {
 	if cc.updateColumns != nil {
 		return cc.updateColumns
 	}
 
-	cc.updateColumns = cc.NotReadOnly().NotPrimaryKeys()
+	newCC := NewColumnCollectionWithPrefix(cc.columnPrefix)
+	for _, c := range cc.NotReadOnly().NotPrimaryKeys().Columns() {
+		if !c.IsVersion && !c.IsSoftDelete {
+			newCC.Add(c)
+		}
+	}
+
+	cc.updateColumns = newCC
 	return cc.updateColumns
 }
 
+// VersionColumn returns the column tagged `version`, or nil.
+func (cc *ColumnCollection) VersionColumn() *Column {
+	for index := range cc.columns {
+		if cc.columns[index].IsVersion {
+			return &cc.columns[index]
+		}
+	}
+	return nil
+}
+
+// SoftDeleteColumn returns the column tagged `deleted_at`, or nil.
+func (cc *ColumnCollection) SoftDeleteColumn() *Column {
+	for index := range cc.columns {
+		if cc.columns[index].IsSoftDelete {
+			return &cc.columns[index]
+		}
+	}
+	return nil
+}
+
 // PrimaryKeys are columns we use as where predicates and can't update.
 func (cc *ColumnCollection) PrimaryKeys() *ColumnCollection {
 	if cc.primaryKeys != nil {
@@ -284,3 +284,17 @@ func Test_Column_GetValue(t *testing.T) {
 	its.NotNil(value)
 	its.Equal(5, value)
 }
+
+func Test_NewColumnFromFieldTag_versionAndSoftDelete(t *testing.T) {
+	its := assert.New(t)
+
+	cols := Columns(versionedObj{})
+	its.NotNil(cols.VersionColumn())
+	its.Equal("version", cols.VersionColumn().ColumnName)
+	its.NotNil(cols.SoftDeleteColumn())
+	its.Equal("deleted_utc", cols.SoftDeleteColumn().ColumnName)
+	its.False(cols.UpdateColumns().HasColumn("version"))
+
+	its.Nil(Columns(setValueTest{}).VersionColumn())
+	its.Nil(Columns(setValueTest{}).SoftDeleteColumn())
+}
@@ -10,6 +10,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
@@ -23,7 +24,13 @@ import (
 
 // NewConfigFromDSN creates a new config from a DSN.
 // Errors can be produced by parsing the DSN.
+//
+// Mysql DSNs, either urls with a `mysql://` scheme or in the mysql driver format,
+// e.g. `user:password@tcp(localhost:3306)/dbname`, produce a config with the mysql engine and dialect.
 func NewConfigFromDSN(dsn string) (config Config, err error) {
+	if isMySQLDSN(dsn) {
+		return newMySQLConfigFromDSN(dsn)
+	}
 	parsed, parseErr := ParseURL(dsn)
 	if parseErr != nil {
 		err = ex.New(parseErr)
@@ -73,6 +80,114 @@ func NewConfigFromDSN(dsn string) (config Config, err error) {
 	return
 }
 
+// isMySQLDSN returns if a DSN is a mysql url or in the mysql driver format.
+func isMySQLDSN(dsn string) bool {
+	if strings.HasPrefix(dsn, "mysql://") {
+		return true
+	}
+	for _, protocol := range []string{"tcp(", "unix("} {
+		if strings.HasPrefix(dsn, protocol) || strings.Contains(dsn, "@"+protocol) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMySQLConfigFromDSN creates a new config from a mysql url or a DSN in the mysql driver format.
+func newMySQLConfigFromDSN(dsn string) (config Config, err error) {
+	config.Engine = EngineMySQL
+	config.Dialect = string(DialectMySQL)
+
+	var query url.Values
+	if strings.HasPrefix(dsn, "mysql://") {
+		parsed, parseErr := url.Parse(dsn)
+		if parseErr != nil {
+			err = ex.New(parseErr)
+			return
+		}
+		if parsed.User != nil {
+			config.Username = parsed.User.Username()
+			config.Password, _ = parsed.User.Password()
+		}
+		config.Host = parsed.Hostname()
+		config.Port = parsed.Port()
+		config.Database = strings.TrimPrefix(parsed.Path, "/")
+		query = parsed.Query()
+	} else {
+		// the database name follows the last slash, and the user info precedes the last `@` before it.
+		slash := strings.LastIndex(dsn, "/")
+		if slash < 0 {
+			err = ex.New(ErrInvalidMySQLDSN, ex.OptMessage("missing database name"))
+			return
+		}
+		address := dsn[:slash]
+		if at := strings.LastIndex(address, "@"); at >= 0 {
+			userInfo := address[:at]
+			address = address[at+1:]
+			if colon := strings.Index(userInfo, ":"); colon >= 0 {
+				config.Username, config.Password = userInfo[:colon], userInfo[colon+1:]
+			} else {
+				config.Username = userInfo
+			}
+		}
+		if open := strings.Index(address, "("); open >= 0 && strings.HasSuffix(address, ")") {
+			protocol, addr := address[:open], address[open+1:len(address)-1]
+			if protocol == "unix" {
+				config.Host = addr
+			} else if host, port, splitErr := net.SplitHostPort(addr); splitErr == nil {
+				config.Host, config.Port = host, port
+			} else {
+				config.Host = addr
+			}
+		}
+		database := dsn[slash+1:]
+		if question := strings.Index(database, "?"); question >= 0 {
+			var parseErr error
+			if query, parseErr = url.ParseQuery(database[question+1:]); parseErr != nil {
+				err = ex.New(parseErr)
+				return
+			}
+			database = database[:question]
+		}
+		config.Database = database
+	}
+
+	switch query.Get("tls") {
+	case "false":
+		config.SSLMode = SSLModeDisable
+	case "preferred":
+		config.SSLMode = SSLModePrefer
+	case "skip-verify":
+		config.SSLMode = SSLModeRequire
+	case "true":
+		config.SSLMode = SSLModeVerifyFull
+	}
+	if value := query.Get("timeout"); value != "" {
+		var parseErr error
+		if config.ConnectTimeout, parseErr = time.ParseDuration(value); parseErr != nil {
+			err = ex.New(parseErr, ex.OptMessage("field: timeout"))
+			return
+		}
+	}
+	if value := query.Get("innodb_lock_wait_timeout"); value != "" {
+		seconds, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			err = ex.New(parseErr, ex.OptMessage("field: innodb_lock_wait_timeout"))
+			return
+		}
+		config.LockTimeout = time.Duration(seconds) * time.Second
+	}
+	if value := query.Get("max_execution_time"); value != "" {
+		milliseconds, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			err = ex.New(parseErr, ex.OptMessage("field: max_execution_time"))
+			return
+		}
+		config.StatementTimeout = time.Duration(milliseconds) * time.Millisecond
+	}
+	return
+}
+
 // NewConfigFromEnv returns a new config from the environment.
 // The environment variable mappings are as follows:
 //  -  DB_ENGINE            = Engine
@@ -93,6 +208,9 @@ func NewConfigFromDSN(dsn string) (config Config, err error) {
 //  -  DB_MAX_LIFETIME      = MaxLifetime
 //  -  DB_BUFFER_POOL_SIZE  = BufferPoolSize
 //  -  DB_DIALECT           = Dialect
+//  -  DB_REPLICA_DSNS      = ReplicaDSNs
+//  -  DB_REPLICA_MAX_LAG   = ReplicaMaxLag
+//  -  DB_REPLICA_CHECK_INTERVAL = ReplicaCheckInterval
 func NewConfigFromEnv() (config Config, err error) {
 	if err = (&config).Resolve(env.WithVars(context.Background(), env.Env())); err != nil {
 		return
@@ -183,6 +301,14 @@ type Config struct {
 	BufferPoolSize int `json:"bufferPoolSize,omitempty" yaml:"bufferPoolSize,omitempty" env:"DB_BUFFER_POOL_SIZE"`
 	// Dialect includes hints to tweak specific sql semantics by database connection.
 	Dialect string `json:"dialect,omitempty" yaml:"dialect,omitempty" env:"DB_DIALECT"`
+	// ReplicaDSNs are fully formed DSNs of read replicas; if set, reads that are not
+	// in a transaction are served by a healthy replica rather than the primary.
+	// Read replicas are not supported for the mysql dialect.
+	ReplicaDSNs []string `json:"replicaDSNs,omitempty" yaml:"replicaDSNs,omitempty" env:"DB_REPLICA_DSNS"`
+	// ReplicaMaxLag is the maximum replication lag of a replica that serves reads.
+	ReplicaMaxLag time.Duration `json:"replicaMaxLag,omitempty" yaml:"replicaMaxLag,omitempty" env:"DB_REPLICA_MAX_LAG"`
+	// ReplicaCheckInterval is the interval between replica health and lag checks.
+	ReplicaCheckInterval time.Duration `json:"replicaCheckInterval,omitempty" yaml:"replicaCheckInterval,omitempty" env:"DB_REPLICA_CHECK_INTERVAL"`
 }
 
 // IsZero returns if the config is unset.
@@ -193,10 +319,11 @@ func (c Config) IsZero() bool {
 // Resolve applies any external data sources to the config.
 func (c *Config) Resolve(ctx context.Context) error {
 	return configutil.Resolve(ctx,
-		configutil.SetString(&c.Engine, configutil.Env(EnvVarDBEngine), configutil.String(c.Engine), configutil.String(DefaultEngine)),
+		configutil.SetString(&c.Dialect, configutil.Env(EnvVarDBDialect), configutil.String(c.Dialect), configutil.String(DialectPostgres)),
+		configutil.SetString(&c.Engine, configutil.Env(EnvVarDBEngine), configutil.String(c.Engine), configutil.StringFunc(c.resolveDefaultEngine)),
 		configutil.SetString(&c.DSN, configutil.Env(EnvVarDatabaseURL), configutil.String(c.DSN)),
 		configutil.SetString(&c.Host, configutil.Env(EnvVarDBHost), configutil.String(c.Host), configutil.String(DefaultHost)),
-		configutil.SetString(&c.Port, configutil.Env(EnvVarDBPort), configutil.String(c.Port), configutil.String(DefaultPort)),
+		configutil.SetString(&c.Port, configutil.Env(EnvVarDBPort), configutil.String(c.Port), configutil.StringFunc(c.resolveDefaultPort)),
 		configutil.SetString(&c.Database, configutil.Env(EnvVarDBName), configutil.String(c.Database), configutil.String(DefaultDatabase)),
 		configutil.SetString(&c.Schema, configutil.Env(EnvVarDBSchema), configutil.String(c.Schema)),
 		configutil.SetString(&c.ApplicationName, configutil.Env(EnvVarDBApplicationName), configutil.String(c.ApplicationName)),
@@ -211,12 +338,18 @@ func (c *Config) Resolve(ctx context.Context) error {
 		configutil.SetDuration(&c.MaxLifetime, configutil.Env(EnvVarDBMaxLifetime), configutil.Duration(c.MaxLifetime), configutil.Duration(DefaultMaxLifetime)),
 		configutil.SetDuration(&c.MaxIdleTime, configutil.Env(EnvVarDBMaxIdleTime), configutil.Duration(c.MaxIdleTime), configutil.Duration(DefaultMaxIdleTime)),
 		configutil.SetInt(&c.BufferPoolSize, configutil.Env(EnvVarDBBufferPoolSize), configutil.Int(c.BufferPoolSize), configutil.Int(DefaultBufferPoolSize)),
-		configutil.SetString(&c.Dialect, configutil.Env(EnvVarDBDialect), configutil.String(c.Dialect), configutil.String(DialectPostgres)),
+		configutil.SetStrings(&c.ReplicaDSNs, configutil.Env(EnvVarDBReplicaDSNs), configutil.Strings(c.ReplicaDSNs)),
+		configutil.SetDuration(&c.ReplicaMaxLag, configutil.Env(EnvVarDBReplicaMaxLag), configutil.Duration(c.ReplicaMaxLag), configutil.Duration(DefaultReplicaMaxLag)),
+		configutil.SetDuration(&c.ReplicaCheckInterval, configutil.Env(EnvVarDBReplicaCheckInterval), configutil.Duration(c.ReplicaCheckInterval), configutil.Duration(DefaultReplicaCheckInterval)),
 	)
 }
 
 // Reparse creates a DSN and reparses it, in case some values need to be coalesced.
+// Sqlite configs are returned as is.
 func (c Config) Reparse() (Config, error) {
+	if c.DialectOrDefault().Is(DialectSQLite) {
+		return c, nil
+	}
 	cfg, err := NewConfigFromDSN(c.CreateDSN())
 	if err != nil {
 		return Config{}, err
@@ -226,6 +359,9 @@ func (c Config) Reparse() (Config, error) {
 	cfg.MaxConnections = c.MaxConnections
 	cfg.BufferPoolSize = c.BufferPoolSize
 	cfg.MaxLifetime = c.MaxLifetime
+	cfg.ReplicaDSNs = c.ReplicaDSNs
+	cfg.ReplicaMaxLag = c.ReplicaMaxLag
+	cfg.ReplicaCheckInterval = c.ReplicaCheckInterval
 
 	return cfg, nil
 }
@@ -233,6 +369,9 @@ func (c Config) Reparse() (Config, error) {
 // MustReparse creates a DSN and reparses it, in case some values need to be coalesced,
 // and panics if there is an error.
 func (c Config) MustReparse() Config {
+	if c.DialectOrDefault().Is(DialectSQLite) {
+		return c
+	}
 	cfg, err := NewConfigFromDSN(c.CreateDSN())
 	if err != nil {
 		panic(err)
@@ -245,9 +384,24 @@ func (c Config) EngineOrDefault() string {
 	if c.Engine != "" {
 		return c.Engine
 	}
+	return c.defaultEngine()
+}
+
+// defaultEngine returns the default driver of the config dialect.
+func (c Config) defaultEngine() string {
+	if c.DialectOrDefault().Is(DialectMySQL) {
+		return EngineMySQL
+	}
 	return DefaultEngine
 }
 
+// resolveDefaultEngine is a config source for the default driver of the config dialect,
+// which must be resolved before the engine.
+func (c *Config) resolveDefaultEngine(_ context.Context) (*string, error) {
+	engine := c.defaultEngine()
+	return &engine, nil
+}
+
 // HostOrDefault returns the postgres host for the connection or a default.
 func (c Config) HostOrDefault() string {
 	if c.Host != "" {
@@ -261,9 +415,24 @@ func (c Config) PortOrDefault() string {
 	if c.Port != "" {
 		return c.Port
 	}
+	return c.defaultPort()
+}
+
+// defaultPort returns the standard port of the config dialect.
+func (c Config) defaultPort() string {
+	if c.DialectOrDefault().Is(DialectMySQL) {
+		return DefaultMySQLPort
+	}
 	return DefaultPort
 }
 
+// resolveDefaultPort is a config source for the standard port of the config dialect,
+// which must be resolved before the port.
+func (c *Config) resolveDefaultPort(_ context.Context) (*string, error) {
+	port := c.defaultPort()
+	return &port, nil
+}
+
 // DatabaseOrDefault returns the connection database or a default.
 func (c Config) DatabaseOrDefault() string {
 	if c.Database != "" {
@@ -329,11 +498,37 @@ func (c Config) DialectOrDefault() Dialect {
 	return DialectPostgres
 }
 
+// ReplicaMaxLagOrDefault returns the maximum replication lag of a replica that serves reads or a default.
+func (c Config) ReplicaMaxLagOrDefault() time.Duration {
+	if c.ReplicaMaxLag > 0 {
+		return c.ReplicaMaxLag
+	}
+	return DefaultReplicaMaxLag
+}
+
+// ReplicaCheckIntervalOrDefault returns the interval between replica health checks or a default.
+func (c Config) ReplicaCheckIntervalOrDefault() time.Duration {
+	if c.ReplicaCheckInterval > 0 {
+		return c.ReplicaCheckInterval
+	}
+	return DefaultReplicaCheckInterval
+}
+
 // CreateDSN creates a postgres connection string from the config.
+//
+// For the sqlite dialect it is the database file path, with the lock timeout as the busy timeout.
+//
+// For the mysql dialect it is in the mysql driver format, e.g. `user:password@tcp(localhost:3306)/dbname?parseTime=true`.
 func (c Config) CreateDSN() string {
+	if c.DialectOrDefault().Is(DialectMySQL) {
+		return c.createMySQLDSN()
+	}
 	if c.DSN != "" {
 		return c.DSN
 	}
+	if c.DialectOrDefault().Is(DialectSQLite) {
+		return c.createSQLiteDSN()
+	}
 
 	host := c.HostOrDefault()
 	if c.PortOrDefault() != "" {
@@ -378,9 +573,88 @@ func (c Config) CreateDSN() string {
 	return dsn.String()
 }
 
+// createSQLiteDSN creates a sqlite connection string from the config.
+func (c Config) createSQLiteDSN() string {
+	queryArgs := url.Values{}
+	queryArgs.Add("_pragma", "foreign_keys(1)")
+	if c.LockTimeout > 0 {
+		queryArgs.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", c.LockTimeout.Milliseconds()))
+	}
+	separator := "?"
+	if strings.Contains(c.Database, "?") {
+		separator = "&"
+	}
+	return c.Database + separator + queryArgs.Encode()
+}
+
+// createMySQLDSN creates a connection string in the mysql driver format from the config.
+//
+// A `mysql://` url DSN is converted to the driver format; other DSNs are returned as is.
+// Times are parsed into `time.Time`, the lock timeout is set as `innodb_lock_wait_timeout`
+// (in whole seconds), and the statement timeout as `max_execution_time`, which only applies to selects.
+// A host starting with a slash is a unix socket path.
+func (c Config) createMySQLDSN() string {
+	if c.DSN != "" {
+		if !strings.HasPrefix(c.DSN, "mysql://") {
+			return c.DSN
+		}
+		parsed, err := newMySQLConfigFromDSN(c.DSN)
+		if err != nil {
+			return c.DSN
+		}
+		return parsed.createMySQLDSN()
+	}
+
+	var dsn strings.Builder
+	if len(c.Username) > 0 {
+		dsn.WriteString(c.Username)
+		if len(c.Password) > 0 {
+			dsn.WriteString(":" + c.Password)
+		}
+		dsn.WriteString("@")
+	}
+	if host := c.HostOrDefault(); strings.HasPrefix(host, "/") {
+		dsn.WriteString("unix(" + host + ")")
+	} else {
+		dsn.WriteString("tcp(" + net.JoinHostPort(host, c.PortOrDefault()) + ")")
+	}
+	dsn.WriteString("/" + c.DatabaseOrDefault())
+
+	queryArgs := url.Values{}
+	queryArgs.Add("parseTime", "true")
+	switch c.SSLMode {
+	case SSLModeDisable:
+		queryArgs.Add("tls", "false")
+	case SSLModeAllow, SSLModePrefer:
+		queryArgs.Add("tls", "preferred")
+	case SSLModeRequire:
+		queryArgs.Add("tls", "skip-verify")
+	case SSLModeVerifyCA, SSLModeVerifyFull:
+		queryArgs.Add("tls", "true")
+	}
+	if c.ConnectTimeout > 0 {
+		queryArgs.Add("timeout", c.ConnectTimeout.String())
+	}
+	if c.LockTimeout > 0 {
+		seconds := c.LockTimeout.Round(time.Second) / time.Second
+		if seconds < 1 {
+			seconds = 1
+		}
+		queryArgs.Add("innodb_lock_wait_timeout", strconv.Itoa(int(seconds)))
+	}
+	if c.StatementTimeout > 0 {
+		queryArgs.Add("max_execution_time", strconv.Itoa(int(c.StatementTimeout.Round(time.Millisecond)/time.Millisecond)))
+	}
+	dsn.WriteString("?" + queryArgs.Encode())
+	return dsn.String()
+}
+
 // CreateLoggingDSN creates a postgres connection string from the config suitable for logging.
 // It will not include the password.
 func (c Config) CreateLoggingDSN() string {
+	if c.DialectOrDefault().Is(DialectSQLite) {
+		return c.CreateDSN()
+	}
 	if c.DSN != "" {
 		nc, err := NewConfigFromDSN(c.DSN)
 		if err != nil {
@@ -10,6 +10,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"sync/atomic"
 
 	"github.com/zpkg/blend-go-sdk/async"
 	"github.com/zpkg/blend-go-sdk/bufferutil"
@@ -68,11 +69,25 @@ type Connection struct {
 	Log                  logger.Log
 	Tracer               Tracer
 	StatementInterceptor StatementInterceptor
+	// StatementCache is an optional cache of prepared statements for labeled statements.
+	StatementCache *StatementCache
+	// Replicas are the read replica pools, opened from `Config.ReplicaDSNs`.
+	Replicas []*Replica
+
+	replicaNext uint32
 }
 
 // Close implements a closer.
 func (dbc *Connection) Close() error {
-	return dbc.Connection.Close()
+	var err error
+	if dbc.StatementCache != nil {
+		err = dbc.StatementCache.Clear()
+	}
+	err = ex.Nest(err, dbc.Connection.Close())
+	for _, replica := range dbc.Replicas {
+		err = ex.Nest(err, replica.Connection.Close())
+	}
+	return err
 }
 
 // Open returns a connection object, either a cached connection object or creating a new one in the process.
@@ -84,14 +99,20 @@ func (dbc *Connection) Open() error {
 	if dbc.Config.IsZero() {
 		return Error(ErrConfigUnset)
 	}
+	// replica lag checks are postgres specific.
+	if len(dbc.Config.ReplicaDSNs) > 0 && dbc.Config.DialectOrDefault().Is(DialectMySQL) {
+		return ex.New(ErrUnsupportedDialect, ex.OptMessage("read replicas"))
+	}
 	if dbc.BufferPool == nil {
 		dbc.BufferPool = bufferutil.NewPool(dbc.Config.BufferPoolSizeOrDefault())
 	}
 
-	dsn := dbc.Config.CreateDSN()
-	namedValues, err := ParseURL(dsn)
-	if err != nil {
-		return err
+	namedValues := dbc.Config.CreateDSN()
+	if !dbc.Config.DialectOrDefault().Is(DialectSQLite, DialectMySQL) {
+		var err error
+		if namedValues, err = ParseURL(namedValues); err != nil {
+			return err
+		}
 	}
 
 	// open the connection
@@ -105,6 +126,48 @@ func (dbc *Connection) Open() error {
 	dbc.Connection.SetConnMaxIdleTime(dbc.Config.MaxIdleTimeOrDefault())
 	dbc.Connection.SetMaxIdleConns(dbc.Config.IdleConnectionsOrDefault())
 	dbc.Connection.SetMaxOpenConns(dbc.Config.MaxConnectionsOrDefault())
+	if dbc.StatementCache != nil {
+		dbc.StatementCache.conn = dbc
+	}
+
+	for index, replicaDSN := range dbc.Config.ReplicaDSNs {
+		if err = dbc.openReplica(index, replicaDSN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openReplica opens the read replica pool for a given dsn.
+func (dbc *Connection) openReplica(index int, dsn string) error {
+	namedValues, err := ParseURL(dsn)
+	if err != nil {
+		return err
+	}
+	replicaConn, err := sql.Open(dbc.Config.EngineOrDefault(), namedValues)
+	if err != nil {
+		return Error(err)
+	}
+	replicaConn.SetConnMaxLifetime(dbc.Config.MaxLifetimeOrDefault())
+	replicaConn.SetConnMaxIdleTime(dbc.Config.MaxIdleTimeOrDefault())
+	replicaConn.SetMaxIdleConns(dbc.Config.IdleConnectionsOrDefault())
+	replicaConn.SetMaxOpenConns(dbc.Config.MaxConnectionsOrDefault())
+	dbc.Replicas = append(dbc.Replicas, NewReplica(replicaName(index), replicaConn, dbc.Config.ReplicaMaxLagOrDefault(), dbc.Config.ReplicaCheckIntervalOrDefault()))
+	return nil
+}
+
+// Replica returns the next healthy read replica in turn, or nil if there are none.
+func (dbc *Connection) Replica() *Replica {
+	if len(dbc.Replicas) == 0 {
+		return nil
+	}
+	start := int(atomic.AddUint32(&dbc.replicaNext, 1))
+	for offset := 0; offset < len(dbc.Replicas); offset++ {
+		replica := dbc.Replicas[(start+offset)%len(dbc.Replicas)]
+		if replica.Healthy() {
+			return replica
+		}
+	}
 	return nil
 }
 
@@ -160,10 +223,15 @@ func (dbc *Connection) Invoke(options ...InvocationOption) *Invocation {
 		Log:                  dbc.Log,
 		Tracer:               dbc.Tracer,
 		StatementInterceptor: dbc.StatementInterceptor,
+		StatementCache:       dbc.StatementCache,
 	}
 	if dbc.Connection != nil {
 		i.DB = dbc.Connection
 	}
+	if len(dbc.Replicas) > 0 {
+		i.Pool = PoolPrimary
+		i.ReplicaProvider = dbc.Replica
+	}
 	for _, option := range options {
 		option(&i)
 	}
@@ -190,8 +258,22 @@ func (dbc *Connection) QueryContext(ctx context.Context, statement string, args
 	return dbc.Invoke(OptContext(ctx)).Query(statement, args...)
 }
 
+// Notify sends a notification with a payload on a channel with `pg_notify`.
+//
+// To notify as part of a transaction, exec `SELECT pg_notify($1, $2)` with `OptTx`;
+// the notification is delivered when the transaction commits.
+//
+// It returns an `ErrUnsupportedDialect` for the sqlite and mysql dialects.
+func (dbc *Connection) Notify(ctx context.Context, channel, payload string) error {
+	if dbc.Config.DialectOrDefault().Is(DialectSQLite, DialectMySQL) {
+		return ex.New(ErrUnsupportedDialect, ex.OptMessage("notify"))
+	}
+	_, err := dbc.Invoke(OptContext(ctx), OptLabel("notify")).Exec("SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
+
 // Check implements a status check.
 func (dbc *Connection) Check(ctx context.Context) error {
-	_, err := dbc.QueryContext(ctx, "select 1").Any()
+	_, err := dbc.Invoke(OptContext(ctx), OptPrimary()).Query("select 1").Any()
 	return err
 }
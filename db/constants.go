@@ -14,6 +14,10 @@ import (
 const (
 	// DefaultEngine is the default database engine.
 	DefaultEngine = "pgx" // "postgres"
+	// EngineSQLite is the engine of the pure go sqlite driver, registered by importing `db/sqlite`.
+	EngineSQLite = "sqlite"
+	// EngineMySQL is the engine of the mysql driver, registered by importing `db/mysql`.
+	EngineMySQL = "mysql"
 
 	// EnvVarDBEngine is the environment variable used to set the Go `sql` driver.
 	EnvVarDBEngine = "DB_ENGINE"
@@ -74,12 +78,23 @@ const (
 	// EnvVarDBDialect is the environment variable used to set the dialect
 	// on a connection configuration (e.g. `postgres` or `cockroachdb`).
 	EnvVarDBDialect = "DB_DIALECT"
+	// EnvVarDBReplicaDSNs is the environment variable used to set the
+	// comma separated connection strings of read replicas.
+	EnvVarDBReplicaDSNs = "DB_REPLICA_DSNS"
+	// EnvVarDBReplicaMaxLag is the environment variable used to set the
+	// maximum replication lag of a read replica that serves reads.
+	EnvVarDBReplicaMaxLag = "DB_REPLICA_MAX_LAG"
+	// EnvVarDBReplicaCheckInterval is the environment variable used to set
+	// the interval between read replica health checks.
+	EnvVarDBReplicaCheckInterval = "DB_REPLICA_CHECK_INTERVAL"
 
 	// DefaultHost is the default database hostname, typically used
 	// when developing locally.
 	DefaultHost = "localhost"
 	// DefaultPort is the default postgres port.
 	DefaultPort = "5432"
+	// DefaultMySQLPort is the default mysql port.
+	DefaultMySQLPort = "3306"
 	// DefaultDatabase is the default database to connect to, we use
 	// `postgres` to not pollute the template databases.
 	DefaultDatabase = "postgres"
@@ -119,4 +134,35 @@ const (
 	DefaultMaxIdleTime = time.Duration(0)
 	// DefaultBufferPoolSize is the default number of buffer pool entries to maintain.
 	DefaultBufferPoolSize = 1024
+
+	// DefaultReplicaMaxLag is the default maximum replication lag of a read replica that serves reads.
+	DefaultReplicaMaxLag = 10 * time.Second
+	// DefaultReplicaCheckInterval is the default interval between read replica health checks.
+	DefaultReplicaCheckInterval = 5 * time.Second
+	// DefaultReplicaCheckTimeout is the timeout of a read replica health check.
+	DefaultReplicaCheckTimeout = 2 * time.Second
+
+	// PoolPrimary is the pool name of the primary on query events of connections with read replicas.
+	PoolPrimary = "primary"
+
+	// DefaultTxMaxAttempts is the default number of attempts `Connection.InTx` makes to run a transaction.
+	DefaultTxMaxAttempts = 5
+	// DefaultTxRetryDelay is the base of the exponential backoff between transaction attempts.
+	DefaultTxRetryDelay = 50 * time.Millisecond
+	// DefaultTxLabel is the label of the query events triggered for each transaction attempt.
+	DefaultTxLabel = "in_tx"
+
+	// DefaultPageSize is the default number of rows returned by `Invocation.Page`.
+	DefaultPageSize = 100
+
+	// DefaultCopyBatchSize is the default number of rows written by each `COPY` or chunked insert of `Invocation.CopyMany`.
+	DefaultCopyBatchSize = 10000
+
+	// DefaultListenerReconnectDelay is the default delay before the first reconnect attempt of a `Listener`.
+	DefaultListenerReconnectDelay = 500 * time.Millisecond
+	// DefaultListenerMaxReconnectDelay is the default maximum delay between reconnect attempts of a `Listener`.
+	DefaultListenerMaxReconnectDelay = 30 * time.Second
+
+	// DefaultStatementCacheSize is the default number of prepared statements a `StatementCache` keeps.
+	DefaultStatementCacheSize = 256
 )
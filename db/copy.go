@@ -0,0 +1,307 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// ObjectIterator returns the next object to copy, and false once there are no more objects.
+type ObjectIterator func() (object DatabaseMapped, ok bool, err error)
+
+// CopyOption mutates copy options.
+type CopyOption func(*CopyOptions)
+
+// OptCopyBatchSize sets the number of rows written by each `COPY` or chunked insert.
+func OptCopyBatchSize(batchSize int) CopyOption {
+	return func(co *CopyOptions) { co.BatchSize = batchSize }
+}
+
+// CopyOptions are options for `Invocation.CopyMany`.
+type CopyOptions struct {
+	BatchSize int
+}
+
+// BatchSizeOrDefault returns the batch size or a default.
+func (co CopyOptions) BatchSizeOrDefault() int {
+	if co.BatchSize > 0 {
+		return co.BatchSize
+	}
+	return DefaultCopyBatchSize
+}
+
+// CopyMany bulk loads objects into the table of their type and returns the number of rows written.
+//
+// The objects can be a slice or array, a channel, or an `ObjectIterator` of `DatabaseMapped` objects,
+// and are read in batches, so channels and iterators are streamed rather than held in memory.
+// The same columns are written as by `CreateMany`.
+//
+// Each batch is written with `COPY ... FROM STDIN` if the connection uses the postgres dialect
+// with the pgx driver and the invocation is not in a transaction. Otherwise each batch is written
+// with multi-row inserts chunked to stay under the statement parameter limit of the dialect.
+// Batches are written separately, so if the invocation is not in a transaction, a failed load
+// leaves the batches written before the failure in place. It returns an `ErrCopyNoColumns`
+// if the type of the objects has no insert columns.
+func (i *Invocation) CopyMany(objects interface{}, opts ...CopyOption) (count int64, err error) {
+	var options CopyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var statement string
+	defer func() { err = i.finish(statement, recover(), driver.RowsAffected(count), err) }()
+
+	var next ObjectIterator
+	var objectType reflect.Type
+	next, objectType, err = copySource(i.Context, objects)
+	if err != nil || objectType == nil {
+		return
+	}
+	tableName := TableNameByType(objectType)
+	insertCols := ColumnsFromType(tableName, objectType).InsertColumns()
+	columnNames := insertCols.ColumnNames()
+	if len(columnNames) == 0 {
+		err = ex.New(ErrCopyNoColumns, ex.OptMessagef("table: %s", tableName))
+		return
+	}
+
+	copyDB, useCopy := i.copyDB()
+	batchSize := options.BatchSizeOrDefault()
+	rowsPerInsert := copyRowsPerInsert(i.Config.DialectOrDefault(), len(columnNames), batchSize)
+	if useCopy {
+		statement = copyStatement(tableName, columnNames)
+	} else {
+		statement = insertManyStatement(i.Config.DialectOrDefault(), tableName, columnNames, rowsPerInsert)
+	}
+	if statement, err = i.start(statement); err != nil {
+		return
+	}
+
+	var conn *sql.Conn
+	if useCopy {
+		if conn, err = copyDB.Conn(i.Context); err != nil {
+			err = Error(err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+	}
+
+	rows := make([][]interface{}, 0, batchSize)
+	for {
+		rows = rows[:0]
+		for len(rows) < batchSize {
+			object, ok, nextErr := next()
+			if nextErr != nil {
+				err = nextErr
+				return
+			}
+			if !ok {
+				break
+			}
+			rows = append(rows, insertCols.ColumnValues(object))
+		}
+		if len(rows) == 0 {
+			return
+		}
+
+		var written int64
+		if useCopy {
+			written, err = i.copyRows(conn, tableName, columnNames, rows)
+		} else {
+			written, err = i.insertRows(statement, tableName, columnNames, rowsPerInsert, rows)
+		}
+		count += written
+		if err != nil {
+			return
+		}
+		if len(rows) < batchSize {
+			return
+		}
+	}
+}
+
+// copyDB returns the database to copy with, and if `COPY` can be used.
+func (i *Invocation) copyDB() (*sql.DB, bool) {
+	if !i.Config.DialectOrDefault().Is(DialectPostgres) || i.Config.EngineOrDefault() != DefaultEngine {
+		return nil, false
+	}
+	typed, ok := i.DB.(*sql.DB)
+	return typed, ok
+}
+
+// copyRows writes rows with `COPY ... FROM STDIN` on a given connection.
+func (i *Invocation) copyRows(conn *sql.Conn, tableName string, columnNames []string, rows [][]interface{}) (written int64, err error) {
+	err = conn.Raw(func(driverConn interface{}) error {
+		typed, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return ex.New(ErrCopyUnsupported, ex.OptMessagef("driver connection: %T", driverConn))
+		}
+		var copyErr error
+		written, copyErr = typed.Conn().CopyFrom(i.Context, pgx.Identifier(strings.Split(tableName, ".")), columnNames, pgx.CopyFromRows(rows))
+		return copyErr
+	})
+	if err != nil {
+		err = Error(err)
+	}
+	return
+}
+
+// insertRows writes rows with multi-row inserts of at most a given number of rows.
+func (i *Invocation) insertRows(statement, tableName string, columnNames []string, rowsPerInsert int, rows [][]interface{}) (written int64, err error) {
+	for start := 0; start < len(rows); start += rowsPerInsert {
+		chunk := rows[start:]
+		chunkStatement := statement
+		if len(chunk) > rowsPerInsert {
+			chunk = chunk[:rowsPerInsert]
+		} else if len(chunk) < rowsPerInsert {
+			if chunkStatement, err = i.intercept(insertManyStatement(i.Config.DialectOrDefault(), tableName, columnNames, len(chunk))); err != nil {
+				return
+			}
+		}
+		args := make([]interface{}, 0, len(chunk)*len(columnNames))
+		for _, row := range chunk {
+			args = append(args, row...)
+		}
+		var res sql.Result
+		if res, err = i.DB.ExecContext(i.Context, chunkStatement, args...); err != nil {
+			err = Error(err)
+			return
+		}
+		var affected int64
+		if affected, err = res.RowsAffected(); err != nil {
+			err = Error(err)
+			return
+		}
+		written += affected
+	}
+	return
+}
+
+// copySource returns an iterator over the objects of a slice, array, channel or `ObjectIterator`,
+// and the type of the objects.
+//
+// The type is nil if there are no objects.
+func copySource(ctx context.Context, objects interface{}) (ObjectIterator, reflect.Type, error) {
+	if typed, ok := objects.(ObjectIterator); ok {
+		return peekIterator(typed)
+	}
+	if typed, ok := objects.(func() (DatabaseMapped, bool, error)); ok {
+		return peekIterator(typed)
+	}
+
+	value := reflect.ValueOf(objects)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Len() == 0 {
+			return nil, nil, nil
+		}
+		var index int
+		return func() (DatabaseMapped, bool, error) {
+			if index >= value.Len() {
+				return nil, false, nil
+			}
+			index++
+			return value.Index(index - 1).Interface(), true, nil
+		}, ReflectSliceType(objects), nil
+	case reflect.Chan:
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: value},
+		}
+		if ctx != nil && ctx.Done() != nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+		}
+		next := func() (DatabaseMapped, bool, error) {
+			chosen, received, ok := reflect.Select(cases)
+			if chosen == 1 {
+				return nil, false, ex.New(ctx.Err())
+			}
+			if !ok {
+				return nil, false, nil
+			}
+			return received.Interface(), true, nil
+		}
+		elemType := value.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Interface {
+			return peekIterator(next)
+		}
+		return next, elemType, nil
+	default:
+		return nil, nil, ex.New(ErrInvalidCopySource, ex.OptMessagef("objects: %T", objects))
+	}
+}
+
+// peekIterator reads the first object of an iterator to find the type of its objects.
+func peekIterator(next ObjectIterator) (ObjectIterator, reflect.Type, error) {
+	first, ok, err := next()
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	var peeked bool
+	return func() (DatabaseMapped, bool, error) {
+		if !peeked {
+			peeked = true
+			return first, true, nil
+		}
+		return next()
+	}, ReflectType(first), nil
+}
+
+// copyStatement returns the `COPY` statement for a table and columns, used in query events.
+func copyStatement(tableName string, columnNames []string) string {
+	return "COPY " + tableName + " (" + strings.Join(columnNames, ",") + ") FROM STDIN"
+}
+
+// copyRowsPerInsert returns the number of rows written by each chunked insert, i.e. the batch size
+// capped such that an insert stays under the statement parameter limit of the dialect.
+func copyRowsPerInsert(dialect Dialect, columns, batchSize int) int {
+	if maxRows := dialect.MaxParameters() / columns; batchSize > maxRows {
+		return maxRows
+	}
+	return batchSize
+}
+
+// insertManyStatement returns a multi-row insert for a table and columns.
+func insertManyStatement(dialect Dialect, tableName string, columnNames []string, rows int) string {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(tableName)
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(columnNames, ","))
+	sb.WriteString(") VALUES ")
+	metaIndex := 1
+	for x := 0; x < rows; x++ {
+		if x > 0 {
+			sb.WriteRune(',')
+		}
+		sb.WriteRune('(')
+		for y := range columnNames {
+			if y > 0 {
+				sb.WriteRune(',')
+			}
+			sb.WriteString(dialect.Placeholder(metaIndex))
+			metaIndex++
+		}
+		sb.WriteRune(')')
+	}
+	return sb.String()
+}
@@ -0,0 +1,167 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+	"github.com/zpkg/blend-go-sdk/uuid"
+)
+
+func newCopyTestObjects(count int) []benchObj {
+	var objects []benchObj
+	for x := 0; x < count; x++ {
+		objects = append(objects, benchObj{
+			Name:      fmt.Sprintf("test_object_%d", x),
+			UUID:      uuid.V4().String(),
+			Timestamp: time.Now().UTC(),
+			Amount:    1005.0,
+			Pending:   true,
+			Category:  fmt.Sprintf("category_%d", x),
+		})
+	}
+	return objects
+}
+
+func Test_copySource(t *testing.T) {
+	its := assert.New(t)
+
+	next, objectType, err := copySource(context.Background(), newCopyTestObjects(2))
+	its.Nil(err)
+	its.Equal("benchObj", objectType.Name())
+	var count int
+	for {
+		_, ok, err := next()
+		its.Nil(err)
+		if !ok {
+			break
+		}
+		count++
+	}
+	its.Equal(2, count)
+
+	_, objectType, err = copySource(context.Background(), []benchObj{})
+	its.Nil(err)
+	its.Nil(objectType)
+
+	objects := make(chan DatabaseMapped, 2)
+	objects <- &benchObj{Name: "foo"}
+	close(objects)
+	next, objectType, err = copySource(context.Background(), objects)
+	its.Nil(err)
+	its.Equal("benchObj", objectType.Name())
+	object, ok, err := next()
+	its.Nil(err)
+	its.True(ok)
+	its.Equal("foo", object.(*benchObj).Name)
+	_, ok, err = next()
+	its.Nil(err)
+	its.False(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	next, _, err = copySource(ctx, make(chan benchObj))
+	its.Nil(err)
+	_, _, err = next()
+	its.NotNil(err)
+
+	remaining := newCopyTestObjects(3)
+	next, objectType, err = copySource(context.Background(), ObjectIterator(func() (DatabaseMapped, bool, error) {
+		if len(remaining) == 0 {
+			return nil, false, nil
+		}
+		object := remaining[0]
+		remaining = remaining[1:]
+		return object, true, nil
+	}))
+	its.Nil(err)
+	its.Equal("benchObj", objectType.Name())
+	count = 0
+	for {
+		_, ok, err := next()
+		its.Nil(err)
+		if !ok {
+			break
+		}
+		count++
+	}
+	its.Equal(3, count)
+
+	_, _, err = copySource(context.Background(), "not objects")
+	its.True(ex.Is(err, ErrInvalidCopySource))
+}
+
+func Test_insertManyStatement(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal("INSERT INTO bench_object (name,category) VALUES ($1,$2),($3,$4)", insertManyStatement(DialectPostgres, "bench_object", []string{"name", "category"}, 2))
+}
+
+func Test_copyRowsPerInsert(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal(DefaultCopyBatchSize, copyRowsPerInsert(DialectPostgres, 4, DefaultCopyBatchSize))
+	its.Equal(8191, copyRowsPerInsert(DialectSQLite, 4, DefaultCopyBatchSize))
+	its.Equal(6553, copyRowsPerInsert(DialectMySQL, 10, DefaultCopyBatchSize))
+	its.Equal(10, copyRowsPerInsert(DialectSQLite, 4, 10))
+}
+
+func Test_Invocation_CopyMany(t *testing.T) {
+	its := assert.New(t)
+	its.Nil(dropTableIfExists(nil))
+	its.Nil(createTable(nil))
+	defer func() { its.Nil(dropTableIfExists(nil)) }()
+
+	count, err := defaultDB().Invoke().CopyMany(newCopyTestObjects(25), OptCopyBatchSize(10))
+	its.Nil(err)
+	its.Equal(25, count)
+
+	objects := make(chan benchObj)
+	go func() {
+		defer close(objects)
+		for _, object := range newCopyTestObjects(5) {
+			objects <- object
+		}
+	}()
+	count, err = defaultDB().Invoke().CopyMany(objects)
+	its.Nil(err)
+	its.Equal(5, count)
+
+	var verify []benchObj
+	its.Nil(defaultDB().Invoke().Query(`select * from bench_object`).OutMany(&verify))
+	its.Len(verify, 30)
+}
+
+func Test_Invocation_CopyMany_tx(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+	its.Nil(createTable(tx))
+
+	var statements []string
+	count, err := defaultDB().Invoke(
+		OptTx(tx),
+		OptInvocationStatementInterceptor(func(_ context.Context, _, statement string) (string, error) {
+			statements = append(statements, statement)
+			return statement, nil
+		}),
+	).CopyMany(newCopyTestObjects(25), OptCopyBatchSize(10))
+	its.Nil(err)
+	its.Equal(25, count)
+	its.Len(statements, 2)
+
+	var verify []benchObj
+	its.Nil(defaultDB().Invoke(OptTx(tx)).Query(`select * from bench_object`).OutMany(&verify))
+	its.Len(verify, 25)
+}
@@ -0,0 +1,192 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"database/sql"
+	"reflect"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// Cursor streams the results of a query one row at a time.
+//
+// Rows are only read from the connection as `Next` is called, so a slow consumer applies
+// backpressure to the query rather than buffering the whole result set in memory.
+// A cursor must be closed, which finishes the invocation and releases the connection.
+type Cursor struct {
+	Query *Query
+	Rows  *sql.Rows
+
+	err    error
+	closed bool
+}
+
+// Cursor runs the query and returns a cursor over its results.
+func (q *Query) Cursor() (cursor *Cursor, err error) {
+	var rows *sql.Rows
+	rows, err = q.query()
+	if err != nil {
+		err = q.finish(nil, err)
+		err = q.rowsClose(rows, err)
+		return
+	}
+	cursor = &Cursor{
+		Query: q,
+		Rows:  rows,
+	}
+	return
+}
+
+// Stream runs the query and populates a given object with each row in turn,
+// calling the consumer after each row.
+//
+// The next row is not read until the consumer returns; if the consumer returns
+// an error, the query is stopped and the error is returned.
+func (q *Query) Stream(object interface{}, consumer func() error) (err error) {
+	var cursor *Cursor
+	if cursor, err = q.Cursor(); err != nil {
+		return
+	}
+	defer func() { err = ex.Nest(err, cursor.Close()) }()
+
+	for cursor.Next() {
+		if err = cursor.Out(object); err != nil {
+			return
+		}
+		if err = consumer(); err != nil {
+			err = Error(err)
+			return
+		}
+	}
+	return
+}
+
+// StreamBatches runs the query and fills a given collection with up to a batch size of rows
+// at a time, calling the consumer after each batch.
+//
+// The collection is truncated before each batch is read; the next batch is not
+// read until the consumer returns. It returns an `ErrInvalidBatchSize` if the batch size is not positive.
+func (q *Query) StreamBatches(collection interface{}, batchSize int, consumer func() error) (err error) {
+	if batchSize <= 0 {
+		err = ex.New(ErrInvalidBatchSize, ex.OptMessagef("batch size: %d", batchSize))
+		return
+	}
+	var cursor *Cursor
+	if cursor, err = q.Cursor(); err != nil {
+		return
+	}
+	defer func() { err = ex.Nest(err, cursor.Close()) }()
+
+	var count int
+	for {
+		if count, err = cursor.OutBatch(collection, batchSize); err != nil {
+			return
+		}
+		if count == 0 {
+			return
+		}
+		if err = consumer(); err != nil {
+			err = Error(err)
+			return
+		}
+		if count < batchSize {
+			return
+		}
+	}
+}
+
+// Next advances the cursor to the next row, returning false when there
+// are no more rows or an error occurred, which is returned by `Err`.
+func (c *Cursor) Next() bool {
+	if c.closed || c.err != nil {
+		return false
+	}
+	if c.Rows.Next() {
+		return true
+	}
+	if err := c.Rows.Err(); err != nil {
+		c.err = Error(err)
+	}
+	return false
+}
+
+// Out populates a given object from the current row.
+func (c *Cursor) Out(object interface{}) (err error) {
+	if ReflectType(object).Kind() != reflect.Struct {
+		err = Error(ErrDestinationNotStruct)
+		return
+	}
+	if populatable, ok := object.(Populatable); ok {
+		err = populatable.Populate(c.Rows)
+	} else {
+		err = PopulateByName(object, c.Rows, Columns(object))
+	}
+	if err != nil {
+		c.err = Error(err)
+		err = c.err
+	}
+	return
+}
+
+// OutBatch reads up to a batch size of rows into a given collection, replacing its contents,
+// and returns the number of rows read; fewer than the batch size means the cursor is exhausted.
+// It returns an `ErrInvalidBatchSize` if the batch size is not positive.
+func (c *Cursor) OutBatch(collection interface{}, batchSize int) (count int, err error) {
+	if batchSize <= 0 {
+		err = ex.New(ErrInvalidBatchSize, ex.OptMessagef("batch size: %d", batchSize))
+		return
+	}
+	sliceType := ReflectType(collection)
+	if sliceType.Kind() != reflect.Slice {
+		err = Error(ErrCollectionNotSlice)
+		return
+	}
+
+	sliceInnerType := ReflectSliceType(collection)
+	collectionValue := ReflectValue(collection)
+	meta := ColumnsFromType(newColumnCacheKey(sliceInnerType), sliceInnerType)
+	isPopulatable := IsPopulatable(makeNew(sliceInnerType))
+
+	batch := reflect.MakeSlice(sliceType, 0, batchSize)
+	for count < batchSize && c.Next() {
+		newObj := makeNew(sliceInnerType)
+		if isPopulatable {
+			err = AsPopulatable(newObj).Populate(c.Rows)
+		} else {
+			err = PopulateByName(newObj, c.Rows, meta)
+		}
+		if err != nil {
+			c.err = Error(err)
+			err = c.err
+			return
+		}
+		batch = reflect.Append(batch, ReflectValue(newObj))
+		count++
+	}
+	collectionValue.Set(batch)
+	err = c.err
+	return
+}
+
+// Err returns the first error encountered by the cursor.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close closes the rows and finishes the invocation, returning any error encountered by the cursor.
+//
+// It is safe to call close more than once.
+func (c *Cursor) Close() error {
+	if c.closed {
+		return c.err
+	}
+	c.closed = true
+	err := c.Query.finish(nil, c.err)
+	return c.Query.rowsClose(c.Rows, err)
+}
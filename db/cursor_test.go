@@ -0,0 +1,130 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+)
+
+func Test_Query_Cursor(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+
+	its.Nil(seedObjects(10, tx))
+
+	cursor, err := defaultDB().Invoke(OptTx(tx)).Query("select * from bench_object order by id asc").Cursor()
+	its.Nil(err)
+
+	var names []string
+	for cursor.Next() {
+		var obj benchObj
+		its.Nil(cursor.Out(&obj))
+		names = append(names, obj.Name)
+	}
+	its.Nil(cursor.Err())
+	its.Nil(cursor.Close())
+	its.Nil(cursor.Close())
+	its.Len(names, 10)
+	its.Equal("test_object_0", names[0])
+	its.False(cursor.Next())
+}
+
+func Test_Query_Cursor_statementInterceptorFailure(t *testing.T) {
+	its := assert.New(t)
+
+	cursor, err := defaultDB().Invoke(OptInvocationStatementInterceptor(failInterceptor)).Query("select 1").Cursor()
+	its.Nil(cursor)
+	its.Equal(failInterceptorError, err.Error())
+}
+
+func Test_Query_Stream(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+
+	its.Nil(seedObjects(10, tx))
+
+	var obj benchObj
+	var count int
+	err = defaultDB().Invoke(OptTx(tx)).Query("select * from bench_object order by id asc").Stream(&obj, func() error {
+		its.Equal(fmt.Sprintf("test_object_%d", count), obj.Name)
+		count++
+		return nil
+	})
+	its.Nil(err)
+	its.Equal(10, count)
+
+	count = 0
+	err = defaultDB().Invoke(OptTx(tx)).Query("select * from bench_object").Stream(&obj, func() error {
+		count++
+		if count == 3 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	})
+	its.NotNil(err)
+	its.Equal(3, count)
+}
+
+func Test_Query_StreamBatches(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+
+	its.Nil(seedObjects(10, tx))
+
+	var batch []benchObj
+	var sizes []int
+	err = defaultDB().Invoke(OptTx(tx)).Query("select * from bench_object").StreamBatches(&batch, 4, func() error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+	its.Nil(err)
+	its.Equal([]int{4, 4, 2}, sizes)
+
+	sizes = nil
+	err = defaultDB().Invoke(OptTx(tx)).Query("select * from bench_object").StreamBatches(&batch, 5, func() error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+	its.Nil(err)
+	its.Equal([]int{5, 5}, sizes)
+}
+
+func Test_Query_StreamBatches_invalidBatchSize(t *testing.T) {
+	its := assert.New(t)
+
+	conn, err := Open(New(OptSQLite(filepath.Join(its.T.TempDir(), "test.db"))))
+	its.Nil(err)
+	defer func() { _ = conn.Close() }()
+	its.Nil(IgnoreExecResult(conn.Exec("CREATE TABLE bench_object (id INTEGER PRIMARY KEY, name TEXT)")))
+	its.Nil(IgnoreExecResult(conn.Exec("INSERT INTO bench_object (id, name) VALUES (1, 'one')")))
+
+	var batch []benchObj
+	var calls int
+	err = conn.Invoke().Query("select id, name from bench_object").StreamBatches(&batch, 0, func() error {
+		calls++
+		return nil
+	})
+	its.True(IsInvalidBatchSize(err))
+	its.Zero(calls)
+
+	cursor, err := conn.Invoke().Query("select id, name from bench_object").Cursor()
+	its.Nil(err)
+	defer func() { _ = cursor.Close() }()
+	_, err = cursor.OutBatch(&batch, -1)
+	its.True(IsInvalidBatchSize(err))
+}
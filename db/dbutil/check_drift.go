@@ -0,0 +1,76 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package dbutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// CheckDrift verifies the columns of database mapped objects match their live tables,
+// and returns an `ErrSchemaDrift` listing every mismatch if they do not.
+//
+// It is meant to be called at startup, so drift is caught before queries fail. A table drifts if
+// it does not exist, is missing a column of its object, disagrees with its object on primary keys,
+// or has a column without a default that its object would not insert.
+func CheckDrift(ctx context.Context, conn *db.Connection, objects ...db.DatabaseMapped) error {
+	var problems []string
+	for _, object := range objects {
+		tableProblems, err := tableDrift(ctx, conn, object)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, tableProblems...)
+	}
+	if len(problems) > 0 {
+		return ex.New(ErrSchemaDrift, ex.OptMessage(strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// tableDrift returns the mismatches between a database mapped object and its live table.
+func tableDrift(ctx context.Context, conn *db.Connection, object db.DatabaseMapped) ([]string, error) {
+	tableName := db.TableName(object)
+	schema, name := conn.Config.SchemaOrDefault(), tableName
+	if index := strings.LastIndex(tableName, "."); index >= 0 {
+		schema, name = tableName[:index], tableName[index+1:]
+	}
+	table, err := IntrospectTable(ctx, conn, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return []string{fmt.Sprintf("%s: table does not exist", tableName)}, nil
+	}
+
+	var problems []string
+	cols := db.Columns(object)
+	for _, col := range cols.Columns() {
+		tableCol := table.Column(col.ColumnName)
+		if tableCol == nil {
+			problems = append(problems, fmt.Sprintf("%s: column %s does not exist", tableName, col.ColumnName))
+			continue
+		}
+		if col.IsPrimaryKey != tableCol.IsPrimaryKey {
+			problems = append(problems, fmt.Sprintf("%s: column %s primary key mismatch; struct: %t, table: %t", tableName, col.ColumnName, col.IsPrimaryKey, tableCol.IsPrimaryKey))
+		}
+	}
+	for _, tableCol := range table.Columns {
+		if cols.HasColumn(tableCol.Name) {
+			continue
+		}
+		if !tableCol.IsNullable && tableCol.Default == "" && !tableCol.IsAuto && !tableCol.IsGenerated {
+			problems = append(problems, fmt.Sprintf("%s: column %s is not nullable, has no default, and is not mapped", tableName, tableCol.Name))
+		}
+	}
+	return problems, nil
+}
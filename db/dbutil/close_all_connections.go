@@ -14,7 +14,12 @@ import (
 )
 
 // CloseAllConnections closes all other connections to a database.
+//
+// It does nothing for the sqlite dialect, which has no server to hold connections.
 func CloseAllConnections(ctx context.Context, conn *db.Connection, databaseName string) error {
+	if conn.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return nil
+	}
 	_, err := conn.Invoke(db.OptContext(ctx)).Exec(`select pg_terminate_backend(pid) from pg_stat_activity where datname = $1;`, databaseName)
 	return err
 }
@@ -18,7 +18,17 @@ import (
 //
 // Note: the `name` parameter is passed to the statement directly (not via. a parameter).
 // You should use extreme care to not pass user submitted inputs to this function.
+//
+// For the sqlite dialect, the `name` parameter is the path of the database file to create.
 func CreateDatabase(ctx context.Context, name string, opts ...db.Option) (err error) {
+	var sqlite bool
+	if sqlite, err = isSQLite(opts...); err != nil {
+		return
+	}
+	if sqlite {
+		return createSQLiteDatabase(name)
+	}
+
 	var conn *db.Connection
 	defer func() {
 		err = db.PoolCloseFinalizer(conn, err)
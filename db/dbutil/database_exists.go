@@ -14,7 +14,17 @@ import (
 )
 
 // DatabaseExists returns if a database exists or not.
+//
+// For the sqlite dialect, the `name` parameter is the path of the database file.
 func DatabaseExists(ctx context.Context, name string, opts ...db.Option) (exists bool, err error) {
+	var sqlite bool
+	if sqlite, err = isSQLite(opts...); err != nil {
+		return
+	}
+	if sqlite {
+		return sqliteDatabaseExists(name)
+	}
+
 	var conn *db.Connection
 	defer func() {
 		err = db.PoolCloseFinalizer(conn, err)
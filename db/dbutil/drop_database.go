@@ -15,7 +15,17 @@ import (
 )
 
 // DropDatabase drops a database.
+//
+// For the sqlite dialect, the `name` parameter is the path of the database file to remove.
 func DropDatabase(ctx context.Context, name string, opts ...db.Option) (err error) {
+	var sqlite bool
+	if sqlite, err = isSQLite(opts...); err != nil {
+		return
+	}
+	if sqlite {
+		return dropSQLiteDatabase(name)
+	}
+
 	var conn *db.Connection
 	defer func() {
 		err = db.PoolCloseFinalizer(conn, err)
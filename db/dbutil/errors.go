@@ -12,4 +12,16 @@ import "github.com/zpkg/blend-go-sdk/ex"
 // Error constant
 const (
 	ErrDatabaseDoesntExist ex.Class = "dbutil; database doesnt exist"
+	ErrSchemaDrift         ex.Class = "dbutil; database mapped types do not match their tables"
+	ErrNameCollision       ex.Class = "dbutil; sql identifiers map to the same go name"
 )
+
+// IsSchemaDrift returns if an error is an `ErrSchemaDrift`.
+func IsSchemaDrift(err error) bool {
+	return ex.Is(err, ErrSchemaDrift)
+}
+
+// IsNameCollision returns if an error is an `ErrNameCollision`.
+func IsNameCollision(err error) bool {
+	return ex.Is(err, ErrNameCollision)
+}
@@ -0,0 +1,192 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package dbutil
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// GenerateStructs writes go source for a package with a `db.DatabaseMapped` struct for each table.
+//
+// Each struct has a field for each column with a `db` tag marking primary keys (`pk`), sequence or identity
+// columns (`auto`), json columns (`json`) and generated columns (`readonly`), and a `TableName` method.
+// Tables outside the default schema have schema qualified table names.
+//
+// Nullable columns are mapped to pointers, and columns of types without a go mapping to strings.
+// Numeric columns are mapped to `float64`, which loses precision for values with more than
+// about 15 significant digits; map them to strings by hand where exact values matter.
+//
+// It returns an `ErrNameCollision` if two tables, or two columns of a table, map to the same
+// go name, e.g. `user_id` and `userID`, or if a column maps to `TableName`.
+func GenerateStructs(wr io.Writer, packageName string, tables []Table) error {
+	if err := checkNameCollisions(tables); err != nil {
+		return err
+	}
+
+	buffer := new(bytes.Buffer)
+	fmt.Fprintf(buffer, "// Code generated by dbgen. DO NOT EDIT.\n\npackage %s\n\n", packageName)
+
+	var usesTime bool
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			if strings.Contains(goType(col), "time.Time") {
+				usesTime = true
+			}
+		}
+	}
+	if usesTime {
+		buffer.WriteString("import \"time\"\n\n")
+	}
+
+	for _, table := range tables {
+		structName := GoName(table.Name)
+		fmt.Fprintf(buffer, "// %s is a row of the `%s` table.\n", structName, table.Name)
+		fmt.Fprintf(buffer, "type %s struct {\n", structName)
+		for _, col := range table.Columns {
+			fmt.Fprintf(buffer, "\t%s %s `db:\"%s\"`\n", GoName(col.Name), goType(col), dbTag(col))
+		}
+		buffer.WriteString("}\n\n")
+
+		tableName := table.Name
+		if table.Schema != "" && table.Schema != db.DefaultSchema {
+			tableName = table.Schema + "." + table.Name
+		}
+		fmt.Fprintf(buffer, "// TableName implements db.TableNameProvider.\n")
+		fmt.Fprintf(buffer, "func (%s) TableName() string { return %q }\n\n", structName, tableName)
+	}
+
+	contents, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return ex.New(err)
+	}
+	_, err = wr.Write(contents)
+	return ex.New(err)
+}
+
+// checkNameCollisions returns an error if tables or columns of a table map to the same go name.
+func checkNameCollisions(tables []Table) error {
+	structNames := make(map[string]string)
+	for _, table := range tables {
+		structName := GoName(table.Name)
+		if other, ok := structNames[structName]; ok {
+			return ex.New(ErrNameCollision, ex.OptMessagef("tables %q and %q both map to %s", other, table.Name, structName))
+		}
+		structNames[structName] = table.Name
+
+		fieldNames := map[string]string{"TableName": "the TableName method"}
+		for _, col := range table.Columns {
+			fieldName := GoName(col.Name)
+			if other, ok := fieldNames[fieldName]; ok {
+				return ex.New(ErrNameCollision, ex.OptMessagef("table %q: column %q and %s both map to %s", table.Name, col.Name, other, fieldName))
+			}
+			fieldNames[fieldName] = fmt.Sprintf("column %q", col.Name)
+		}
+	}
+	return nil
+}
+
+// GoName returns the exported go name of a sql identifier, e.g. `user_id` becomes `UserID`.
+func GoName(identifier string) string {
+	var output strings.Builder
+	for _, piece := range strings.FieldsFunc(identifier, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		if initialism := strings.ToUpper(piece); commonInitialisms[initialism] {
+			output.WriteString(initialism)
+			continue
+		}
+		output.WriteString(strings.ToUpper(piece[:1]) + piece[1:])
+	}
+	name := output.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+	return name
+}
+
+var commonInitialisms = map[string]bool{
+	"API":  true,
+	"DB":   true,
+	"HTML": true,
+	"HTTP": true,
+	"ID":   true,
+	"IP":   true,
+	"JSON": true,
+	"SQL":  true,
+	"URI":  true,
+	"URL":  true,
+	"UTC":  true,
+	"UUID": true,
+	"XML":  true,
+}
+
+// dbTag returns the `db` struct tag of a column.
+func dbTag(col TableColumn) string {
+	options := []string{col.Name}
+	if col.IsPrimaryKey {
+		options = append(options, "pk")
+	}
+	if col.IsAuto {
+		options = append(options, "auto")
+	}
+	if isJSONType(col) {
+		options = append(options, "json")
+	}
+	if col.IsGenerated {
+		options = append(options, "readonly")
+	}
+	return strings.Join(options, ",")
+}
+
+// goType returns the go type of a column.
+func goType(col TableColumn) string {
+	if isJSONType(col) {
+		return "map[string]interface{}"
+	}
+	if col.DataType == "ARRAY" {
+		return nullable(col, "string")
+	}
+	switch col.UDTName {
+	case "bool":
+		return nullable(col, "bool")
+	case "int2":
+		return nullable(col, "int16")
+	case "int4":
+		return nullable(col, "int")
+	case "int8":
+		return nullable(col, "int64")
+	case "float4":
+		return nullable(col, "float32")
+	case "float8", "numeric":
+		return nullable(col, "float64")
+	case "timestamp", "timestamptz", "date":
+		return nullable(col, "time.Time")
+	case "bytea":
+		return "[]byte"
+	default:
+		return nullable(col, "string")
+	}
+}
+
+func nullable(col TableColumn, goType string) string {
+	if col.IsNullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+func isJSONType(col TableColumn) bool {
+	return col.UDTName == "json" || col.UDTName == "jsonb"
+}
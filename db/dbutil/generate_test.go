@@ -0,0 +1,102 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package dbutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+func Test_GenerateStructs(t *testing.T) {
+	its := assert.New(t)
+
+	tables := []Table{
+		{
+			Schema: "public",
+			Name:   "user_accounts",
+			Columns: []TableColumn{
+				{Name: "id", UDTName: "int8", IsPrimaryKey: true, IsAuto: true},
+				{Name: "email", UDTName: "varchar"},
+				{Name: "settings", UDTName: "jsonb", IsNullable: true},
+				{Name: "created_utc", UDTName: "timestamp"},
+				{Name: "deleted_utc", UDTName: "timestamp", IsNullable: true},
+				{Name: "email_lower", UDTName: "text", IsGenerated: true},
+			},
+		},
+		{
+			Schema: "billing",
+			Name:   "invoices",
+			Columns: []TableColumn{
+				{Name: "invoice_uuid", UDTName: "uuid", IsPrimaryKey: true},
+				{Name: "amount", UDTName: "numeric"},
+			},
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	its.Nil(GenerateStructs(buffer, "model", tables))
+	its.Equal(`// Code generated by dbgen. DO NOT EDIT.
+
+package model
+
+import "time"
+
+// UserAccounts is a row of the `+"`user_accounts`"+` table.
+type UserAccounts struct {
+	ID         int64                  `+"`db:\"id,pk,auto\"`"+`
+	Email      string                 `+"`db:\"email\"`"+`
+	Settings   map[string]interface{} `+"`db:\"settings,json\"`"+`
+	CreatedUTC time.Time              `+"`db:\"created_utc\"`"+`
+	DeletedUTC *time.Time             `+"`db:\"deleted_utc\"`"+`
+	EmailLower string                 `+"`db:\"email_lower,readonly\"`"+`
+}
+
+// TableName implements db.TableNameProvider.
+func (UserAccounts) TableName() string { return "user_accounts" }
+
+// Invoices is a row of the `+"`invoices`"+` table.
+type Invoices struct {
+	InvoiceUUID string  `+"`db:\"invoice_uuid,pk\"`"+`
+	Amount      float64 `+"`db:\"amount\"`"+`
+}
+
+// TableName implements db.TableNameProvider.
+func (Invoices) TableName() string { return "billing.invoices" }
+`, buffer.String())
+}
+
+func Test_GenerateStructs_nameCollision(t *testing.T) {
+	its := assert.New(t)
+
+	err := GenerateStructs(new(bytes.Buffer), "model", []Table{
+		{Name: "users", Columns: []TableColumn{{Name: "user_id", UDTName: "int8"}, {Name: "userID", UDTName: "int8"}}},
+	})
+	its.True(IsNameCollision(err))
+	its.Equal(`table "users": column "userID" and column "user_id" both map to UserID`, ex.ErrMessage(err))
+
+	err = GenerateStructs(new(bytes.Buffer), "model", []Table{{Name: "type"}, {Name: "Type"}})
+	its.True(IsNameCollision(err))
+	its.Equal(`tables "type" and "Type" both map to Type`, ex.ErrMessage(err))
+
+	err = GenerateStructs(new(bytes.Buffer), "model", []Table{
+		{Name: "reports", Columns: []TableColumn{{Name: "table_name", UDTName: "text"}}},
+	})
+	its.True(IsNameCollision(err))
+}
+
+func Test_GoName(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal("UserID", GoName("user_id"))
+	its.Equal("APIKeyURL", GoName("api_key_url"))
+	its.Equal("X2ndLine", GoName("2nd_line"))
+	its.Equal("CreatedAt", GoName("CreatedAt"))
+}
@@ -0,0 +1,128 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package dbutil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/db"
+)
+
+// Table is the introspected definition of a table.
+type Table struct {
+	Schema  string
+	Name    string
+	Columns []TableColumn
+}
+
+// Column returns a column of the table by name, or nil.
+func (t Table) Column(name string) *TableColumn {
+	for index := range t.Columns {
+		if t.Columns[index].Name == name {
+			return &t.Columns[index]
+		}
+	}
+	return nil
+}
+
+// TableColumn is the introspected definition of a table column.
+type TableColumn struct {
+	Name string
+	// DataType is the sql type of the column, e.g. `character varying`.
+	DataType string
+	// UDTName is the underlying postgres type of the column, e.g. `varchar`.
+	UDTName    string
+	IsNullable bool
+	Default    string
+	// IsPrimaryKey is true if the column is part of the primary key.
+	IsPrimaryKey bool
+	// IsAuto is true if the column is generated on insert by a sequence or identity.
+	IsAuto bool
+	// IsGenerated is true if the column is computed from other columns and cannot be written.
+	IsGenerated bool
+}
+
+// IntrospectSchema returns the tables of a schema, ordered by name, with their columns in table order.
+func IntrospectSchema(ctx context.Context, conn *db.Connection, schema string) ([]Table, error) {
+	return introspect(ctx, conn, schema, "")
+}
+
+// IntrospectTable returns a table of a schema, or nil if it does not exist.
+func IntrospectTable(ctx context.Context, conn *db.Connection, schema, table string) (*Table, error) {
+	tables, err := introspect(ctx, conn, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	return &tables[0], nil
+}
+
+// introspect returns the tables of a schema, optionally limited to a single table.
+func introspect(ctx context.Context, conn *db.Connection, schema, table string) ([]Table, error) {
+	if conn.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return introspectSQLite(ctx, conn, schema, table)
+	}
+	primaryKeys := map[string]bool{}
+	err := conn.Invoke(db.OptContext(ctx), db.OptPrimary(), db.OptLabel("dbutil_introspect_primary_keys")).Query(`SELECT
+		cls.relname::text, a.attname::text
+	FROM pg_catalog.pg_index i
+	JOIN pg_catalog.pg_class cls ON cls.oid = i.indrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = cls.relnamespace
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = cls.oid AND a.attnum = ANY(i.indkey)
+	WHERE i.indisprimary AND n.nspname::text = $1::text AND ($2::text = '' OR cls.relname::text = $2::text)`,
+		schema, table,
+	).Each(func(r db.Rows) error {
+		var tableName, columnName string
+		if err := r.Scan(&tableName, &columnName); err != nil {
+			return err
+		}
+		primaryKeys[tableName+"."+columnName] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	err = conn.Invoke(db.OptContext(ctx), db.OptPrimary(), db.OptLabel("dbutil_introspect_columns")).Query(`SELECT
+		c.table_name::text
+		, c.column_name::text
+		, c.data_type::text
+		, c.udt_name::text
+		, c.is_nullable::text = 'YES'
+		, coalesce(c.column_default::text, '')
+		, c.is_identity::text = 'YES'
+		, c.is_generated::text = 'ALWAYS'
+	FROM information_schema.columns c
+	JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+	WHERE t.table_type::text = 'BASE TABLE' AND c.table_schema::text = $1::text AND ($2::text = '' OR c.table_name::text = $2::text)
+	ORDER BY c.table_name, c.ordinal_position`,
+		schema, table,
+	).Each(func(r db.Rows) error {
+		var tableName string
+		var col TableColumn
+		var isIdentity bool
+		if err := r.Scan(&tableName, &col.Name, &col.DataType, &col.UDTName, &col.IsNullable, &col.Default, &isIdentity, &col.IsGenerated); err != nil {
+			return err
+		}
+		col.IsPrimaryKey = primaryKeys[tableName+"."+col.Name]
+		col.IsAuto = isIdentity || strings.HasPrefix(col.Default, "nextval(")
+		if len(tables) == 0 || tables[len(tables)-1].Name != tableName {
+			tables = append(tables, Table{Schema: schema, Name: tableName})
+		}
+		tables[len(tables)-1].Columns = append(tables[len(tables)-1].Columns, col)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
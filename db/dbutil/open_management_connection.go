@@ -11,14 +11,8 @@ import "github.com/zpkg/blend-go-sdk/db"
 
 // OpenManagementConnection creates a database connection to the default database (typically postgres).
 func OpenManagementConnection(options ...db.Option) (*db.Connection, error) {
-	defaults := []db.Option{
-		db.OptHost("localhost"),
-		db.OptSSLMode("disable"),
-		db.OptConfigFromEnv(),
-		db.OptDatabase("postgres"),
-	}
 	conn, err := db.New(
-		append(defaults, options...)...,
+		append(managementDefaults(), options...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -29,3 +23,13 @@ func OpenManagementConnection(options ...db.Option) (*db.Connection, error) {
 	}
 	return conn, nil
 }
+
+// managementDefaults returns the options applied to a management connection before the given options.
+func managementDefaults() []db.Option {
+	return []db.Option{
+		db.OptHost("localhost"),
+		db.OptSSLMode("disable"),
+		db.OptConfigFromEnv(),
+		db.OptDatabase("postgres"),
+	}
+}
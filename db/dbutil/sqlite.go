@@ -0,0 +1,176 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// managementConfig returns the config a management connection would be opened with, without opening it.
+func managementConfig(options ...db.Option) (db.Config, error) {
+	conn, err := db.New(append(managementDefaults(), options...)...)
+	if err != nil {
+		return db.Config{}, err
+	}
+	return conn.Config, nil
+}
+
+// isSQLite returns if the management connection for the given options uses the sqlite dialect.
+//
+// For the sqlite dialect a database is a file, and its name is the path of the file.
+func isSQLite(options ...db.Option) (bool, error) {
+	cfg, err := managementConfig(options...)
+	if err != nil {
+		return false, err
+	}
+	return cfg.DialectOrDefault().Is(db.DialectSQLite), nil
+}
+
+// createSQLiteDatabase creates an empty database file, which sqlite treats as an empty database.
+func createSQLiteDatabase(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0600)
+	if err != nil {
+		return ex.New(err)
+	}
+	return ex.New(f.Close())
+}
+
+// dropSQLiteDatabase removes a database file along with its journal files.
+func dropSQLiteDatabase(path string) error {
+	if err := os.Remove(path); err != nil {
+		return ex.New(err)
+	}
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return ex.New(err)
+		}
+	}
+	return nil
+}
+
+// sqliteDatabaseExists returns if a database file exists.
+func sqliteDatabaseExists(path string) (bool, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, ex.New(err)
+	}
+	return true, nil
+}
+
+// introspectSQLite returns the tables of a sqlite schema, optionally limited to a single table.
+//
+// The declared column types are mapped to the postgres type names used by `TableColumn.UDTName`.
+func introspectSQLite(ctx context.Context, conn *db.Connection, schema, table string) ([]Table, error) {
+	schema = sqliteSchemaName(schema)
+	var tables []Table
+	err := conn.Invoke(db.OptContext(ctx), db.OptPrimary(), db.OptLabel("dbutil_introspect_columns")).Query(fmt.Sprintf(`SELECT
+		m.name
+		, c.name
+		, c.type
+		, c."notnull" = 0
+		, coalesce(c.dflt_value, '')
+		, c.pk
+		, c.hidden IN (2, 3)
+	FROM %s.sqlite_master m
+	JOIN pragma_table_xinfo(m.name, ?1) c
+	WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%%' AND (?2 = '' OR m.name = ?2)
+	ORDER BY m.name, c.cid`, db.DialectSQLite.QuoteIdentifier(schema)),
+		schema, table,
+	).Each(func(r db.Rows) error {
+		var tableName, declaredType string
+		var col TableColumn
+		var pk int
+		if err := r.Scan(&tableName, &col.Name, &declaredType, &col.IsNullable, &col.Default, &pk, &col.IsGenerated); err != nil {
+			return err
+		}
+		col.DataType = strings.ToLower(declaredType)
+		col.UDTName = sqliteUDTName(declaredType)
+		col.IsPrimaryKey = pk > 0
+		if len(tables) == 0 || tables[len(tables)-1].Name != tableName {
+			tables = append(tables, Table{Schema: schema, Name: tableName})
+		}
+		tables[len(tables)-1].Columns = append(tables[len(tables)-1].Columns, col)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// an `INTEGER PRIMARY KEY` column is an alias of the rowid, and is generated on insert.
+	for index := range tables {
+		var primaryKeys []*TableColumn
+		for colIndex := range tables[index].Columns {
+			if tables[index].Columns[colIndex].IsPrimaryKey {
+				primaryKeys = append(primaryKeys, &tables[index].Columns[colIndex])
+			}
+		}
+		if len(primaryKeys) == 1 && primaryKeys[0].DataType == "integer" {
+			primaryKeys[0].IsAuto = true
+		}
+	}
+	return tables, nil
+}
+
+// sqliteSchemaName returns the sqlite schema for a schema name, where the default schema is `main`.
+func sqliteSchemaName(schema string) string {
+	if schema == "" || schema == db.DefaultSchema {
+		return "main"
+	}
+	return schema
+}
+
+// sqliteUDTName returns the postgres type name for a declared sqlite column type.
+func sqliteUDTName(declaredType string) string {
+	declaredType = strings.ToLower(strings.TrimSpace(declaredType))
+	if index := strings.IndexByte(declaredType, '('); index >= 0 {
+		declaredType = strings.TrimSpace(declaredType[:index])
+	}
+	switch declaredType {
+	case "bool", "boolean":
+		return "bool"
+	case "int2", "smallint":
+		return "int2"
+	case "int", "int4", "mediumint":
+		return "int4"
+	case "float4":
+		return "float4"
+	case "numeric", "decimal":
+		return "numeric"
+	case "date":
+		return "date"
+	case "timestamp", "datetime":
+		return "timestamp"
+	case "timestamptz":
+		return "timestamptz"
+	case "json", "jsonb":
+		return declaredType
+	case "", "blob", "bytea":
+		return "bytea"
+	}
+	// the remaining types follow the sqlite type affinity rules.
+	switch {
+	case strings.Contains(declaredType, "int"):
+		return "int8"
+	case strings.Contains(declaredType, "char"), strings.Contains(declaredType, "clob"), strings.Contains(declaredType, "text"):
+		return "text"
+	case strings.Contains(declaredType, "real"), strings.Contains(declaredType, "floa"), strings.Contains(declaredType, "doub"):
+		return "float8"
+	default:
+		return "numeric"
+	}
+}
@@ -0,0 +1,77 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package dbutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
+	_ "github.com/zpkg/blend-go-sdk/db/sqlite"
+)
+
+func Test_Database_sqlite(t *testing.T) {
+	its := assert.New(t)
+	ctx := context.Background()
+	path := filepath.Join(its.T.TempDir(), "test.db")
+
+	exists, err := DatabaseExists(ctx, path, db.OptSQLite(path))
+	its.Nil(err)
+	its.False(exists)
+
+	its.Nil(CreateDatabaseIfNotExists(ctx, "dev", path, db.OptSQLite(path)))
+	exists, err = DatabaseExists(ctx, path, db.OptSQLite(path))
+	its.Nil(err)
+	its.True(exists)
+	its.NotNil(CreateDatabase(ctx, path, db.OptSQLite(path)))
+
+	conn, err := OpenManagementConnection(db.OptSQLite(path))
+	its.Nil(err)
+	its.Nil(db.IgnoreExecResult(conn.Exec(`CREATE TABLE user_accounts (
+		id INTEGER PRIMARY KEY
+		, email VARCHAR(255) NOT NULL
+		, settings JSON
+		, created_utc TIMESTAMP NOT NULL
+		, email_lower TEXT GENERATED ALWAYS AS (lower(email)) VIRTUAL
+	)`)))
+	its.Nil(db.IgnoreExecResult(conn.Exec(`CREATE TABLE memberships (
+		account_id INTEGER NOT NULL
+		, group_id INTEGER NOT NULL
+		, PRIMARY KEY (account_id, group_id)
+	)`)))
+
+	tables, err := IntrospectSchema(ctx, conn, db.DefaultSchema)
+	its.Nil(err)
+	its.Len(tables, 2)
+	its.Equal("memberships", tables[0].Name)
+	its.True(tables[0].Column("account_id").IsPrimaryKey)
+	its.False(tables[0].Column("account_id").IsAuto)
+
+	table, err := IntrospectTable(ctx, conn, db.DefaultSchema, "user_accounts")
+	its.Nil(err)
+	its.NotNil(table)
+	its.Equal("main", table.Schema)
+	its.Len(table.Columns, 5)
+	its.True(table.Column("id").IsPrimaryKey)
+	its.True(table.Column("id").IsAuto)
+	its.Equal("int8", table.Column("id").UDTName)
+	its.Equal("text", table.Column("email").UDTName)
+	its.False(table.Column("email").IsNullable)
+	its.Equal("json", table.Column("settings").UDTName)
+	its.True(table.Column("settings").IsNullable)
+	its.Equal("timestamp", table.Column("created_utc").UDTName)
+	its.True(table.Column("email_lower").IsGenerated)
+	its.Nil(conn.Close())
+
+	its.Nil(DropDatabase(ctx, path, db.OptSQLite(path)))
+	exists, err = DatabaseExists(ctx, path, db.OptSQLite(path))
+	its.Nil(err)
+	its.False(exists)
+}
@@ -0,0 +1,71 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+// Interface assertions.
+var (
+	_ StatementBuilder = (*DeleteBuilder)(nil)
+)
+
+// DeleteFrom returns a delete statement builder for a given table.
+func DeleteFrom(table string) *DeleteBuilder {
+	return &DeleteBuilder{
+		table: table,
+	}
+}
+
+// DeleteBuilder builds delete statements.
+type DeleteBuilder struct {
+	label     string
+	table     string
+	where     []Predicate
+	returning []string
+}
+
+// WithLabel sets the label for the statement.
+func (db *DeleteBuilder) WithLabel(label string) *DeleteBuilder {
+	db.label = label
+	return db
+}
+
+// Where adds predicates to the where clause; all predicates must be true.
+func (db *DeleteBuilder) Where(predicates ...Predicate) *DeleteBuilder {
+	db.where = append(db.where, predicates...)
+	return db
+}
+
+// Returning sets the columns to return from the deleted rows.
+//
+// Mysql does not support returning clauses; the clause is not built for the mysql dialect,
+// and the statement cannot be run with `Invocation.QueryStatement`.
+func (db *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	db.returning = columns
+	return db
+}
+
+func (db *DeleteBuilder) hasReturning() bool {
+	return len(db.returning) > 0
+}
+
+// Label implements StatementBuilder.
+func (db *DeleteBuilder) Label() string {
+	if db.label != "" {
+		return db.label
+	}
+	return db.table + "_delete"
+}
+
+// Build implements StatementBuilder.
+func (db *DeleteBuilder) Build(dialect Dialect) (string, []interface{}) {
+	sw := newStatementWriter(dialect)
+	sw.WriteString("DELETE FROM ")
+	sw.WriteIdentifier(db.table)
+	sw.WriteWhere(db.where)
+	sw.WriteReturning(db.returning)
+	return sw.Result()
+}
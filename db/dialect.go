@@ -7,7 +7,10 @@ Use of this source code is governed by a MIT license that can be found in the LI
 
 package db
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 // Dialect is the flavor of sql.
 type Dialect string
@@ -31,4 +34,43 @@ var (
 	DialectCockroachDB Dialect = "cockroachdb"
 	// DialectRedshift is the redshift dialect.
 	DialectRedshift Dialect = "redshift"
+	// DialectSQLite is the sqlite dialect.
+	DialectSQLite Dialect = "sqlite"
+	// DialectMySQL is the mysql dialect.
+	DialectMySQL Dialect = "mysql"
 )
+
+// Placeholder returns the placeholder for the argument at a given
+// one based index, e.g. `$1` for postgres or `?1` for sqlite.
+//
+// Mysql placeholders are positional, i.e. `?` for every index, so an argument
+// cannot be referenced twice in a mysql statement.
+func (d Dialect) Placeholder(index int) string {
+	if d.Is(DialectMySQL) {
+		return "?"
+	}
+	if d.Is(DialectSQLite) {
+		return "?" + strconv.Itoa(index)
+	}
+	return "$" + strconv.Itoa(index)
+}
+
+// QuoteIdentifier quotes an identifier, e.g. a table or column name, such that
+// it is not interpreted as a keyword; quotes within the identifier are escaped.
+//
+// Mysql identifiers are quoted with backticks.
+func (d Dialect) QuoteIdentifier(identifier string) string {
+	if d.Is(DialectMySQL) {
+		return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// MaxParameters returns the maximum number of parameters of a single statement,
+// e.g. 65535 for postgres, or 32766 for sqlite (the default `SQLITE_MAX_VARIABLE_NUMBER`).
+func (d Dialect) MaxParameters() int {
+	if d.Is(DialectSQLite) {
+		return 32766
+	}
+	return 65535
+}
@@ -46,6 +46,30 @@ const (
 	ErrRowsNotColumnsProvider ex.Class = "db: rows is not a columns provider"
 	// ErrTooManyRows is returned by Out if there is more than one row returned by the query
 	ErrTooManyRows ex.Class = "db: too many rows returned to map to single object"
+	// ErrReplicaLagging is an error indicating a read replica's replication lag is greater than the configured max lag.
+	ErrReplicaLagging ex.Class = "db: replica replication lag exceeds max lag"
+	// ErrInvalidPageToken is returned by Page if the page token is malformed or was issued for a different sort order.
+	ErrInvalidPageToken ex.Class = "db: invalid page token"
+	// ErrInvalidPageOrder is returned by Page if a sort column is not a column of the mapped type.
+	ErrInvalidPageOrder ex.Class = "db: invalid page order column"
+	// ErrVersionConflict is returned by Update and Upsert if the version of an object does not match the stored version.
+	ErrVersionConflict ex.Class = "db: version conflict; the row has been modified or deleted"
+	// ErrInvalidVersionColumn is returned by Update and Upsert if the version column is not an integer.
+	ErrInvalidVersionColumn ex.Class = "db: version column is not an integer"
+	// ErrInvalidCopySource is returned by CopyMany if the objects are not a slice, array, channel or `ObjectIterator`.
+	ErrInvalidCopySource ex.Class = "db: copy objects are not a slice, array, channel or iterator"
+	// ErrCopyUnsupported is returned by CopyMany if the driver connection does not support `COPY`.
+	ErrCopyUnsupported ex.Class = "db: copy is not supported by the driver connection"
+	// ErrCopyNoColumns is returned by CopyMany if the mapped type has no insert columns.
+	ErrCopyNoColumns ex.Class = "db: copy type has no insert columns"
+	// ErrInvalidBatchSize is returned by StreamBatches and OutBatch if the batch size is not positive.
+	ErrInvalidBatchSize ex.Class = "db: batch size must be positive"
+	// ErrUnsupportedDialect is returned by operations that are not supported by the connection dialect.
+	ErrUnsupportedDialect ex.Class = "db: operation is not supported by the connection dialect"
+	// ErrInvalidMySQLDSN is returned by NewConfigFromDSN if a mysql DSN cannot be parsed.
+	ErrInvalidMySQLDSN ex.Class = "db: invalid mysql dsn"
+	// ErrInvalidStatement is returned by QueryStatement and ExecStatement if a statement builder cannot build a valid statement.
+	ErrInvalidStatement ex.Class = "db: invalid statement"
 
 	// ErrNetwork is a grouped error for network issues.
 	ErrNetwork ex.Class = "db: network error"
@@ -91,6 +115,36 @@ func IsPlanCacheKeyUnset(err error) bool {
 	return ex.Is(err, ErrPlanCacheKeyUnset)
 }
 
+// IsInvalidPageToken returns if the error is an `ErrInvalidPageToken`.
+func IsInvalidPageToken(err error) bool {
+	return ex.Is(err, ErrInvalidPageToken)
+}
+
+// IsInvalidPageOrder returns if the error is an `ErrInvalidPageOrder`.
+func IsInvalidPageOrder(err error) bool {
+	return ex.Is(err, ErrInvalidPageOrder)
+}
+
+// IsVersionConflict returns if the error is an `ErrVersionConflict`.
+func IsVersionConflict(err error) bool {
+	return ex.Is(err, ErrVersionConflict)
+}
+
+// IsUnsupportedDialect returns if an error is an `ErrUnsupportedDialect`.
+func IsUnsupportedDialect(err error) bool {
+	return ex.Is(err, ErrUnsupportedDialect)
+}
+
+// IsInvalidStatement returns if an error is an `ErrInvalidStatement`.
+func IsInvalidStatement(err error) bool {
+	return ex.Is(err, ErrInvalidStatement)
+}
+
+// IsInvalidBatchSize returns if an error is an `ErrInvalidBatchSize`.
+func IsInvalidBatchSize(err error) bool {
+	return ex.Is(err, ErrInvalidBatchSize)
+}
+
 // Error returns a new exception by parsing (potentially)
 // a driver error into relevant pieces.
 func Error(err error, options ...ex.Option) error {
@@ -0,0 +1,141 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import "fmt"
+
+// Interface assertions.
+var (
+	_ StatementBuilder = (*InsertBuilder)(nil)
+)
+
+// InsertInto returns an insert statement builder for a given table.
+func InsertInto(table string) *InsertBuilder {
+	return &InsertBuilder{
+		table: table,
+	}
+}
+
+// InsertObjects returns an insert statement builder for the insert columns of a set of
+// database mapped objects, which must all be of the same type, with a row per object.
+//
+// Auto columns are not returned unless they are passed to `Returning`. If there are no objects,
+// or they are not all of the same type, `QueryStatement` and `ExecStatement` return an `ErrInvalidStatement`.
+func InsertObjects(objects ...DatabaseMapped) *InsertBuilder {
+	if len(objects) == 0 {
+		return &InsertBuilder{}
+	}
+	objectType := ReflectType(objects[0])
+	insertCols := Columns(objects[0]).InsertColumns()
+	ib := InsertInto(TableName(objects[0])).Columns(insertCols.ColumnNames()...)
+	for index, object := range objects {
+		if ReflectType(object) != objectType {
+			ib.err = fmt.Errorf("object %d is a %v, not a %v", index, ReflectType(object), objectType)
+			return ib
+		}
+		ib.Values(insertCols.ColumnValues(object)...)
+	}
+	return ib
+}
+
+// InsertBuilder builds insert statements.
+type InsertBuilder struct {
+	label     string
+	table     string
+	columns   []string
+	rows      [][]interface{}
+	returning []string
+	err       error
+}
+
+// WithLabel sets the label for the statement.
+func (ib *InsertBuilder) WithLabel(label string) *InsertBuilder {
+	ib.label = label
+	return ib
+}
+
+// Columns sets the columns to insert.
+func (ib *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	ib.columns = columns
+	return ib
+}
+
+// Values adds a row of values, in the same order as the columns.
+func (ib *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	ib.rows = append(ib.rows, values)
+	return ib
+}
+
+// Returning sets the columns to return from the inserted rows.
+//
+// Mysql does not support returning clauses; the clause is not built for the mysql dialect,
+// and the statement cannot be run with `Invocation.QueryStatement`.
+func (ib *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	ib.returning = columns
+	return ib
+}
+
+func (ib *InsertBuilder) hasReturning() bool {
+	return len(ib.returning) > 0
+}
+
+// validate returns an error if the builder was given objects of mixed types, has no table,
+// columns or rows, or a row does not have a value for each column.
+func (ib *InsertBuilder) validate() error {
+	if ib.err != nil {
+		return ib.err
+	}
+	if ib.table == "" {
+		return fmt.Errorf("no table to insert into")
+	}
+	if len(ib.columns) == 0 {
+		return fmt.Errorf("no columns to insert")
+	}
+	if len(ib.rows) == 0 {
+		return fmt.Errorf("no rows to insert")
+	}
+	for index, row := range ib.rows {
+		if len(row) != len(ib.columns) {
+			return fmt.Errorf("row %d has %d values for %d columns", index, len(row), len(ib.columns))
+		}
+	}
+	return nil
+}
+
+// Label implements StatementBuilder.
+func (ib *InsertBuilder) Label() string {
+	if ib.label != "" {
+		return ib.label
+	}
+	return ib.table + "_insert"
+}
+
+// Build implements StatementBuilder.
+func (ib *InsertBuilder) Build(dialect Dialect) (string, []interface{}) {
+	sw := newStatementWriter(dialect)
+	sw.WriteString("INSERT INTO ")
+	sw.WriteIdentifier(ib.table)
+	sw.WriteString(" (")
+	sw.WriteIdentifiers(ib.columns)
+	sw.WriteString(") VALUES ")
+	for rowIndex, row := range ib.rows {
+		if rowIndex > 0 {
+			sw.WriteString(",")
+		}
+		sw.WriteString("(")
+		for index, value := range row {
+			if index > 0 {
+				sw.WriteString(",")
+			}
+			sw.WriteArg(value)
+		}
+		sw.WriteString(")")
+	}
+	sw.WriteReturning(ib.returning)
+	return sw.Result()
+}
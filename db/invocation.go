@@ -8,11 +8,12 @@ Use of this source code is governed by a MIT license that can be found in the LI
 package db
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/zpkg/blend-go-sdk/bufferutil"
@@ -33,6 +34,17 @@ type Invocation struct {
 	Tracer               Tracer
 	StartTime            time.Time
 	TraceFinisher        TraceFinisher
+	// StatementCache runs labeled statements with cached prepared statements, if set.
+	StatementCache *StatementCache
+	// Pool is the name of the pool that serves the invocation, set if the connection has read replicas.
+	Pool string
+	// ReplicaProvider returns a read replica to serve reads; it is unset for invocations
+	// in a transaction or with `OptPrimary`.
+	ReplicaProvider ReplicaProvider
+	// IncludeDeleted includes soft deleted rows in the results of `Get`, `All` and `Exists`.
+	IncludeDeleted bool
+
+	replica *Replica
 }
 
 // Exec executes a sql statement with a given set of arguments and returns the rows affected.
@@ -43,7 +55,7 @@ func (i *Invocation) Exec(statement string, args ...interface{}) (res sql.Result
 	}
 	defer func() { err = i.finish(statement, recover(), res, err) }()
 
-	res, err = i.DB.ExecContext(i.Context, statement, args...)
+	res, err = i.primaryDB().ExecContext(i.Context, statement, args...)
 	if err != nil {
 		err = Error(err)
 		return
@@ -52,7 +64,13 @@ func (i *Invocation) Exec(statement string, args ...interface{}) (res sql.Result
 }
 
 // Query returns a new query object for a given sql query and arguments.
+//
+// If the connection has read replicas, the query is served by a healthy replica
+// unless the invocation is in a transaction or has `OptPrimary`. A query that writes,
+// e.g. an `INSERT ... RETURNING`, fails on a read-only replica and must use `OptPrimary`,
+// as must reads that have to see the invocation's own writes.
 func (i *Invocation) Query(statement string, args ...interface{}) *Query {
+	i.routeRead()
 	q := &Query{
 		Invocation: i,
 		Args:       args,
@@ -61,6 +79,99 @@ func (i *Invocation) Query(statement string, args ...interface{}) *Query {
 	return q
 }
 
+// QueryStatement returns a new query object for a statement builder.
+//
+// The statement is built for the configured dialect, and the builder label is used
+// if the invocation does not already have a label. A statement with a returning clause
+// returns an `ErrUnsupportedDialect` for the mysql dialect, and a statement builder that
+// cannot build a valid statement returns an `ErrInvalidStatement`.
+//
+// Insert, update and delete builders are writes, and always run on the primary.
+func (i *Invocation) QueryStatement(builder StatementBuilder) *Query {
+	dialect := i.Config.DialectOrDefault()
+	statement, args := builder.Build(dialect)
+	i.maybeSetLabel(builder.Label())
+	typed, isWrite := builder.(returningStatementBuilder)
+	if isWrite {
+		i.ReplicaProvider = nil
+	}
+	q := i.Query(statement, args...)
+	if q.Err == nil {
+		q.Err = validateStatement(builder)
+	}
+	if isWrite && typed.hasReturning() && dialect.Is(DialectMySQL) && q.Err == nil {
+		q.Err = ex.New(ErrUnsupportedDialect, ex.OptMessage("returning"))
+	}
+	return q
+}
+
+// ExecStatement executes a statement builder and returns the result.
+//
+// The statement is built for the configured dialect, and the builder label is used
+// if the invocation does not already have a label. A statement builder that cannot
+// build a valid statement returns an `ErrInvalidStatement`.
+func (i *Invocation) ExecStatement(builder StatementBuilder) (sql.Result, error) {
+	if err := validateStatement(builder); err != nil {
+		return nil, err
+	}
+	statement, args := builder.Build(i.Config.DialectOrDefault())
+	i.maybeSetLabel(builder.Label())
+	return i.Exec(statement, args...)
+}
+
+// validateStatement returns an `ErrInvalidStatement` if a statement builder cannot build a valid statement.
+func validateStatement(builder StatementBuilder) error {
+	if typed, ok := builder.(validatingStatementBuilder); ok {
+		if err := typed.validate(); err != nil {
+			return ex.New(ErrInvalidStatement, ex.OptMessagef("%s: %v", builder.Label(), err))
+		}
+	}
+	return nil
+}
+
+// routeRead routes reads of the invocation to a healthy read replica, if there is one.
+func (i *Invocation) routeRead() {
+	if i.ReplicaProvider == nil {
+		return
+	}
+	replica := i.ReplicaProvider()
+	i.ReplicaProvider = nil
+	if replica == nil {
+		return
+	}
+	i.replica = replica
+	i.Pool = replica.Name
+}
+
+// readDB returns the db that serves reads, i.e. the routed read replica or the primary db.
+func (i *Invocation) readDB() DB {
+	if i.replica != nil {
+		return i.replica.Connection
+	}
+	return i.primaryDB()
+}
+
+// primaryDB returns the invocation db, which runs labeled statements with the prepared statements
+// of the statement cache if there is one and the db is the connection pool, i.e. not a transaction.
+func (i *Invocation) primaryDB() DB {
+	if i.StatementCache != nil && i.Label != "" && i.StatementCache.serves(i.DB) {
+		return cachedStatementDB{cache: i.StatementCache, label: i.Label}
+	}
+	return i.DB
+}
+
+// fallbackToPrimary routes reads of an invocation served by a read replica back to the primary
+// if a given error means the replica could not be reached, returning if it did so.
+func (i *Invocation) fallbackToPrimary(err error) bool {
+	if i.replica == nil || !isConnectionError(err) {
+		return false
+	}
+	i.replica.MarkUnhealthy(err)
+	i.replica = nil
+	i.Pool = PoolPrimary
+	return true
+}
+
 func (i *Invocation) maybeSetLabel(label string) {
 	if i.Label != "" {
 		return
@@ -69,6 +180,7 @@ func (i *Invocation) maybeSetLabel(label string) {
 }
 
 // Get returns a given object based on a group of primary key ids within a transaction.
+// Soft deleted objects are not found unless the invocation has `OptIncludeDeleted`.
 func (i *Invocation) Get(object DatabaseMapped, ids ...interface{}) (found bool, err error) {
 	if len(ids) == 0 {
 		err = Error(ErrInvalidIDs)
@@ -85,6 +197,7 @@ func (i *Invocation) Get(object DatabaseMapped, ids ...interface{}) (found bool,
 }
 
 // All returns all rows of an object mapped table wrapped in a transaction.
+// Soft deleted rows are not returned unless the invocation has `OptIncludeDeleted`.
 func (i *Invocation) All(collection interface{}) (err error) {
 	label, queryBody := i.generateGetAll(collection)
 	i.maybeSetLabel(label)
@@ -92,6 +205,9 @@ func (i *Invocation) All(collection interface{}) (err error) {
 }
 
 // Create writes an object to the database within a transaction.
+//
+// For the mysql dialect, which has no `RETURNING` clause, an unset auto column
+// is set from the id generated by the insert, i.e. `LAST_INSERT_ID()`.
 func (i *Invocation) Create(object DatabaseMapped) (err error) {
 	var queryBody, label string
 	var insertCols, autos *ColumnCollection
@@ -105,16 +221,17 @@ func (i *Invocation) Create(object DatabaseMapped) (err error) {
 	if err != nil {
 		return
 	}
-	if autos.Len() == 0 {
-		if res, err = i.DB.ExecContext(i.Context, queryBody, insertCols.ColumnValues(object)...); err != nil {
+	if autos.Len() == 0 || i.Config.DialectOrDefault().Is(DialectMySQL) {
+		if res, err = i.primaryDB().ExecContext(i.Context, queryBody, insertCols.ColumnValues(object)...); err != nil {
 			err = Error(err)
 			return
 		}
+		err = i.setLastInsertID(object, autos, res)
 		return
 	}
 
 	autoValues := i.autoValues(autos)
-	if err = i.DB.QueryRowContext(i.Context, queryBody, insertCols.ColumnValues(object)...).Scan(autoValues...); err != nil {
+	if err = i.primaryDB().QueryRowContext(i.Context, queryBody, insertCols.ColumnValues(object)...).Scan(autoValues...); err != nil {
 		err = Error(err)
 		return
 	}
@@ -129,6 +246,8 @@ func (i *Invocation) Create(object DatabaseMapped) (err error) {
 // CreateIfNotExists writes an object to the database if it does not already exist within a transaction.
 // This will _ignore_ auto columns, as they will always invalidate the assertion that there already exists
 // a row with a given primary key set.
+//
+// For the mysql dialect a conflict on any unique key, not only the primary key, leaves the existing row as is.
 func (i *Invocation) CreateIfNotExists(object DatabaseMapped) (err error) {
 	var queryBody, label string
 	var insertCols *ColumnCollection
@@ -142,13 +261,15 @@ func (i *Invocation) CreateIfNotExists(object DatabaseMapped) (err error) {
 	if err != nil {
 		return
 	}
-	if res, err = i.DB.ExecContext(i.Context, queryBody, insertCols.ColumnValues(object)...); err != nil {
+	if res, err = i.primaryDB().ExecContext(i.Context, queryBody, insertCols.ColumnValues(object)...); err != nil {
 		err = Error(err)
 	}
 	return
 }
 
 // CreateMany writes many objects to the database in a single insert.
+//
+// Use `CopyMany` to bulk load large numbers of objects.
 func (i *Invocation) CreateMany(objects interface{}) (err error) {
 	return i.insertOrUpsertMany(objects, false)
 }
@@ -185,6 +306,7 @@ func (i *Invocation) insertOrUpsertMany(objects interface{}, overwrite bool) (er
 		colValues = append(colValues, insertCols.ColumnValues(sliceValue.Index(row).Interface())...)
 	}
 
+	// the statement text varies with the number of objects, so it is not run with a cached prepared statement.
 	res, err = i.DB.ExecContext(i.Context, queryBody, colValues...)
 	if err != nil {
 		err = Error(err)
@@ -197,6 +319,9 @@ func (i *Invocation) insertOrUpsertMany(objects interface{}, overwrite bool) (er
 // an error. If ErrTooManyRows is returned, it's important to note that due to https://github.com/golang/go/issues/7898,
 // the Update HAS BEEN APPLIED. Its on the developer using UPDATE to ensure his tags are correct and/or execute it in a
 // transaction and roll back on this error
+//
+// If the object has a `version` column, the update only applies if the stored version matches the object's version,
+// and increments both; otherwise it returns an `ErrVersionConflict`.
 func (i *Invocation) Update(object DatabaseMapped) (updated bool, err error) {
 	var queryBody, label string
 	var pks, updateCols *ColumnCollection
@@ -210,11 +335,16 @@ func (i *Invocation) Update(object DatabaseMapped) (updated bool, err error) {
 	if err != nil {
 		return
 	}
-	res, err = i.DB.ExecContext(
-		i.Context,
-		queryBody,
-		append(updateCols.ColumnValues(object), pks.ColumnValues(object)...)...,
-	)
+	args := append(updateCols.ColumnValues(object), pks.ColumnValues(object)...)
+	version := Columns(object).VersionColumn()
+	var nextVersion interface{}
+	if version != nil {
+		if nextVersion, err = versionIncrement(*version, object); err != nil {
+			return
+		}
+		args = append(args, version.GetValue(object))
+	}
+	res, err = i.primaryDB().ExecContext(i.Context, queryBody, args...)
 	if err != nil {
 		err = Error(err)
 		return
@@ -231,12 +361,23 @@ func (i *Invocation) Update(object DatabaseMapped) (updated bool, err error) {
 	}
 	if rowCount > 1 {
 		err = Error(ErrTooManyRows)
+		return
+	}
+	if version != nil {
+		if rowCount == 0 {
+			err = Error(ErrVersionConflict, ex.OptMessagef("table: %s, version: %v", TableName(object), version.GetValue(object)))
+			return
+		}
+		err = maybeSetValue(*version, object, nextVersion)
 	}
 	return
 }
 
 // Upsert inserts the object if it doesn't exist already (as defined by its primary keys) or updates it atomically.
 // It returns `found` as true if the effect was an upsert, i.e. the pk was found.
+//
+// If the object has a `version` column, an existing row is only updated if its version matches the object's version,
+// and the object's version is set to the stored version; otherwise it returns an `ErrVersionConflict`.
 func (i *Invocation) Upsert(object DatabaseMapped) (err error) {
 	var queryBody, label string
 	var autos, upsertCols *ColumnCollection
@@ -249,15 +390,23 @@ func (i *Invocation) Upsert(object DatabaseMapped) (err error) {
 	if err != nil {
 		return
 	}
+	if i.Config.DialectOrDefault().Is(DialectMySQL) {
+		err = i.upsertMySQL(object, queryBody, autos, upsertCols)
+		return
+	}
 	if autos.Len() == 0 {
-		if _, err = i.DB.ExecContext(i.Context, queryBody, upsertCols.ColumnValues(object)...); err != nil {
+		if _, err = i.primaryDB().ExecContext(i.Context, queryBody, upsertCols.ColumnValues(object)...); err != nil {
 			return
 		}
 		return
 	}
 
 	autoValues := i.autoValues(autos)
-	if err = i.DB.QueryRowContext(i.Context, queryBody, upsertCols.ColumnValues(object)...).Scan(autoValues...); err != nil {
+	if err = i.primaryDB().QueryRowContext(i.Context, queryBody, upsertCols.ColumnValues(object)...).Scan(autoValues...); err != nil {
+		if version := Columns(object).VersionColumn(); version != nil && ex.Is(err, sql.ErrNoRows) {
+			err = Error(ErrVersionConflict, ex.OptMessagef("table: %s, version: %v", TableName(object), version.GetValue(object)))
+			return
+		}
 		err = Error(err)
 		return
 	}
@@ -269,6 +418,8 @@ func (i *Invocation) Upsert(object DatabaseMapped) (err error) {
 }
 
 // Exists returns a bool if a given object exists (utilizing the primary key columns if they exist) wrapped in a transaction.
+// Like `Query`, it is served by a read replica if the connection has them.
+// Soft deleted objects do not exist unless the invocation has `OptIncludeDeleted`.
 func (i *Invocation) Exists(object DatabaseMapped) (exists bool, err error) {
 	var queryBody, label string
 	var pks *ColumnCollection
@@ -279,12 +430,17 @@ func (i *Invocation) Exists(object DatabaseMapped) (exists bool, err error) {
 		return
 	}
 	i.maybeSetLabel(label)
+	i.routeRead()
 	queryBody, err = i.start(queryBody)
 	if err != nil {
 		return
 	}
 	var value int
-	if queryErr := i.DB.QueryRowContext(i.Context, queryBody, pks.ColumnValues(object)...).Scan(&value); queryErr != nil && !ex.Is(queryErr, sql.ErrNoRows) {
+	queryErr := i.readDB().QueryRowContext(i.Context, queryBody, pks.ColumnValues(object)...).Scan(&value)
+	if queryErr != nil && i.fallbackToPrimary(queryErr) {
+		queryErr = i.primaryDB().QueryRowContext(i.Context, queryBody, pks.ColumnValues(object)...).Scan(&value)
+	}
+	if queryErr != nil && !ex.Is(queryErr, sql.ErrNoRows) {
 		err = Error(queryErr)
 		return
 	}
@@ -296,6 +452,9 @@ func (i *Invocation) Exists(object DatabaseMapped) (exists bool, err error) {
 // and potentially an error. If ErrTooManyRows is returned, it's important to note that due to
 // https://github.com/golang/go/issues/7898, the Delete HAS BEEN APPLIED on the current transaction. Its on the
 // developer using Delete to ensure their tags are correct and/or ensure theit Tx rolls back on this error.
+//
+// If the object has a `deleted_at` column, the row is soft deleted by setting that column to the current time
+// on the row and the object, if it is not already deleted.
 func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
 	var queryBody, label string
 	var pks *ColumnCollection
@@ -311,7 +470,13 @@ func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
 	if err != nil {
 		return
 	}
-	res, err = i.DB.ExecContext(i.Context, queryBody, pks.ColumnValues(object)...)
+	args := pks.ColumnValues(object)
+	softDelete := Columns(object).SoftDeleteColumn()
+	deletedUTC := time.Now().UTC()
+	if softDelete != nil {
+		args = append([]interface{}{deletedUTC}, args...)
+	}
+	res, err = i.primaryDB().ExecContext(i.Context, queryBody, args...)
 	if err != nil {
 		err = Error(err)
 		return
@@ -328,6 +493,10 @@ func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
 	}
 	if rowCount > 1 {
 		err = Error(ErrTooManyRows)
+		return
+	}
+	if softDelete != nil && deleted {
+		err = maybeSetValue(*softDelete, object, &deletedUTC)
 	}
 	return
 }
@@ -337,6 +506,7 @@ func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
 // --------------------------------------------------------------------------------
 
 func (i *Invocation) generateGet(object DatabaseMapped) (cachePlan, queryBody string, err error) {
+	dialect := i.Config.DialectOrDefault()
 	tableName := TableName(object)
 
 	cols := Columns(object).NotReadOnly()
@@ -364,7 +534,7 @@ func (i *Invocation) generateGet(object DatabaseMapped) (cachePlan, queryBody st
 	for i, pk := range pks.Columns() {
 		queryBodyBuffer.WriteString(pk.ColumnName)
 		queryBodyBuffer.WriteString(" = ")
-		queryBodyBuffer.WriteString("$" + strconv.Itoa(i+1))
+		queryBodyBuffer.WriteString(dialect.Placeholder(i + 1))
 
 		if i < (pks.Len() - 1) {
 			queryBodyBuffer.WriteString(" AND ")
@@ -372,6 +542,13 @@ func (i *Invocation) generateGet(object DatabaseMapped) (cachePlan, queryBody st
 	}
 
 	cachePlan = fmt.Sprintf("%s_get", tableName)
+	if softDelete := Columns(object).SoftDeleteColumn(); softDelete != nil {
+		if i.IncludeDeleted {
+			cachePlan = cachePlan + "_with_deleted"
+		} else {
+			queryBodyBuffer.WriteString(" AND " + softDelete.ColumnName + " IS NULL")
+		}
+	}
 	queryBody = queryBodyBuffer.String()
 	return
 }
@@ -395,17 +572,29 @@ func (i *Invocation) generateGetAll(collection interface{}) (statementLabel, que
 	queryBodyBuffer.WriteString(" FROM ")
 	queryBodyBuffer.WriteString(tableName)
 
-	queryBody = queryBodyBuffer.String()
 	statementLabel = tableName + "_get_all"
+	if softDelete := ColumnsFromType(tableName, collectionType).SoftDeleteColumn(); softDelete != nil {
+		if i.IncludeDeleted {
+			statementLabel = statementLabel + "_with_deleted"
+		} else {
+			queryBodyBuffer.WriteString(" WHERE " + softDelete.ColumnName + " IS NULL")
+		}
+	}
+	queryBody = queryBodyBuffer.String()
 	return
 }
 
 func (i *Invocation) generateCreate(object DatabaseMapped) (statementLabel, queryBody string, insertCols, autos *ColumnCollection) {
+	dialect := i.Config.DialectOrDefault()
 	tableName := TableName(object)
 
 	cols := Columns(object)
 	insertCols = cols.InsertColumns().ConcatWith(cols.Autos().NotZero(object))
 	autos = cols.Autos()
+	if dialect.Is(DialectMySQL) {
+		// only unset autos are read back, from the last insert id
+		autos = autos.Zero(object)
+	}
 
 	queryBodyBuffer := i.BufferPool.Get()
 	defer i.BufferPool.Put(queryBodyBuffer)
@@ -421,14 +610,14 @@ func (i *Invocation) generateCreate(object DatabaseMapped) (statementLabel, quer
 	}
 	queryBodyBuffer.WriteString(") VALUES (")
 	for x := 0; x < insertCols.Len(); x++ {
-		queryBodyBuffer.WriteString("$" + strconv.Itoa(x+1))
+		queryBodyBuffer.WriteString(dialect.Placeholder(x + 1))
 		if x < (insertCols.Len() - 1) {
 			queryBodyBuffer.WriteRune(',')
 		}
 	}
 	queryBodyBuffer.WriteString(")")
 
-	if autos.Len() > 0 {
+	if autos.Len() > 0 && !dialect.Is(DialectMySQL) {
 		queryBodyBuffer.WriteString(" RETURNING ")
 		queryBodyBuffer.WriteString(autos.ColumnNamesCSV())
 	}
@@ -439,6 +628,7 @@ func (i *Invocation) generateCreate(object DatabaseMapped) (statementLabel, quer
 }
 
 func (i *Invocation) generateCreateIfNotExists(object DatabaseMapped) (statementLabel, queryBody string, insertCols *ColumnCollection) {
+	dialect := i.Config.DialectOrDefault()
 	cols := Columns(object)
 
 	insertCols = cols.InsertColumns().ConcatWith(cols.Autos().NotZero(object))
@@ -460,14 +650,18 @@ func (i *Invocation) generateCreateIfNotExists(object DatabaseMapped) (statement
 	}
 	queryBodyBuffer.WriteString(") VALUES (")
 	for x := 0; x < insertCols.Len(); x++ {
-		queryBodyBuffer.WriteString("$" + strconv.Itoa(x+1))
+		queryBodyBuffer.WriteString(dialect.Placeholder(x + 1))
 		if x < (insertCols.Len() - 1) {
 			queryBodyBuffer.WriteRune(',')
 		}
 	}
 	queryBodyBuffer.WriteString(")")
 
-	if pks.Len() > 0 {
+	if pks.Len() > 0 && dialect.Is(DialectMySQL) {
+		// assigning a primary key to itself leaves a conflicting row as is
+		queryBodyBuffer.WriteString(" ON DUPLICATE KEY UPDATE ")
+		queryBodyBuffer.WriteString(pks.Columns()[0].ColumnName + " = " + pks.Columns()[0].ColumnName)
+	} else if pks.Len() > 0 {
 		queryBodyBuffer.WriteString(" ON CONFLICT (")
 		pkColumnNames := pks.ColumnNames()
 		for i, name := range pkColumnNames {
@@ -492,7 +686,15 @@ func (i *Invocation) generateUpsertMany(objects interface{}) (queryBody string,
 	queryBodyBuffer.WriteString(queryBodyInsertMany)
 
 	uks := insertCols.UniqueKeys()
-	if uks.Len() > 0 {
+	if uks.Len() > 0 && i.Config.DialectOrDefault().Is(DialectMySQL) {
+		queryBodyBuffer.WriteString(" ON DUPLICATE KEY UPDATE ")
+		for i, name := range insertCols.ColumnNames() {
+			queryBodyBuffer.WriteString(fmt.Sprintf("%s=VALUES(%s)", name, name))
+			if i < (insertCols.Len() - 1) {
+				queryBodyBuffer.WriteRune(',')
+			}
+		}
+	} else if uks.Len() > 0 {
 		queryBodyBuffer.WriteString(" ON CONFLICT (")
 		ukColumnNames := uks.ColumnNames()
 		for i, name := range ukColumnNames {
@@ -515,6 +717,7 @@ func (i *Invocation) generateUpsertMany(objects interface{}) (queryBody string,
 }
 
 func (i *Invocation) generateCreateMany(objects interface{}) (queryBody string, insertCols *ColumnCollection, sliceValue reflect.Value) {
+	dialect := i.Config.DialectOrDefault()
 	sliceValue = ReflectValue(objects)
 	sliceType := ReflectSliceType(objects)
 	tableName := TableNameByType(sliceType)
@@ -541,7 +744,7 @@ func (i *Invocation) generateCreateMany(objects interface{}) (queryBody string,
 	for x := 0; x < sliceValue.Len(); x++ {
 		queryBodyBuffer.WriteString("(")
 		for y := 0; y < insertCols.Len(); y++ {
-			queryBodyBuffer.WriteString(fmt.Sprintf("$%d", metaIndex))
+			queryBodyBuffer.WriteString(dialect.Placeholder(metaIndex))
 			metaIndex = metaIndex + 1
 			if y < insertCols.Len()-1 {
 				queryBodyBuffer.WriteRune(',')
@@ -558,6 +761,7 @@ func (i *Invocation) generateCreateMany(objects interface{}) (queryBody string,
 }
 
 func (i *Invocation) generateUpdate(object DatabaseMapped) (statementLabel, queryBody string, pks, updateCols *ColumnCollection) {
+	dialect := i.Config.DialectOrDefault()
 	tableName := TableName(object)
 
 	cols := Columns(object)
@@ -577,22 +781,33 @@ func (i *Invocation) generateUpdate(object DatabaseMapped) (statementLabel, quer
 	for ; updateColIndex < updateCols.Len(); updateColIndex++ {
 		col = updateCols.Columns()[updateColIndex]
 		queryBodyBuffer.WriteString(col.ColumnName)
-		queryBodyBuffer.WriteString(" = $" + strconv.Itoa(updateColIndex+1))
+		queryBodyBuffer.WriteString(" = " + dialect.Placeholder(updateColIndex+1))
 		if updateColIndex != (updateCols.Len() - 1) {
 			queryBodyBuffer.WriteRune(',')
 		}
 	}
 
+	version := cols.VersionColumn()
+	if version != nil {
+		if updateCols.Len() > 0 {
+			queryBodyBuffer.WriteRune(',')
+		}
+		queryBodyBuffer.WriteString(version.ColumnName + " = " + version.ColumnName + " + 1")
+	}
+
 	queryBodyBuffer.WriteString(" WHERE ")
 	for i, pk := range pks.Columns() {
 		queryBodyBuffer.WriteString(pk.ColumnName)
 		queryBodyBuffer.WriteString(" = ")
-		queryBodyBuffer.WriteString("$" + strconv.Itoa(i+(updateColIndex+1)))
+		queryBodyBuffer.WriteString(dialect.Placeholder(i + (updateColIndex + 1)))
 
 		if i < (pks.Len() - 1) {
 			queryBodyBuffer.WriteString(" AND ")
 		}
 	}
+	if version != nil {
+		queryBodyBuffer.WriteString(" AND " + version.ColumnName + " = " + dialect.Placeholder(updateColIndex+pks.Len()+1))
+	}
 
 	queryBody = queryBodyBuffer.String()
 	statementLabel = tableName + "_update"
@@ -600,6 +815,7 @@ func (i *Invocation) generateUpdate(object DatabaseMapped) (statementLabel, quer
 }
 
 func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, queryBody string, autos, insertsWithAutos *ColumnCollection) {
+	dialect := i.Config.DialectOrDefault()
 	tableName := TableName(object)
 	cols := Columns(object)
 	updates := cols.UpdateColumns()
@@ -621,11 +837,15 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 	insertCols := insertsWithAutos.Columns()
 	tokenMap := map[string]string{}
 	for i, col := range insertCols {
-		tokenMap[col.ColumnName] = "$" + strconv.Itoa(i+1)
+		tokenMap[col.ColumnName] = dialect.Placeholder(i + 1)
 	}
 
-	// autos are read out on insert (but only if unset)
+	// autos are read out on insert (but only if unset), along with the version
 	autos = cols.Autos().Zero(object)
+	version := cols.VersionColumn()
+	if version != nil && !dialect.Is(DialectMySQL) {
+		autos = autos.ConcatWith(NewColumnCollection(*version))
+	}
 	pkNames := pks.ColumnNames()
 
 	queryBodyBuffer := i.BufferPool.Get()
@@ -660,7 +880,9 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 
 	queryBodyBuffer.WriteString(")")
 
-	if pks.Len() > 0 {
+	if pks.Len() > 0 && dialect.Is(DialectMySQL) {
+		writeMySQLUpsertUpdates(queryBodyBuffer, pks, updateCols, autos, version)
+	} else if pks.Len() > 0 {
 		queryBodyBuffer.WriteString(" ON CONFLICT (")
 
 		for i, name := range pkNames {
@@ -677,8 +899,17 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 				queryBodyBuffer.WriteRune(',')
 			}
 		}
+		if version != nil {
+			// the stored row is referenced by its unqualified table name
+			storedVersion := unqualifiedTableName(tableName) + "." + version.ColumnName
+			if len(updateCols) > 0 {
+				queryBodyBuffer.WriteRune(',')
+			}
+			queryBodyBuffer.WriteString(version.ColumnName + " = " + storedVersion + " + 1")
+			queryBodyBuffer.WriteString(" WHERE " + storedVersion + " = EXCLUDED." + version.ColumnName)
+		}
 	}
-	if autos.Len() > 0 {
+	if autos.Len() > 0 && !dialect.Is(DialectMySQL) {
 		queryBodyBuffer.WriteString(" RETURNING ")
 		queryBodyBuffer.WriteString(autos.ColumnNamesCSV())
 	}
@@ -689,6 +920,7 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 }
 
 func (i *Invocation) generateExists(object DatabaseMapped) (statementLabel, queryBody string, pks *ColumnCollection, err error) {
+	dialect := i.Config.DialectOrDefault()
 	tableName := TableName(object)
 	pks = Columns(object).PrimaryKeys()
 	if pks.Len() == 0 {
@@ -704,18 +936,26 @@ func (i *Invocation) generateExists(object DatabaseMapped) (statementLabel, quer
 	for i, pk := range pks.Columns() {
 		queryBodyBuffer.WriteString(pk.ColumnName)
 		queryBodyBuffer.WriteString(" = ")
-		queryBodyBuffer.WriteString("$" + strconv.Itoa(i+1))
+		queryBodyBuffer.WriteString(dialect.Placeholder(i + 1))
 
 		if i < (pks.Len() - 1) {
 			queryBodyBuffer.WriteString(" AND ")
 		}
 	}
 	statementLabel = tableName + "_exists"
+	if softDelete := Columns(object).SoftDeleteColumn(); softDelete != nil {
+		if i.IncludeDeleted {
+			statementLabel = statementLabel + "_with_deleted"
+		} else {
+			queryBodyBuffer.WriteString(" AND " + softDelete.ColumnName + " IS NULL")
+		}
+	}
 	queryBody = queryBodyBuffer.String()
 	return
 }
 
 func (i *Invocation) generateDelete(object DatabaseMapped) (statementLabel, queryBody string, pks *ColumnCollection, err error) {
+	dialect := i.Config.DialectOrDefault()
 	tableName := TableName(object)
 	pks = Columns(object).PrimaryKeys()
 	if len(pks.Columns()) == 0 {
@@ -725,19 +965,34 @@ func (i *Invocation) generateDelete(object DatabaseMapped) (statementLabel, quer
 	queryBodyBuffer := i.BufferPool.Get()
 	defer i.BufferPool.Put(queryBodyBuffer)
 
-	queryBodyBuffer.WriteString("DELETE FROM ")
-	queryBodyBuffer.WriteString(tableName)
+	softDelete := Columns(object).SoftDeleteColumn()
+	var argOffset int
+	if softDelete != nil {
+		queryBodyBuffer.WriteString("UPDATE ")
+		queryBodyBuffer.WriteString(tableName)
+		queryBodyBuffer.WriteString(" SET ")
+		queryBodyBuffer.WriteString(softDelete.ColumnName)
+		queryBodyBuffer.WriteString(" = " + dialect.Placeholder(1))
+		argOffset = 1
+	} else {
+		queryBodyBuffer.WriteString("DELETE FROM ")
+		queryBodyBuffer.WriteString(tableName)
+	}
 	queryBodyBuffer.WriteString(" WHERE ")
 	for i, pk := range pks.Columns() {
 		queryBodyBuffer.WriteString(pk.ColumnName)
 		queryBodyBuffer.WriteString(" = ")
-		queryBodyBuffer.WriteString("$" + strconv.Itoa(i+1))
+		queryBodyBuffer.WriteString(dialect.Placeholder(i + argOffset + 1))
 
 		if i < (pks.Len() - 1) {
 			queryBodyBuffer.WriteString(" AND ")
 		}
 	}
 	statementLabel = tableName + "_delete"
+	if softDelete != nil {
+		queryBodyBuffer.WriteString(" AND " + softDelete.ColumnName + " IS NULL")
+		statementLabel = tableName + "_soft_delete"
+	}
 	queryBody = queryBodyBuffer.String()
 	return
 }
@@ -767,24 +1022,138 @@ func (i *Invocation) setAutos(object DatabaseMapped, autos *ColumnCollection, au
 	return
 }
 
+// setLastInsertID sets the unset auto increment column of an object from the id generated by a mysql insert.
+//
+// Mysql has no `RETURNING` clause, and a table can only have a single `AUTO_INCREMENT` column,
+// so only the first integer auto column is read back; other auto columns, e.g. with a default timestamp, are left unset.
+func (i *Invocation) setLastInsertID(object DatabaseMapped, autos *ColumnCollection, res sql.Result) error {
+	for _, col := range autos.Columns() {
+		switch col.FieldType.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			continue
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return Error(err)
+		}
+		return Error(col.SetValue(object, id))
+	}
+	return nil
+}
+
+// upsertMySQL executes a mysql upsert, which reports an insert as one row affected,
+// an update as two, and a row left as is as zero.
+func (i *Invocation) upsertMySQL(object DatabaseMapped, queryBody string, autos, upsertCols *ColumnCollection) error {
+	res, err := i.primaryDB().ExecContext(i.Context, queryBody, upsertCols.ColumnValues(object)...)
+	if err != nil {
+		return Error(err)
+	}
+	if err = i.setLastInsertID(object, autos, res); err != nil {
+		return err
+	}
+	version := Columns(object).VersionColumn()
+	if version == nil {
+		return nil
+	}
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return Error(err)
+	}
+	switch rowCount {
+	case 0:
+		return Error(ErrVersionConflict, ex.OptMessagef("table: %s, version: %v", TableName(object), version.GetValue(object)))
+	case 2:
+		nextVersion, err := versionIncrement(*version, object)
+		if err != nil {
+			return err
+		}
+		return maybeSetValue(*version, object, nextVersion)
+	default:
+		return nil
+	}
+}
+
+// writeMySQLUpsertUpdates writes the `ON DUPLICATE KEY UPDATE` clause of a mysql upsert.
+//
+// An unset auto primary key is assigned `LAST_INSERT_ID(column)` so that the id of an updated row is read back.
+// With a version column, each column is only updated if the stored version matches the inserted version,
+// and the version is assigned last, as mysql assignments see the columns assigned before them.
+func writeMySQLUpsertUpdates(queryBodyBuffer *bytes.Buffer, pks *ColumnCollection, updateCols []Column, autos *ColumnCollection, version *Column) {
+	var assignments []string
+	for _, col := range autos.Columns() {
+		if col.IsPrimaryKey {
+			assignments = append(assignments, col.ColumnName+" = LAST_INSERT_ID("+col.ColumnName+")")
+			break
+		}
+	}
+	for _, col := range updateCols {
+		if version != nil {
+			assignments = append(assignments, fmt.Sprintf("%s = IF(%s = VALUES(%s), VALUES(%s), %s)",
+				col.ColumnName, version.ColumnName, version.ColumnName, col.ColumnName, col.ColumnName))
+		} else {
+			assignments = append(assignments, col.ColumnName+" = VALUES("+col.ColumnName+")")
+		}
+	}
+	if version != nil {
+		assignments = append(assignments, fmt.Sprintf("%s = IF(%s = VALUES(%s), %s + 1, %s)",
+			version.ColumnName, version.ColumnName, version.ColumnName, version.ColumnName, version.ColumnName))
+	}
+	if len(assignments) == 0 {
+		// assigning a primary key to itself leaves a conflicting row as is
+		name := pks.Columns()[0].ColumnName
+		assignments = append(assignments, name+" = "+name)
+	}
+	queryBodyBuffer.WriteString(" ON DUPLICATE KEY UPDATE ")
+	queryBodyBuffer.WriteString(strings.Join(assignments, ","))
+}
+
+// versionIncrement returns the value of a version column incremented.
+func versionIncrement(version Column, object DatabaseMapped) (interface{}, error) {
+	value := reflect.ValueOf(version.GetValue(object))
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() + 1, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint() + 1, nil
+	default:
+		return nil, Error(ErrInvalidVersionColumn, ex.OptMessagef("field: %s", version.FieldName))
+	}
+}
+
+// maybeSetValue sets a column on an object if the object was passed by reference.
+func maybeSetValue(col Column, object DatabaseMapped, value interface{}) error {
+	if !ReflectValue(object).FieldByName(col.FieldName).CanSet() {
+		return nil
+	}
+	return Error(col.SetValue(object, value))
+}
+
+// unqualifiedTableName returns a table name without its schema.
+func unqualifiedTableName(tableName string) string {
+	if index := strings.LastIndex(tableName, "."); index >= 0 {
+		return tableName[index+1:]
+	}
+	return tableName
+}
+
 // start runs on start steps.
 func (i *Invocation) start(statement string) (string, error) {
 	if i.DB == nil {
 		return "", ex.New(ErrConnectionClosed)
 	}
 	i.StartTime = time.Now()
-	if i.StatementInterceptor != nil {
-		var err error
-		statement, err = i.StatementInterceptor(i.Context, i.Label, statement)
-		if err != nil {
-			return statement, err
-		}
+	statement, err := i.intercept(statement)
+	if err != nil {
+		return statement, err
 	}
 	if i.Log != nil && !IsSkipQueryLogging(i.Context) {
 		qse := NewQueryStartEvent(statement)
 		qse.Username = i.Config.Username
 		qse.Database = i.Config.DatabaseOrDefault()
 		qse.Label = i.Label
+		qse.Pool = i.Pool
 		qse.Engine = i.Config.EngineOrDefault()
 		i.Log.TriggerContext(i.Context, qse)
 	}
@@ -794,6 +1163,14 @@ func (i *Invocation) start(statement string) (string, error) {
 	return statement, nil
 }
 
+// intercept applies the statement interceptor to a statement, if one is set.
+func (i *Invocation) intercept(statement string) (string, error) {
+	if i.StatementInterceptor != nil {
+		return i.StatementInterceptor(i.Context, i.Label, statement)
+	}
+	return statement, nil
+}
+
 // finish runs on complete steps.
 func (i *Invocation) finish(statement string, r interface{}, res sql.Result, err error) error {
 	if i.Cancel != nil {
@@ -807,6 +1184,7 @@ func (i *Invocation) finish(statement string, r interface{}, res sql.Result, err
 		qe.Username = i.Config.Username
 		qe.Database = i.Config.DatabaseOrDefault()
 		qe.Label = i.Label
+		qe.Pool = i.Pool
 		qe.Engine = i.Config.EngineOrDefault()
 		qe.Err = err
 		i.Log.TriggerContext(i.Context, qe)
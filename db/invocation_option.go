@@ -47,18 +47,41 @@ func OptTimeout(d time.Duration) InvocationOption {
 }
 
 // OptTx is an invocation option that sets the invocation transaction.
+//
+// Reads with `OptTx` are not routed to read replicas, even if the transaction is nil,
+// e.g. for statements that optionally run in a transaction.
 func OptTx(tx *sql.Tx) InvocationOption {
 	return func(i *Invocation) {
 		if tx != nil {
 			i.DB = tx
 		}
+		i.ReplicaProvider = nil
 	}
 }
 
 // OptInvocationDB is an invocation option that sets the underlying invocation db.
+//
+// Reads with an explicit db are not routed to read replicas.
 func OptInvocationDB(db DB) InvocationOption {
 	return func(i *Invocation) {
 		i.DB = db
+		i.ReplicaProvider = nil
+	}
+}
+
+// OptPrimary is an invocation option that sends reads to the primary rather than a read replica,
+// e.g. to read your own writes, or for a `Query` that writes with `RETURNING`.
+func OptPrimary() InvocationOption {
+	return func(i *Invocation) {
+		i.ReplicaProvider = nil
+	}
+}
+
+// OptIncludeDeleted is an invocation option that includes soft deleted rows, i.e. rows with
+// a `deleted_at` column set, in the results of `Get`, `All` and `Exists`.
+func OptIncludeDeleted() InvocationOption {
+	return func(i *Invocation) {
+		i.IncludeDeleted = true
 	}
 }
 
@@ -12,12 +12,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/bufferutil"
 	"github.com/zpkg/blend-go-sdk/ex"
 	"github.com/zpkg/blend-go-sdk/logger"
 	"github.com/zpkg/blend-go-sdk/uuid"
@@ -60,6 +62,35 @@ func Test_Invocation_Query(t *testing.T) {
 	its.Equal("this is just an interceptor error", err.Error())
 }
 
+func Test_Invocation_QueryStatement(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+
+	err = seedObjects(10, tx)
+	its.Nil(err)
+
+	statementTracer := new(captureStatementTracer)
+	var objs []benchObj
+	err = defaultDB().Invoke(OptTx(tx), OptInvocationTracer(statementTracer)).QueryStatement(
+		SelectFrom(benchObj{}).Where(In("name", []string{"test_object_1", "test_object_2"})).OrderBy("name"),
+	).OutMany(&objs)
+	its.Nil(err)
+	its.Len(objs, 2)
+	its.Equal("test_object_1", objs[0].Name)
+	its.Equal("bench_object_select", statementTracer.Label)
+
+	res, err := defaultDB().Invoke(OptTx(tx), OptLabel("delete_pending"), OptInvocationTracer(statementTracer)).ExecStatement(
+		DeleteFrom("bench_object").Where(Eq("pending", true)),
+	)
+	its.Nil(err)
+	rowsAffected, err := res.RowsAffected()
+	its.Nil(err)
+	its.Equal(5, rowsAffected)
+	its.Equal("delete_pending", statementTracer.Label)
+}
+
 func Test_Invocation_Create(t *testing.T) {
 	its := assert.New(t)
 	tx, err := defaultDB().Begin()
@@ -1156,3 +1187,184 @@ func Test_Invocation_start(t *testing.T) {
 	its.Equal("select 1", statement)
 	its.NotEmpty(buf.String())
 }
+
+type versionedObj struct {
+	ID         int        `db:"id,pk"`
+	Name       string     `db:"name"`
+	Version    int        `db:"version,version"`
+	DeletedUTC *time.Time `db:"deleted_utc,deleted_at"`
+}
+
+func (versionedObj) TableName() string { return "versioned_obj" }
+
+func Test_Invocation_generateVersioned(t *testing.T) {
+	its := assert.New(t)
+	invocation := &Invocation{BufferPool: bufferutil.NewPool(16)}
+
+	_, queryBody, _, updateCols := invocation.generateUpdate(versionedObj{})
+	its.Equal("UPDATE versioned_obj SET name = $1,version = version + 1 WHERE id = $2 AND version = $3", queryBody)
+	its.Equal([]string{"name"}, updateCols.ColumnNames())
+
+	_, queryBody, _, _ = invocation.generateUpsert(versionedObj{})
+	its.Equal("INSERT INTO versioned_obj (id,name,version) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET name = $2,version = versioned_obj.version + 1 WHERE versioned_obj.version = EXCLUDED.version RETURNING version", queryBody)
+
+	label, queryBody, _, err := invocation.generateDelete(versionedObj{})
+	its.Nil(err)
+	its.Equal("versioned_obj_soft_delete", label)
+	its.Equal("UPDATE versioned_obj SET deleted_utc = $1 WHERE id = $2 AND deleted_utc IS NULL", queryBody)
+
+	label, queryBody, err = invocation.generateGet(versionedObj{})
+	its.Nil(err)
+	its.Equal("versioned_obj_get", label)
+	its.Equal("SELECT id,name,version,deleted_utc FROM versioned_obj WHERE id = $1 AND deleted_utc IS NULL", queryBody)
+
+	label, queryBody = invocation.generateGetAll([]versionedObj{})
+	its.Equal("versioned_obj_get_all", label)
+	its.Equal("SELECT id,name,version,deleted_utc FROM versioned_obj WHERE deleted_utc IS NULL", queryBody)
+
+	label, queryBody, _, err = invocation.generateExists(versionedObj{})
+	its.Nil(err)
+	its.Equal("versioned_obj_exists", label)
+	its.Equal("SELECT 1 FROM versioned_obj WHERE id = $1 AND deleted_utc IS NULL", queryBody)
+
+	invocation.IncludeDeleted = true
+	label, queryBody, err = invocation.generateGet(versionedObj{})
+	its.Nil(err)
+	its.Equal("versioned_obj_get_with_deleted", label)
+	its.Equal("SELECT id,name,version,deleted_utc FROM versioned_obj WHERE id = $1", queryBody)
+}
+
+func Test_Invocation_Versioned(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+
+	its.Nil(IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("CREATE TABLE versioned_obj (id int not null primary key, name varchar(255) not null, version int not null, deleted_utc timestamp)")))
+
+	obj := versionedObj{ID: 1, Name: "foo", Version: 1}
+	its.Nil(defaultDB().Invoke(OptTx(tx)).Create(&obj))
+
+	stale := obj
+	obj.Name = "bar"
+	updated, err := defaultDB().Invoke(OptTx(tx)).Update(&obj)
+	its.Nil(err)
+	its.True(updated)
+	its.Equal(2, obj.Version)
+
+	stale.Name = "baz"
+	_, err = defaultDB().Invoke(OptTx(tx)).Update(&stale)
+	its.True(IsVersionConflict(err))
+
+	obj.Name = "buzz"
+	its.Nil(defaultDB().Invoke(OptTx(tx)).Upsert(&obj))
+	its.Equal(3, obj.Version)
+	its.True(IsVersionConflict(defaultDB().Invoke(OptTx(tx)).Upsert(&stale)))
+
+	deleted, err := defaultDB().Invoke(OptTx(tx)).Delete(&obj)
+	its.Nil(err)
+	its.True(deleted)
+	its.NotNil(obj.DeletedUTC)
+
+	var verify versionedObj
+	found, err := defaultDB().Invoke(OptTx(tx)).Get(&verify, 1)
+	its.Nil(err)
+	its.False(found)
+	exists, err := defaultDB().Invoke(OptTx(tx)).Exists(&obj)
+	its.Nil(err)
+	its.False(exists)
+
+	found, err = defaultDB().Invoke(OptTx(tx), OptIncludeDeleted()).Get(&verify, 1)
+	its.Nil(err)
+	its.True(found)
+	its.Equal("buzz", verify.Name)
+	its.NotNil(verify.DeletedUTC)
+
+	var all []versionedObj
+	its.Nil(defaultDB().Invoke(OptTx(tx)).All(&all))
+	its.Empty(all)
+	its.Nil(defaultDB().Invoke(OptTx(tx), OptIncludeDeleted()).All(&all))
+	its.Len(all, 1)
+}
+
+type softDeletedObj struct {
+	ID         int       `db:"id,pk"`
+	Name       string    `db:"name"`
+	DeletedUTC time.Time `db:"deleted_utc,deleted_at"`
+}
+
+func (softDeletedObj) TableName() string { return "soft_deleted_obj" }
+
+func Test_Invocation_softDeleteTime(t *testing.T) {
+	its := assert.New(t)
+
+	conn, err := Open(New(OptSQLite(filepath.Join(its.T.TempDir(), "test.db"))))
+	its.Nil(err)
+	defer func() { _ = conn.Close() }()
+	its.Nil(IgnoreExecResult(conn.Exec("CREATE TABLE soft_deleted_obj (id INTEGER PRIMARY KEY, name TEXT NOT NULL, deleted_utc TIMESTAMP)")))
+
+	obj := softDeletedObj{ID: 1, Name: "foo"}
+	its.Nil(conn.Invoke().Create(&obj))
+
+	var verify softDeletedObj
+	found, err := conn.Invoke().Get(&verify, 1)
+	its.Nil(err)
+	its.True(found)
+	its.Equal("foo", verify.Name)
+	its.True(verify.DeletedUTC.IsZero())
+
+	deleted, err := conn.Invoke().Delete(&verify)
+	its.Nil(err)
+	its.True(deleted)
+	its.False(verify.DeletedUTC.IsZero())
+
+	found, err = conn.Invoke().Get(&verify, 1)
+	its.Nil(err)
+	its.False(found)
+}
+
+type lastInsertIDResult int64
+
+func (r lastInsertIDResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r lastInsertIDResult) RowsAffected() (int64, error) { return 1, nil }
+
+func Test_Invocation_generateMySQL(t *testing.T) {
+	its := assert.New(t)
+	invocation := &Invocation{
+		BufferPool: bufferutil.NewPool(16),
+		Config:     Config{Dialect: string(DialectMySQL)},
+	}
+
+	_, queryBody, _, autos := invocation.generateCreate(&benchObj{})
+	its.Equal("INSERT INTO bench_object (uuid,name,timestamp_utc,amount,pending,category) VALUES (?,?,?,?,?,?)", queryBody)
+	its.Equal([]string{"id"}, autos.ColumnNames())
+
+	obj := benchObj{}
+	its.Nil(invocation.setLastInsertID(&obj, autos, lastInsertIDResult(42)))
+	its.Equal(42, obj.ID)
+
+	_, queryBody, _, autos = invocation.generateCreate(&benchObj{ID: 42})
+	its.Equal("INSERT INTO bench_object (uuid,name,timestamp_utc,amount,pending,category,id) VALUES (?,?,?,?,?,?,?)", queryBody)
+	its.Zero(autos.Len())
+
+	_, queryBody, _ = invocation.generateCreateIfNotExists(&benchObj{ID: 42})
+	its.Equal("INSERT INTO bench_object (uuid,name,timestamp_utc,amount,pending,category,id) VALUES (?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE id = id", queryBody)
+
+	queryBody, _, _ = invocation.generateUpsertMany([]benchObj{{}, {}})
+	its.Equal("INSERT INTO bench_object (uuid,name,timestamp_utc,amount,pending,category) VALUES (?,?,?,?,?,?),(?,?,?,?,?,?) ON DUPLICATE KEY UPDATE uuid=VALUES(uuid),name=VALUES(name),timestamp_utc=VALUES(timestamp_utc),amount=VALUES(amount),pending=VALUES(pending),category=VALUES(category)", queryBody)
+
+	_, queryBody, autos, _ = invocation.generateUpsert(&benchObj{})
+	its.Equal("INSERT INTO bench_object (uuid,name,timestamp_utc,amount,pending,category) VALUES (?,?,?,?,?,?) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id),uuid = VALUES(uuid),name = VALUES(name),timestamp_utc = VALUES(timestamp_utc),amount = VALUES(amount),pending = VALUES(pending),category = VALUES(category)", queryBody)
+	its.Equal([]string{"id"}, autos.ColumnNames())
+
+	_, queryBody, autos, _ = invocation.generateUpsert(versionedObj{})
+	its.Equal("INSERT INTO versioned_obj (id,name,version,deleted_utc) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE name = IF(version = VALUES(version), VALUES(name), name),deleted_utc = IF(version = VALUES(version), VALUES(deleted_utc), deleted_utc),version = IF(version = VALUES(version), version + 1, version)", queryBody)
+	its.Zero(autos.Len())
+
+	_, queryBody, _, _ = invocation.generateUpdate(versionedObj{})
+	its.Equal("UPDATE versioned_obj SET name = ?,deleted_utc = ?,version = version + 1 WHERE id = ? AND version = ?", queryBody)
+
+	_, queryBody, _, err := invocation.generateDelete(versionedObj{})
+	its.Nil(err)
+	its.Equal("UPDATE versioned_obj SET deleted_utc = ? WHERE id = ? AND deleted_utc IS NULL", queryBody)
+}
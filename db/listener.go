@@ -0,0 +1,382 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/zpkg/blend-go-sdk/async"
+	"github.com/zpkg/blend-go-sdk/ex"
+	"github.com/zpkg/blend-go-sdk/logger"
+)
+
+// Notification is a notification sent with `NOTIFY` (or `pg_notify`) on a channel.
+type Notification struct {
+	Channel string
+	Payload string
+	// PID is the process id of the server backend that sent the notification.
+	PID uint32
+}
+
+// Unmarshal decodes a json payload into a given value.
+func (n Notification) Unmarshal(v interface{}) error {
+	return ex.New(json.Unmarshal([]byte(n.Payload), v))
+}
+
+// NotificationHandler handles a notification.
+type NotificationHandler func(context.Context, Notification) error
+
+// NewListener returns a new listener for notifications on a connection.
+//
+// The listener holds its own pgx connection to the database, separate from the connection pool,
+// created from the connection config.
+//
+// Example:
+//
+//	listener := db.NewListener(conn)
+//	listener.Listen("cache_invalidation", func(ctx context.Context, n db.Notification) error {
+//		cache.Remove(n.Payload)
+//		return nil
+//	})
+//	go listener.Start()
+//	<-listener.NotifyStarted()
+func NewListener(conn *Connection, options ...ListenerOption) *Listener {
+	l := Listener{
+		Latch:   async.NewLatch(),
+		Conn:    conn,
+		Context: context.Background(),
+	}
+	for _, option := range options {
+		option(&l)
+	}
+	return &l
+}
+
+// ListenerOption is an option for a listener.
+type ListenerOption func(*Listener)
+
+// OptListenerContext sets the listener context.
+func OptListenerContext(ctx context.Context) ListenerOption {
+	return func(l *Listener) { l.Context = ctx }
+}
+
+// OptListenerReconnectDelay sets the delay before the first reconnect attempt after a failed connection attempt.
+func OptListenerReconnectDelay(d time.Duration) ListenerOption {
+	return func(l *Listener) { l.ReconnectDelay = d }
+}
+
+// OptListenerMaxReconnectDelay sets the maximum delay between reconnect attempts.
+func OptListenerMaxReconnectDelay(d time.Duration) ListenerOption {
+	return func(l *Listener) { l.MaxReconnectDelay = d }
+}
+
+// OptListenerHandler adds a handler for a channel.
+func OptListenerHandler(channel string, handler NotificationHandler) ListenerOption {
+	return func(l *Listener) { l.Listen(channel, handler) }
+}
+
+// Listener listens for notifications on channels and delivers them to handlers.
+//
+// If the connection is lost, the listener reconnects, with a backoff between failed attempts,
+// and subscribes to every channel again. Notifications sent while the listener is disconnected are not delivered.
+//
+// Handlers are called in order on the listener goroutine, so a slow handler delays the notifications after it.
+// Each delivery triggers a `NotificationEvent` on the connection logger, and is traced if the connection
+// tracer implements `NotificationTracer`.
+type Listener struct {
+	*async.Latch
+	Conn              *Connection
+	Context           context.Context
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	handlersMu sync.Mutex
+	handlers   map[string][]NotificationHandler
+	interrupt  context.CancelFunc
+}
+
+// ReconnectDelayOrDefault returns the reconnect delay or a default.
+func (l *Listener) ReconnectDelayOrDefault() time.Duration {
+	if l.ReconnectDelay > 0 {
+		return l.ReconnectDelay
+	}
+	return DefaultListenerReconnectDelay
+}
+
+// MaxReconnectDelayOrDefault returns the max reconnect delay or a default.
+func (l *Listener) MaxReconnectDelayOrDefault() time.Duration {
+	if l.MaxReconnectDelay > 0 {
+		return l.MaxReconnectDelay
+	}
+	return DefaultListenerMaxReconnectDelay
+}
+
+// Listen adds a handler for a channel.
+//
+// It can be called while the listener is running; the listener subscribes to new channels
+// before it waits for the next notification.
+func (l *Listener) Listen(channel string, handler NotificationHandler) {
+	l.handlersMu.Lock()
+	defer l.handlersMu.Unlock()
+	if l.handlers == nil {
+		l.handlers = make(map[string][]NotificationHandler)
+	}
+	l.handlers[channel] = append(l.handlers[channel], handler)
+	if l.interrupt != nil {
+		l.interrupt()
+	}
+}
+
+// Channels returns the channels the listener has handlers for, sorted by name.
+func (l *Listener) Channels() (output []string) {
+	l.handlersMu.Lock()
+	defer l.handlersMu.Unlock()
+	for channel := range l.handlers {
+		output = append(output, channel)
+	}
+	sort.Strings(output)
+	return
+}
+
+/*
+Start starts the listener.
+
+It will return an ErrCannotStart if the listener is already started.
+
+This call will block.
+*/
+func (l *Listener) Start() error {
+	if !l.CanStart() {
+		return ex.New(async.ErrCannotStart)
+	}
+	l.Starting()
+	return l.Dispatch()
+}
+
+// Stop stops the listener and closes its connection.
+func (l *Listener) Stop() error {
+	if !l.CanStop() {
+		return ex.New(async.ErrCannotStop)
+	}
+	l.Stopping()
+	<-l.NotifyStopped()
+	l.Latch.Reset() // reset the latch in case we have to start again
+	return nil
+}
+
+// Dispatch is the main dispatch loop.
+func (l *Listener) Dispatch() error {
+	if l.Conn == nil || l.Conn.Config.IsZero() {
+		l.Started()
+		l.Stopped()
+		return ex.New(ErrConfigUnset)
+	}
+	if l.Conn.Config.DialectOrDefault().Is(DialectSQLite, DialectMySQL) {
+		l.Started()
+		l.Stopped()
+		return ex.New(ErrUnsupportedDialect, ex.OptMessage("listen"))
+	}
+
+	ctx, cancel := context.WithCancel(l.Context)
+	defer cancel()
+	stopping := l.NotifyStopping()
+	go func() {
+		select {
+		case <-stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	l.Started()
+	defer l.Stopped()
+
+	var conn *pgx.Conn
+	defer func() {
+		if conn != nil {
+			_ = conn.Close(context.Background())
+		}
+	}()
+
+	var subscribed map[string]bool
+	var failures int
+	var reconnecting bool
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if conn == nil {
+			if failures > 0 && !l.sleep(ctx, l.reconnectDelay(failures)) {
+				return nil
+			}
+			var err error
+			if conn, err = pgx.Connect(ctx, l.Conn.Config.CreateDSN()); err != nil {
+				conn = nil
+				failures++
+				l.logError(ctx, err)
+				continue
+			}
+			subscribed = make(map[string]bool)
+		}
+		if err := l.subscribe(ctx, conn, subscribed); err != nil {
+			_ = conn.Close(context.Background())
+			conn = nil
+			failures++
+			l.logError(ctx, err)
+			continue
+		}
+		if reconnecting {
+			logger.MaybeInfofContext(ctx, l.Conn.Log, "db listener; reconnected and subscribed to %d channel(s)", len(subscribed))
+			reconnecting = false
+		}
+		failures = 0
+
+		notification, err := l.wait(ctx, conn, subscribed)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if notification == nil && !conn.IsClosed() {
+				continue // interrupted to subscribe to new channels
+			}
+			_ = conn.Close(context.Background())
+			conn = nil
+			reconnecting = true
+			l.logError(ctx, err)
+		}
+		if notification != nil {
+			l.deliver(ctx, Notification{
+				Channel: notification.Channel,
+				Payload: notification.Payload,
+				PID:     notification.PID,
+			})
+		}
+	}
+}
+
+// subscribe issues a `LISTEN` for each channel that is not yet subscribed on the connection.
+func (l *Listener) subscribe(ctx context.Context, conn *pgx.Conn, subscribed map[string]bool) error {
+	for _, channel := range l.Channels() {
+		if subscribed[channel] {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return Error(err)
+		}
+		subscribed[channel] = true
+	}
+	return nil
+}
+
+// wait waits for the next notification on the connection.
+//
+// The wait is interrupted, returning a nil notification and the context error, if a channel
+// is added that is not yet subscribed.
+func (l *Listener) wait(ctx context.Context, conn *pgx.Conn, subscribed map[string]bool) (*pgconn.Notification, error) {
+	waitCtx, interrupt := context.WithCancel(ctx)
+	defer interrupt()
+
+	l.handlersMu.Lock()
+	l.interrupt = interrupt
+	for channel := range l.handlers {
+		if !subscribed[channel] {
+			interrupt()
+		}
+	}
+	l.handlersMu.Unlock()
+
+	defer func() {
+		l.handlersMu.Lock()
+		l.interrupt = nil
+		l.handlersMu.Unlock()
+	}()
+
+	notification, err := conn.WaitForNotification(waitCtx)
+	if err != nil {
+		return notification, Error(err)
+	}
+	return notification, nil
+}
+
+// deliver calls the handlers of the notification channel.
+func (l *Listener) deliver(ctx context.Context, n Notification) {
+	l.handlersMu.Lock()
+	handlers := append([]NotificationHandler(nil), l.handlers[n.Channel]...)
+	l.handlersMu.Unlock()
+
+	var finisher NotificationTraceFinisher
+	if tracer, ok := l.Conn.Tracer.(NotificationTracer); ok {
+		finisher = tracer.Notification(ctx, l.Conn.Config, n)
+	}
+
+	start := time.Now()
+	var err error
+	for _, handler := range handlers {
+		err = ex.Nest(err, l.handle(ctx, handler, n))
+	}
+
+	if l.Conn.Log != nil {
+		l.Conn.Log.TriggerContext(ctx, NewNotificationEvent(n.Channel, n.Payload, time.Now().UTC().Sub(start),
+			OptNotificationEventDatabase(l.Conn.Config.DatabaseOrDefault()),
+			OptNotificationEventEngine(l.Conn.Config.EngineOrDefault()),
+			OptNotificationEventUsername(l.Conn.Config.Username),
+			OptNotificationEventPID(n.PID),
+			OptNotificationEventErr(err),
+		))
+	}
+	if finisher != nil {
+		finisher.FinishNotification(ctx, err)
+	}
+}
+
+// handle calls a handler, recovering panics as errors.
+func (l *Listener) handle(ctx context.Context, handler NotificationHandler, n Notification) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+	err = handler(ctx, n)
+	return
+}
+
+// reconnectDelay returns the delay before a reconnect attempt, doubling with each failed attempt up to the max.
+func (l *Listener) reconnectDelay(failures int) time.Duration {
+	delay := l.ReconnectDelayOrDefault()
+	maxDelay := l.MaxReconnectDelayOrDefault()
+	for x := 1; x < failures && delay < maxDelay; x++ {
+		delay = delay << 1
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// sleep waits for a given duration, returning false if the context is done first.
+func (l *Listener) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l *Listener) logError(ctx context.Context, err error) {
+	logger.MaybeErrorContext(ctx, l.Conn.Log, err)
+}
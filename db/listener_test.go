@@ -0,0 +1,110 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/logger"
+)
+
+func Test_Notification_Unmarshal(t *testing.T) {
+	its := assert.New(t)
+
+	var payload struct {
+		Key string `json:"key"`
+	}
+	its.Nil(Notification{Payload: `{"key":"foo"}`}.Unmarshal(&payload))
+	its.Equal("foo", payload.Key)
+	its.NotNil(Notification{Payload: "not json"}.Unmarshal(&payload))
+}
+
+func Test_Listener_reconnectDelay(t *testing.T) {
+	its := assert.New(t)
+
+	l := NewListener(nil, OptListenerReconnectDelay(time.Second), OptListenerMaxReconnectDelay(5*time.Second))
+	its.Equal(time.Second, l.reconnectDelay(1))
+	its.Equal(2*time.Second, l.reconnectDelay(2))
+	its.Equal(4*time.Second, l.reconnectDelay(3))
+	its.Equal(5*time.Second, l.reconnectDelay(4))
+	its.Equal(5*time.Second, l.reconnectDelay(100))
+
+	l = NewListener(nil)
+	its.Equal(DefaultListenerReconnectDelay, l.reconnectDelay(1))
+	its.Equal(DefaultListenerMaxReconnectDelay, l.reconnectDelay(100))
+}
+
+func Test_Listener_Channels(t *testing.T) {
+	its := assert.New(t)
+
+	noop := func(context.Context, Notification) error { return nil }
+	l := NewListener(nil, OptListenerHandler("b", noop), OptListenerHandler("a", noop))
+	l.Listen("a", noop)
+	its.Equal([]string{"a", "b"}, l.Channels())
+	its.Len(l.handlers["a"], 2)
+}
+
+func Test_Listener(t *testing.T) {
+	its := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	conn := *defaultDB()
+	conn.Log = logger.Memory(buf)
+
+	received := make(chan Notification, 16)
+	handler := func(_ context.Context, n Notification) error {
+		received <- n
+		return nil
+	}
+	l := NewListener(&conn, OptListenerReconnectDelay(10*time.Millisecond), OptListenerHandler("listener_test", handler))
+	go func() { _ = l.Start() }()
+	<-l.NotifyStarted()
+	defer func() { its.Nil(l.Stop()) }()
+
+	// notify until the notification is received, as the listener subscribes asynchronously.
+	notifyUntilReceived := func(channel, payload string) Notification {
+		deadline := time.After(5 * time.Second)
+		tick := time.NewTicker(50 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			its.Nil(conn.Notify(context.Background(), channel, payload))
+			select {
+			case n := <-received:
+				if n.Channel == channel && n.Payload == payload {
+					return n
+				}
+			case <-tick.C:
+			case <-deadline:
+				its.FailNow(fmt.Sprintf("timed out waiting for a notification on %s", channel))
+			}
+		}
+	}
+
+	n := notifyUntilReceived("listener_test", "foo")
+	its.Equal("listener_test", n.Channel)
+	its.Equal("foo", n.Payload)
+	its.NotZero(n.PID)
+
+	l.Listen("listener_test_added", handler)
+	n = notifyUntilReceived("listener_test_added", "bar")
+	its.Equal("listener_test_added", n.Channel)
+
+	// the listener should reconnect and subscribe again if its connection is terminated.
+	its.Nil(IgnoreExecResult(conn.Exec(`SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE pid <> pg_backend_pid() AND query LIKE 'LISTEN %'`)))
+	n = notifyUntilReceived("listener_test", "baz")
+	its.Equal("baz", n.Payload)
+	n = notifyUntilReceived("listener_test_added", "buzz")
+	its.Equal("buzz", n.Payload)
+
+	its.Contains(buf.String(), "[listener_test] foo")
+}
@@ -19,6 +19,11 @@ type Action interface {
 	Action(context.Context, *db.Connection, *sql.Tx) error
 }
 
+// Reversible is a type that represents a migration action that can be undone.
+type Reversible interface {
+	Rollback(context.Context, *db.Connection, *sql.Tx) error
+}
+
 // ActionFunc is a function that can be run during a migration step.
 type ActionFunc func(context.Context, *db.Connection, *sql.Tx) error
 
@@ -31,8 +36,16 @@ func (a ActionFunc) Action(ctx context.Context, conn *db.Connection, tx *sql.Tx)
 func NoOp(_ context.Context, _ *db.Connection, _ *sql.Tx) error { return nil }
 
 // Statements returns a body func that executes the statments serially.
+//
+// If the context has a plan, the statements are recorded to the plan instead.
 func Statements(statements ...string) Action {
 	return ActionFunc(func(ctx context.Context, c *db.Connection, tx *sql.Tx) (err error) {
+		if plan := GetContextPlan(ctx); plan != nil {
+			for _, statement := range statements {
+				plan.Record(statement)
+			}
+			return
+		}
 		for _, statement := range statements {
 			err = db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(statement))
 			if err != nil {
@@ -45,8 +58,14 @@ func Statements(statements ...string) Action {
 
 // Exec creates an Action that will run a statement with a given set of arguments.
 // It can be used in lieu of Statements, when parameterization is needed
+//
+// If the context has a plan, the statement is recorded to the plan instead.
 func Exec(statement string, args ...interface{}) Action {
 	return ActionFunc(func(ctx context.Context, c *db.Connection, tx *sql.Tx) (err error) {
+		if plan := GetContextPlan(ctx); plan != nil {
+			plan.Record(statement, args...)
+			return
+		}
 		err = db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(statement, args...))
 		return
 	})
@@ -9,8 +9,19 @@ package migration
 
 // Migration Stats
 const (
-	StatApplied = "applied"
-	StatFailed  = "failed"
-	StatSkipped = "skipped"
-	StatTotal   = "total"
+	StatApplied    = "applied"
+	StatRolledBack = "rolled back"
+	StatFailed     = "failed"
+	StatSkipped    = "skipped"
+	StatTotal      = "total"
 )
+
+// Migration Plan Stats
+const (
+	StatWouldApply    = "would apply"
+	StatWouldRollBack = "would roll back"
+	StatWouldSkip     = "would skip"
+)
+
+// DefaultHistoryTable is the default name of the table that records applied versioned migrations.
+const DefaultHistoryTable = "schema_migrations"
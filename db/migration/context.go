@@ -40,3 +40,16 @@ func GetContextLabels(ctx context.Context) []string {
 	}
 	return nil
 }
+
+type rollbackKey struct{}
+
+// withRollback marks a context as rolling back, so guards report applied steps as rolled back.
+func withRollback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rollbackKey{}, true)
+}
+
+// isRollback returns if a context is rolling back.
+func isRollback(ctx context.Context) bool {
+	value, _ := ctx.Value(rollbackKey{}).(bool)
+	return value
+}
@@ -9,5 +9,16 @@ Use of this source code is governed by a MIT license that can be found in the LI
 Package migration provides helpers for writing rerunnable database migrations.
 
 These are built around Suites, which are sets of Groups that execute within a transaction, those Groups are composed of Steps, which are a Guard and an Action.
+
+Suites can also hold versioned Migrations, loaded from numbered `.sql` files with `LoadMigrations` or
+created from Go actions with `NewMigration`. These are applied at most once each by `Suite.Up` and `Suite.UpTo`,
+and recorded with their checksums in a history table; `Suite.Status` reports which have been applied.
+
+Migrations with a down action, e.g. from a matching `.down.sql` file, can be rolled back newest first with
+`Suite.Rollback` and `Suite.RollbackTo`. Steps and groups can likewise declare down actions with `OptStepDown`
+and `OptGroupDown`, and are undone in reverse order by `Group.Rollback`.
+
+A suite can be dry run by applying it with a context from `WithPlan`; guard predicates are evaluated read only,
+and the statements that would run are recorded to a `Plan`, which can be written as a sql script or a json report.
 */
 package migration // import "github.com/zpkg/blend-go-sdk/db/migration"
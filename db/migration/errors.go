@@ -0,0 +1,36 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import "github.com/zpkg/blend-go-sdk/ex"
+
+const (
+	// ErrMigrationFileName is returned when a migration file name is not of the form `<version>_<name>.sql`.
+	ErrMigrationFileName ex.Class = "migration: invalid migration file name"
+	// ErrMigrationVersionDuplicate is returned when more than one migration has the same version.
+	ErrMigrationVersionDuplicate ex.Class = "migration: duplicate migration version"
+	// ErrMigrationModified is returned when an applied migration has been edited since it was applied.
+	ErrMigrationModified ex.Class = "migration: applied migration has been modified"
+	// ErrIrreversible is returned when rolling back a step, group or migration that has no down action.
+	ErrIrreversible ex.Class = "migration: action cannot be rolled back"
+)
+
+// IsMigrationModified returns if an error is an `ErrMigrationModified`.
+func IsMigrationModified(err error) bool {
+	return ex.Is(err, ErrMigrationModified)
+}
+
+// IsMigrationVersionDuplicate returns if an error is an `ErrMigrationVersionDuplicate`.
+func IsMigrationVersionDuplicate(err error) bool {
+	return ex.Is(err, ErrMigrationVersionDuplicate)
+}
+
+// IsIrreversible returns if an error is an `ErrIrreversible`.
+func IsIrreversible(err error) bool {
+	return ex.Is(err, ErrIrreversible)
+}
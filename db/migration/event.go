@@ -52,9 +52,9 @@ func (e Event) GetFlag() string { return Flag }
 func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 	resultColor := ansi.ColorBlue
 	switch e.Result {
-	case "skipped":
+	case StatSkipped, StatWouldSkip:
 		resultColor = ansi.ColorYellow
-	case "failed":
+	case StatFailed:
 		resultColor = ansi.ColorRed
 	}
 
@@ -35,16 +35,76 @@ func NewGroupWithAction(guard GuardFunc, action Action, options ...GroupOption)
 // It uses normally transactions to apply these actions as an atomic unit, but this transaction can be bypassed by
 // setting the SkipTransaction flag to true. This allows the use of CONCURRENT index creation and other operations that
 // postgres will not allow within a transaction.
+//
+// A group is rolled back with its Down action if set, otherwise by rolling back each of its actions in reverse order.
 type Group struct {
 	Actions         []Action
+	Down            Action
 	Tx              *sql.Tx
 	SkipTransaction bool
 }
 
 // Action runs the groups actions within a transaction.
-func (ga *Group) Action(ctx context.Context, c *db.Connection) (err error) {
+func (ga *Group) Action(ctx context.Context, c *db.Connection) error {
+	return ga.inTx(ctx, c, func(tx *sql.Tx) error {
+		for _, a := range ga.Actions {
+			if err := a.Action(ctx, c, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback undoes the group within a transaction, running its down action or
+// rolling back its actions in reverse order.
+//
+// It returns an `ErrIrreversible` without running anything if the group has no down action
+// and any of its actions do not implement Reversible.
+func (ga *Group) Rollback(ctx context.Context, c *db.Connection) error {
+	ctx = withRollback(ctx)
+	if ga.Down != nil {
+		return ga.inTx(ctx, c, func(tx *sql.Tx) error {
+			return ga.Down.Action(ctx, c, tx)
+		})
+	}
+
+	reversible := make([]Reversible, len(ga.Actions))
+	for index, a := range ga.Actions {
+		typed, ok := a.(Reversible)
+		if !ok {
+			return ex.New(ErrIrreversible, ex.OptMessagef("action: %T", a))
+		}
+		reversible[index] = typed
+	}
+	return ga.inTx(ctx, c, func(tx *sql.Tx) error {
+		for index := len(reversible) - 1; index >= 0; index-- {
+			if err := reversible[index].Rollback(ctx, c, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// inTx calls a given function within the group transaction, or a new transaction
+// which it commits or rolls back, unless the group skips transactions.
+//
+// If the context has a plan, the function is called within a new read only transaction
+// which is always rolled back.
+func (ga *Group) inTx(ctx context.Context, c *db.Connection, action func(*sql.Tx) error) (err error) {
 	var tx *sql.Tx
-	if ga.Tx != nil { // if we have a transaction provided to us
+	if GetContextPlan(ctx) != nil {
+		tx, err = c.BeginContext(ctx, func(opts *sql.TxOptions) { opts.ReadOnly = true })
+		if err != nil {
+			return
+		}
+		defer func() {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = ex.Nest(err, txErr)
+			}
+		}()
+	} else if ga.Tx != nil { // if we have a transaction provided to us
 		tx = ga.Tx
 	} else if !ga.SkipTransaction { // if we aren't told to skip transactions
 		tx, err = c.Begin()
@@ -64,12 +124,6 @@ func (ga *Group) Action(ctx context.Context, c *db.Connection) (err error) {
 		}()
 	}
 
-	for _, a := range ga.Actions {
-		err = a.Action(ctx, c, tx)
-		if err != nil {
-			return
-		}
-	}
-
+	err = action(tx)
 	return
 }
@@ -24,6 +24,14 @@ func OptGroupActions(actions ...Action) GroupOption {
 	}
 }
 
+// OptGroupDown sets an action that undoes the whole group when it is rolled back,
+// instead of rolling back each of its actions.
+func OptGroupDown(action Action) GroupOption {
+	return func(g *Group) {
+		g.Down = action
+	}
+}
+
 // OptGroupSkipTransaction will allow this group to be run outside of a transaction. Use this to concurrently create indices
 // and perform other actions that cannot be executed in a Tx
 func OptGroupSkipTransaction() GroupOption {
@@ -8,9 +8,12 @@ Use of this source code is governed by a MIT license that can be found in the LI
 package migration
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
 )
 
 func TestNewGroup(t *testing.T) {
@@ -19,3 +22,59 @@ func TestNewGroup(t *testing.T) {
 	g := NewGroup(OptGroupActions(NewStep(Always(), ActionFunc(NoOp))))
 	assert.Len(g.Actions, 1)
 }
+
+func recordAction(calls *[]string, name string) Action {
+	return ActionFunc(func(_ context.Context, _ *db.Connection, _ *sql.Tx) error {
+		*calls = append(*calls, name)
+		return nil
+	})
+}
+
+func TestGroup_Rollback(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	g := NewGroup(
+		OptGroupSkipTransaction(),
+		OptGroupActions(
+			NewStep(Always(), recordAction(&calls, "up 1"), OptStepDown(Always(), recordAction(&calls, "down 1"))),
+			NewStep(Always(), recordAction(&calls, "up 2"), OptStepDown(Always(), recordAction(&calls, "down 2"))),
+		),
+	)
+
+	s := New()
+	ctx := WithSuite(context.Background(), s)
+	assert.Nil(g.Action(ctx, nil))
+	assert.Nil(g.Rollback(ctx, nil))
+	assert.Equal([]string{"up 1", "up 2", "down 2", "down 1"}, calls)
+	assert.Equal(4, s.Applied)
+	assert.Equal(2, s.RolledBack)
+}
+
+func TestGroup_Rollback_down(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	g := NewGroup(
+		OptGroupSkipTransaction(),
+		OptGroupActions(recordAction(&calls, "up")),
+		OptGroupDown(recordAction(&calls, "down")),
+	)
+	assert.Nil(g.Rollback(context.Background(), nil))
+	assert.Equal([]string{"down"}, calls)
+}
+
+func TestGroup_Rollback_irreversible(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	g := NewGroup(
+		OptGroupSkipTransaction(),
+		OptGroupActions(
+			NewStep(Always(), recordAction(&calls, "up 1"), OptStepDown(Always(), recordAction(&calls, "down 1"))),
+			recordAction(&calls, "up 2"),
+		),
+	)
+	assert.True(IsIrreversible(g.Rollback(context.Background(), nil)))
+	assert.Empty(calls)
+}
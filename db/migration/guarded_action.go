@@ -12,23 +12,47 @@ import (
 	"database/sql"
 
 	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
 )
 
 // NewStep returns a new Step, given a GuardFunc and an Action
-func NewStep(guard GuardFunc, action Action) *Step {
-	return &Step{
+func NewStep(guard GuardFunc, action Action, options ...StepOption) *Step {
+	s := Step{
 		Guard: guard,
 		Body:  action,
 	}
+	for _, opt := range options {
+		opt(&s)
+	}
+	return &s
+}
+
+// StepOption mutates a step.
+type StepOption func(*Step)
+
+// OptStepDown sets the guarded action that undoes the step when its group is rolled back.
+func OptStepDown(guard GuardFunc, action Action) StepOption {
+	return func(s *Step) { s.Down = NewStep(guard, action) }
 }
 
 // Step is a guarded action. The GuardFunc will decide whether to execute this Action
 type Step struct {
 	Guard GuardFunc
 	Body  Action
+	// Down is the guarded action that undoes the step, if any.
+	Down *Step
 }
 
 // Action implements the Actionable interface and runs the body if the provided guard passes.
 func (ga *Step) Action(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
 	return ga.Guard(ctx, c, tx, ga.Body)
 }
+
+// Rollback implements the Reversible interface and runs the down step.
+// It returns an `ErrIrreversible` if the step has no down step.
+func (ga *Step) Rollback(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+	if ga.Down == nil {
+		return ex.New(ErrIrreversible)
+	}
+	return ga.Down.Action(ctx, c, tx)
+}
@@ -8,9 +8,12 @@ Use of this source code is governed by a MIT license that can be found in the LI
 package migration
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
 )
 
 func TestStep(t *testing.T) {
@@ -20,3 +23,20 @@ func TestStep(t *testing.T) {
 	assert.NotNil(step.Guard)
 	assert.NotNil(step.Body)
 }
+
+func TestStep_Rollback(t *testing.T) {
+	assert := assert.New(t)
+
+	step := NewStep(Always(), ActionFunc(NoOp))
+	assert.Nil(step.Down)
+	assert.True(IsIrreversible(step.Rollback(context.Background(), nil, nil)))
+
+	var rolledBack bool
+	step = NewStep(Always(), ActionFunc(NoOp), OptStepDown(Always(), ActionFunc(func(_ context.Context, _ *db.Connection, _ *sql.Tx) error {
+		rolledBack = true
+		return nil
+	})))
+	assert.NotNil(step.Down)
+	assert.Nil(step.Rollback(context.Background(), nil, nil))
+	assert.True(rolledBack)
+}
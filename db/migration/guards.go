@@ -52,7 +52,11 @@ func Guard(description string, predicate GuardPredicateFunc) GuardFunc {
 			return err
 		}
 		if suite := GetContextSuite(ctx); suite != nil {
-			suite.Applyf(ctx, description)
+			if isRollback(ctx) {
+				suite.RolledBackf(ctx, description)
+			} else {
+				suite.Applyf(ctx, description)
+			}
 		}
 		return nil
 	}
@@ -0,0 +1,144 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/db"
+)
+
+// HistoryEntry is a row of the migration history table.
+type HistoryEntry struct {
+	Version     int64
+	Name        string
+	Checksum    string
+	AppliedUTC  time.Time
+	ExecutionMS int64
+}
+
+// historyTableStatement is the statement that creates the history table.
+const historyTableStatement = `CREATE TABLE IF NOT EXISTS %s (
+		version bigint not null primary key
+		, name text not null
+		, checksum text not null
+		, applied_utc %s not null
+		, execution_ms bigint not null
+	)`
+
+// createHistoryTableStatement returns the statement that creates the history table for a dialect.
+//
+// Mysql timestamps are limited to 2038, and may be updated implicitly, so a datetime is used instead.
+func createHistoryTableStatement(dialect db.Dialect, tableName string) string {
+	if dialect.Is(db.DialectMySQL) {
+		return fmt.Sprintf(historyTableStatement, tableName, "datetime(6)")
+	}
+	return fmt.Sprintf(historyTableStatement, tableName, "timestamp")
+}
+
+// ensureHistoryTable creates the history table if it does not exist.
+func ensureHistoryTable(ctx context.Context, c *db.Connection, tableName string) error {
+	return db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptLabel("migration_history_create")).Exec(createHistoryTableStatement(c.Config.DialectOrDefault(), tableName)))
+}
+
+// loadHistory ensures the history table exists and returns its entries by version.
+//
+// If the context has a plan, a missing history table is not created; it is added to the plan
+// as a step that would create it, and read as empty.
+func loadHistory(ctx context.Context, c *db.Connection, tableName string) (map[int64]HistoryEntry, error) {
+	if plan := GetContextPlan(ctx); plan != nil {
+		exists, err := historyTableExists(ctx, c, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			plan.Record(createHistoryTableStatement(c.Config.DialectOrDefault(), tableName))
+			plan.addStep(StatWouldApply, tableName, GetContextLabels(ctx))
+			return map[int64]HistoryEntry{}, nil
+		}
+		return readHistory(ctx, c, nil, tableName)
+	}
+	if err := ensureHistoryTable(ctx, c, tableName); err != nil {
+		return nil, err
+	}
+	return readHistory(ctx, c, nil, tableName)
+}
+
+// historyTableExists returns if the history table exists.
+func historyTableExists(ctx context.Context, c *db.Connection, tableName string) (exists bool, err error) {
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite, db.DialectMySQL) {
+		schemaName, name := "", tableName
+		if index := strings.LastIndex(tableName, "."); index >= 0 {
+			schemaName, name = tableName[:index], tableName[index+1:]
+		}
+		return PredicateTableExistsInSchema(ctx, c, nil, schemaName, name)
+	}
+	_, err = c.Invoke(db.OptContext(ctx), db.OptPrimary(), db.OptLabel("migration_history_exists")).Query(
+		"SELECT to_regclass($1) IS NOT NULL", tableName,
+	).Scan(&exists)
+	return
+}
+
+// readHistory returns the entries of the history table by version.
+func readHistory(ctx context.Context, c *db.Connection, tx *sql.Tx, tableName string) (map[int64]HistoryEntry, error) {
+	history := map[int64]HistoryEntry{}
+	err := c.Invoke(db.OptContext(ctx), db.OptTx(tx), db.OptPrimary(), db.OptLabel("migration_history_read")).Query(
+		fmt.Sprintf("SELECT version, name, checksum, applied_utc, execution_ms FROM %s", tableName),
+	).Each(func(r db.Rows) error {
+		var entry HistoryEntry
+		if err := r.Scan(&entry.Version, &entry.Name, &entry.Checksum, &entry.AppliedUTC, &entry.ExecutionMS); err != nil {
+			return err
+		}
+		history[entry.Version] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// PredicateVersionNotApplied returns if a migration version is not recorded in a given history table.
+func PredicateVersionNotApplied(ctx context.Context, c *db.Connection, tx *sql.Tx, tableName string, version int64) (bool, error) {
+	return c.Invoke(db.OptContext(ctx), db.OptTx(tx), db.OptPrimary()).Query(
+		fmt.Sprintf("SELECT 1 FROM %s WHERE version = %s", tableName, c.Config.DialectOrDefault().Placeholder(1)),
+		version,
+	).None()
+}
+
+// PredicateVersionApplied returns if a migration version is recorded in a given history table.
+func PredicateVersionApplied(ctx context.Context, c *db.Connection, tx *sql.Tx, tableName string, version int64) (bool, error) {
+	return Not(PredicateVersionNotApplied(ctx, c, tx, tableName, version))
+}
+
+// recordApplied writes a history entry for an applied migration.
+func recordApplied(ctx context.Context, c *db.Connection, tx *sql.Tx, tableName string, m *Migration, elapsed time.Duration) error {
+	dialect := c.Config.DialectOrDefault()
+	statement := fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_utc, execution_ms) VALUES (%s, %s, %s, %s, %s)", tableName,
+		dialect.Placeholder(1), dialect.Placeholder(2), dialect.Placeholder(3), dialect.Placeholder(4), dialect.Placeholder(5))
+	args := []interface{}{m.Version, m.Name, m.Checksum, time.Now().UTC(), elapsed.Milliseconds()}
+	if plan := GetContextPlan(ctx); plan != nil {
+		plan.Record(statement, args...)
+		return nil
+	}
+	return db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx), db.OptLabel("migration_history_insert")).Exec(statement, args...))
+}
+
+// removeApplied deletes the history entry of a rolled back migration.
+func removeApplied(ctx context.Context, c *db.Connection, tx *sql.Tx, tableName string, m *Migration) error {
+	statement := fmt.Sprintf("DELETE FROM %s WHERE version = %s", tableName, c.Config.DialectOrDefault().Placeholder(1))
+	if plan := GetContextPlan(ctx); plan != nil {
+		plan.Record(statement, m.Version)
+		return nil
+	}
+	return db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx), db.OptLabel("migration_history_delete")).Exec(statement, m.Version))
+}
@@ -0,0 +1,195 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// migrationFileName matches migration file names, e.g. `0001_create_users.sql` or `0001_create_users.down.sql`.
+var migrationFileName = regexp.MustCompile(`^([0-9]+)_([^.]+)(\.up|\.down)?\.sql$`)
+
+// NewMigration returns a new versioned migration for a go action.
+//
+// Go actions have no checksum unless one is set with `OptMigrationChecksum`, so edits to them are not detected.
+func NewMigration(version int64, name string, up Action, options ...MigrationOption) *Migration {
+	m := Migration{
+		Version: version,
+		Name:    name,
+		Up:      up,
+	}
+	for _, opt := range options {
+		opt(&m)
+	}
+	return &m
+}
+
+// MigrationOption mutates a migration.
+type MigrationOption func(*Migration)
+
+// OptMigrationChecksum sets the checksum of a migration.
+func OptMigrationChecksum(checksum string) MigrationOption {
+	return func(m *Migration) { m.Checksum = checksum }
+}
+
+// OptMigrationDown sets the action that rolls back a migration.
+func OptMigrationDown(down Action) MigrationOption {
+	return func(m *Migration) { m.Down = down }
+}
+
+// OptMigrationSkipTransaction runs the migration, and its rollback, outside of a transaction,
+// e.g. to create an index concurrently.
+func OptMigrationSkipTransaction() MigrationOption {
+	return func(m *Migration) { m.SkipTransaction = true }
+}
+
+// Migration is a versioned migration, applied at most once and recorded in the history table.
+type Migration struct {
+	Version         int64
+	Name            string
+	Checksum        string
+	Up              Action
+	Down            Action
+	SkipTransaction bool
+}
+
+// Label returns the label of the migration used in events, e.g. `0001_create_users`.
+func (m Migration) Label() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// DownAction returns the action that rolls back the migration; this is the down action if set,
+// otherwise the rollback of the up action if it is Reversible, e.g. a Step with a down step.
+//
+// It returns nil if the migration cannot be rolled back.
+func (m Migration) DownAction() Action {
+	if m.Down != nil {
+		return m.Down
+	}
+	if typed, ok := m.Up.(*Step); ok && typed.Down == nil {
+		return nil
+	}
+	if typed, ok := m.Up.(Reversible); ok {
+		return ActionFunc(typed.Rollback)
+	}
+	return nil
+}
+
+// LoadMigrations loads versioned migrations from the `.sql` files in a directory of a filesystem,
+// e.g. an `embed.FS` or `os.DirFS`.
+//
+// Files must be named `<version>_<name>.sql` or `<version>_<name>.up.sql`; other files are ignored.
+// A migration can be rolled back if it has a matching `<version>_<name>.down.sql` file.
+// The contents of each file are executed as a single statement, within a transaction unless
+// either file contains the line `-- migration:no-transaction`. The checksum covers both the up and
+// the down file, so editing either is detected.
+func LoadMigrations(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	var ups []*Migration
+	downs := map[int64]*Migration{}
+	upContents := map[int64][]byte{}
+	downContents := map[int64][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, ex.New(ErrMigrationFileName, ex.OptMessagef("file: %s", entry.Name()))
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, ex.New(ErrMigrationFileName, ex.OptMessagef("file: %s", entry.Name()), ex.OptInner(err))
+		}
+		contents, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, ex.New(err)
+		}
+		m := &Migration{
+			Version:         version,
+			Name:            matches[2],
+			SkipTransaction: hasNoTransactionDirective(string(contents)),
+		}
+		if matches[3] == ".down" {
+			if existing, ok := downs[version]; ok {
+				return nil, ex.New(ErrMigrationVersionDuplicate, ex.OptMessagef("version: %d, down migrations: %s, %s", version, existing.Label(), m.Label()))
+			}
+			m.Down = Statements(string(contents))
+			downs[version] = m
+			downContents[version] = contents
+			continue
+		}
+		m.Up = Statements(string(contents))
+		upContents[version] = contents
+		ups = append(ups, m)
+	}
+	if err = validateMigrations(ups); err != nil {
+		return nil, err
+	}
+	for _, m := range ups {
+		m.Checksum = Checksum(upContents[m.Version])
+		if down, ok := downs[m.Version]; ok {
+			m.Down = down.Down
+			m.SkipTransaction = m.SkipTransaction || down.SkipTransaction
+			// a separator keeps the boundary between the files from shifting undetected.
+			m.Checksum = Checksum(append(append(append([]byte{}, upContents[m.Version]...), 0), downContents[m.Version]...))
+			delete(downs, m.Version)
+		}
+	}
+	for _, down := range downs {
+		return nil, ex.New(ErrMigrationFileName, ex.OptMessagef("down migration has no up migration: %s", down.Label()))
+	}
+	return ups, nil
+}
+
+// Checksum returns the checksum of the contents of a migration.
+func Checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+func hasNoTransactionDirective(contents string) bool {
+	for _, line := range strings.Split(contents, "\n") {
+		if strings.TrimSpace(line) == "-- migration:no-transaction" {
+			return true
+		}
+	}
+	return false
+}
+
+// sortMigrations sorts migrations by version.
+func sortMigrations(migrations []*Migration) {
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+}
+
+// validateMigrations returns an error if more than one migration has the same version.
+func validateMigrations(migrations []*Migration) error {
+	seen := map[int64]*Migration{}
+	for _, m := range migrations {
+		if existing, ok := seen[m.Version]; ok {
+			return ex.New(ErrMigrationVersionDuplicate, ex.OptMessagef("version: %d, migrations: %s, %s", m.Version, existing.Label(), m.Label()))
+		}
+		seen[m.Version] = m
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	its := assert.New(t)
+
+	migrations, err := LoadMigrations(os.DirFS("testdata"), "migrations")
+	its.Nil(err)
+	its.Len(migrations, 3)
+
+	its.Equal(1, migrations[0].Version)
+	its.Equal("create_widgets", migrations[0].Name)
+	its.Equal("0001_create_widgets", migrations[0].Label())
+	its.Len(migrations[0].Checksum, 64)
+	its.False(migrations[0].SkipTransaction)
+	its.NotNil(migrations[0].Up)
+	its.NotNil(migrations[0].Down)
+	its.Nil(migrations[1].DownAction())
+
+	its.Equal(3, migrations[2].Version)
+	its.True(migrations[2].SkipTransaction)
+}
+
+func TestLoadMigrations_checksum(t *testing.T) {
+	its := assert.New(t)
+
+	migrations, err := LoadMigrations(fstest.MapFS{
+		"migrations/1_create_widgets.up.sql":   &fstest.MapFile{Data: []byte("SELECT 1")},
+		"migrations/1_create_widgets.down.sql": &fstest.MapFile{Data: []byte("SELECT 2")},
+		"migrations/2_create_gadgets.sql":      &fstest.MapFile{Data: []byte("SELECT 3")},
+	}, "migrations")
+	its.Nil(err)
+	its.Len(migrations, 2)
+	its.Equal(Checksum([]byte("SELECT 3")), migrations[1].Checksum)
+
+	edited, err := LoadMigrations(fstest.MapFS{
+		"migrations/1_create_widgets.up.sql":   &fstest.MapFile{Data: []byte("SELECT 1")},
+		"migrations/1_create_widgets.down.sql": &fstest.MapFile{Data: []byte("SELECT 4")},
+	}, "migrations")
+	its.Nil(err)
+	its.NotEqual(migrations[0].Checksum, edited[0].Checksum, "editing the down file should change the checksum")
+	its.True(isModified(edited[0], HistoryEntry{Checksum: migrations[0].Checksum}))
+}
+
+func TestLoadMigrations_invalid(t *testing.T) {
+	its := assert.New(t)
+
+	_, err := LoadMigrations(fstest.MapFS{
+		"migrations/create_widgets.sql": &fstest.MapFile{Data: []byte("SELECT 1")},
+	}, "migrations")
+	its.True(ex.Is(err, ErrMigrationFileName))
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"migrations/1_create_widgets.sql":  &fstest.MapFile{Data: []byte("SELECT 1")},
+		"migrations/01_create_gadgets.sql": &fstest.MapFile{Data: []byte("SELECT 1")},
+	}, "migrations")
+	its.True(IsMigrationVersionDuplicate(err))
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"migrations/1_create_widgets.up.sql":   &fstest.MapFile{Data: []byte("SELECT 1")},
+		"migrations/2_create_gadgets.down.sql": &fstest.MapFile{Data: []byte("SELECT 1")},
+	}, "migrations")
+	its.True(ex.Is(err, ErrMigrationFileName))
+}
+
+func TestMigration_DownAction(t *testing.T) {
+	its := assert.New(t)
+
+	its.Nil(NewMigration(1, "noop", ActionFunc(NoOp)).DownAction())
+	its.NotNil(NewMigration(1, "noop", ActionFunc(NoOp), OptMigrationDown(ActionFunc(NoOp))).DownAction())
+	its.NotNil(NewMigration(1, "noop", NewStep(Always(), ActionFunc(NoOp), OptStepDown(Always(), ActionFunc(NoOp)))).DownAction())
+}
+
+func TestChecksum(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal(Checksum([]byte("SELECT 1")), Checksum([]byte("SELECT 1")))
+	its.NotEqual(Checksum([]byte("SELECT 1")), Checksum([]byte("SELECT 2")))
+}
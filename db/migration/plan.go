@@ -0,0 +1,151 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// NewPlan returns a new plan.
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Plan is a dry run of a suite; it records the steps that would be applied or skipped,
+// and the statements they would run, instead of running them.
+//
+// A suite is planned by applying it with a context from `WithPlan`, e.g.
+//
+//	plan := migration.NewPlan()
+//	err := suite.Up(migration.WithPlan(ctx, plan), conn)
+//
+// While planning, guard predicates are evaluated within read only transactions that are rolled back,
+// and the `Statements` and `Exec` actions record their statements to the plan. Custom actions should
+// check `GetContextPlan` and call `Plan.Record` instead of running their statements.
+type Plan struct {
+	Steps []PlanStep `json:"steps"`
+
+	WouldApply int `json:"wouldApply"`
+	WouldSkip  int `json:"wouldSkip"`
+	Failed     int `json:"failed"`
+	Total      int `json:"total"`
+
+	pending []PlanStatement
+}
+
+// PlanStep is a step of a plan.
+type PlanStep struct {
+	Result      string          `json:"result"`
+	Labels      []string        `json:"labels,omitempty"`
+	Description string          `json:"description"`
+	Statements  []PlanStatement `json:"statements,omitempty"`
+}
+
+// PlanStatement is a statement a step would run.
+type PlanStatement struct {
+	Statement string        `json:"statement"`
+	Args      []interface{} `json:"args,omitempty"`
+}
+
+// Record records a statement that would be run by the current step.
+func (p *Plan) Record(statement string, args ...interface{}) {
+	p.pending = append(p.pending, PlanStatement{Statement: statement, Args: args})
+}
+
+// WriteSQL writes the plan as a sql script.
+//
+// Each step is written as a comment with its result, followed by the statements that would be run.
+// Statement arguments are written as comments, as they are not interpolated into the statements.
+func (p *Plan) WriteSQL(wr io.Writer) error {
+	for _, step := range p.Steps {
+		if _, err := fmt.Fprintf(wr, "-- %s: %s\n", step.Result, planStepName(step)); err != nil {
+			return ex.New(err)
+		}
+		for _, statement := range step.Statements {
+			if len(statement.Args) > 0 {
+				if _, err := fmt.Fprintf(wr, "-- args: %v\n", statement.Args); err != nil {
+					return ex.New(err)
+				}
+			}
+			if _, err := fmt.Fprintf(wr, "%s;\n", strings.TrimRight(strings.TrimSpace(statement.Statement), ";")); err != nil {
+				return ex.New(err)
+			}
+		}
+	}
+	_, err := fmt.Fprintf(wr, "-- %d %s %d %s %d %s %d %s\n", p.WouldApply, StatWouldApply, p.WouldSkip, StatWouldSkip, p.Failed, StatFailed, p.Total, StatTotal)
+	return ex.New(err)
+}
+
+// WriteJSON writes the plan as a json report.
+func (p *Plan) WriteJSON(wr io.Writer) error {
+	return ex.New(json.NewEncoder(wr).Encode(p))
+}
+
+// addStep adds a step with the statements recorded since the last step.
+func (p *Plan) addStep(result, description string, labels []string) {
+	switch result {
+	case StatWouldApply, StatWouldRollBack:
+		p.WouldApply++
+	case StatWouldSkip:
+		p.WouldSkip++
+	case StatFailed:
+		p.Failed++
+	}
+	p.Total++
+	p.Steps = append(p.Steps, PlanStep{
+		Result:      result,
+		Labels:      labels,
+		Description: description,
+		Statements:  p.pending,
+	})
+	p.pending = nil
+}
+
+// planResult returns the plan result for a step result.
+func planResult(result string) string {
+	switch result {
+	case StatApplied:
+		return StatWouldApply
+	case StatRolledBack:
+		return StatWouldRollBack
+	case StatSkipped:
+		return StatWouldSkip
+	default:
+		return result
+	}
+}
+
+func planStepName(step PlanStep) string {
+	if len(step.Labels) > 0 {
+		return strings.Join(append(append([]string{}, step.Labels...), step.Description), " > ")
+	}
+	return step.Description
+}
+
+type planKey struct{}
+
+// WithPlan adds a plan to a context, which makes suites applied with the context
+// record their steps to the plan instead of running them.
+func WithPlan(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, planKey{}, plan)
+}
+
+// GetContextPlan gets a plan from a context as a value.
+func GetContextPlan(ctx context.Context) *Plan {
+	value := ctx.Value(planKey{})
+	if typed, ok := value.(*Plan); ok {
+		return typed
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
+)
+
+func never() GuardFunc {
+	return Guard("never run", func(_ context.Context, _ *db.Connection, _ *sql.Tx) (bool, error) { return false, nil })
+}
+
+func TestPlan(t *testing.T) {
+	its := assert.New(t)
+
+	plan := NewPlan()
+	s := New()
+	ctx := WithLabel(WithPlan(WithSuite(context.Background(), s), plan), "widgets")
+
+	its.Nil(NewStep(Always(), Statements("CREATE TABLE widgets (id int)", "CREATE INDEX ON widgets (id);")).Action(ctx, nil, nil))
+	its.Nil(NewStep(never(), Statements("DROP TABLE widgets")).Action(ctx, nil, nil))
+	its.Nil(NewStep(Always(), Exec("INSERT INTO widgets (id) VALUES ($1)", 1)).Action(ctx, nil, nil))
+
+	its.Len(plan.Steps, 3)
+	its.Equal(StatWouldApply, plan.Steps[0].Result)
+	its.Equal([]string{"widgets"}, plan.Steps[0].Labels)
+	its.Equal("always run", plan.Steps[0].Description)
+	its.Len(plan.Steps[0].Statements, 2)
+	its.Equal(StatWouldSkip, plan.Steps[1].Result)
+	its.Empty(plan.Steps[1].Statements)
+	its.Equal([]interface{}{1}, plan.Steps[2].Statements[0].Args)
+
+	its.Equal(2, plan.WouldApply)
+	its.Equal(1, plan.WouldSkip)
+	its.Equal(0, plan.Failed)
+	its.Equal(3, plan.Total)
+	its.Equal(2, s.Applied)
+	its.Equal(1, s.Skipped)
+}
+
+func TestPlan_WriteSQL(t *testing.T) {
+	its := assert.New(t)
+
+	plan := NewPlan()
+	plan.Record("CREATE TABLE widgets (id int)")
+	plan.addStep(StatWouldApply, "create widgets", []string{"widgets"})
+	plan.addStep(StatWouldSkip, "drop widgets", nil)
+	plan.Record("INSERT INTO widgets (id) VALUES ($1);", 1)
+	plan.addStep(StatWouldApply, "seed widgets", nil)
+
+	buffer := new(bytes.Buffer)
+	its.Nil(plan.WriteSQL(buffer))
+	its.Equal(`-- would apply: widgets > create widgets
+CREATE TABLE widgets (id int);
+-- would skip: drop widgets
+-- would apply: seed widgets
+-- args: [1]
+INSERT INTO widgets (id) VALUES ($1);
+-- 2 would apply 1 would skip 0 failed 3 total
+`, buffer.String())
+}
+
+func TestPlan_WriteJSON(t *testing.T) {
+	its := assert.New(t)
+
+	plan := NewPlan()
+	plan.Record("CREATE TABLE widgets (id int)")
+	plan.addStep(StatWouldApply, "create widgets", nil)
+
+	buffer := new(bytes.Buffer)
+	its.Nil(plan.WriteJSON(buffer))
+
+	var report map[string]interface{}
+	its.Nil(json.Unmarshal(buffer.Bytes(), &report))
+	its.Equal(1.0, report["wouldApply"])
+	its.Equal(1.0, report["total"])
+	its.Len(report["steps"], 1)
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
 	"github.com/zpkg/blend-go-sdk/stringutil"
 )
 
@@ -23,7 +24,23 @@ func PredicateTableExists(ctx context.Context, c *db.Connection, tx *sql.Tx, tab
 }
 
 // PredicateTableExistsInSchema returns if a table exists in a specific schema on the given connection.
+//
+// For the sqlite dialect the schema is an attached database, where the default schema is `main`.
+// For the mysql dialect the schema is a database, where the default schema is the connection database.
 func PredicateTableExistsInSchema(ctx context.Context, c *db.Connection, tx *sql.Tx, schemaName, tableName string) (bool, error) {
+	if c.Config.DialectOrDefault().Is(db.DialectMySQL) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM information_schema.tables WHERE table_name = ? AND table_schema = `+mysqlSchemaExpression,
+			tableName,
+			unquotedMySQLSchema(schemaName),
+		).Any()
+	}
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			fmt.Sprintf(`SELECT 1 FROM %s.sqlite_master WHERE type = 'table' AND name = ?1`, sqliteSchema(schemaName)),
+			tableName,
+		).Any()
+	}
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
 		`SELECT 1 FROM pg_catalog.pg_tables WHERE tablename = $1 AND schemaname = $2`,
 		tableName,
@@ -38,6 +55,22 @@ func PredicateColumnExists(ctx context.Context, c *db.Connection, tx *sql.Tx, ta
 
 // PredicateColumnExistsInSchema returns if a column exists on a table in a specific schema on the given connection.
 func PredicateColumnExistsInSchema(ctx context.Context, c *db.Connection, tx *sql.Tx, schemaName, tableName, columnName string) (bool, error) {
+	if c.Config.DialectOrDefault().Is(db.DialectMySQL) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM information_schema.columns WHERE column_name = ? AND table_name = ? AND table_schema = `+mysqlSchemaExpression,
+			columnName,
+			tableName,
+			unquotedMySQLSchema(schemaName),
+		).Any()
+	}
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM pragma_table_info(?1, ?2) WHERE name = ?3`,
+			tableName,
+			unquotedSQLiteSchema(schemaName),
+			columnName,
+		).Any()
+	}
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
 		`SELECT 1 FROM information_schema.columns WHERE column_name = $1 AND table_name = $2 AND table_schema = $3`,
 		columnName,
@@ -52,7 +85,24 @@ func PredicateConstraintExists(ctx context.Context, c *db.Connection, tx *sql.Tx
 }
 
 // PredicateConstraintExistsInSchema returns if a constraint exists on a table in a specific schema on the given connection.
+//
+// For the sqlite dialect, which does not catalog constraints, it returns if the table definition has a named constraint.
 func PredicateConstraintExistsInSchema(ctx context.Context, c *db.Connection, tx *sql.Tx, schemaName, tableName, constraintName string) (bool, error) {
+	if c.Config.DialectOrDefault().Is(db.DialectMySQL) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM information_schema.table_constraints WHERE constraint_name = ? AND table_name = ? AND table_schema = `+mysqlSchemaExpression,
+			constraintName,
+			tableName,
+			unquotedMySQLSchema(schemaName),
+		).Any()
+	}
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			fmt.Sprintf(`SELECT 1 FROM %s.sqlite_master WHERE type = 'table' AND name = ?1 AND instr(lower(sql), lower('constraint ' || ?2 || ' ')) > 0`, sqliteSchema(schemaName)),
+			tableName,
+			constraintName,
+		).Any()
+	}
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
 		`SELECT 1 FROM information_schema.constraint_column_usage WHERE constraint_name = $1 AND table_name = $2 AND table_schema = $3`,
 		constraintName,
@@ -68,18 +118,52 @@ func PredicateIndexExists(ctx context.Context, c *db.Connection, tx *sql.Tx, tab
 
 // PredicateIndexExistsInSchema returns if a index exists on a table in a specific schema on the given connection.
 func PredicateIndexExistsInSchema(ctx context.Context, c *db.Connection, tx *sql.Tx, schemaName, tableName, indexName string) (bool, error) {
+	if c.Config.DialectOrDefault().Is(db.DialectMySQL) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM information_schema.statistics WHERE lower(index_name) = ? AND lower(table_name) = ? AND table_schema = `+mysqlSchemaExpression,
+			strings.ToLower(indexName), strings.ToLower(tableName), unquotedMySQLSchema(schemaName)).Any()
+	}
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			fmt.Sprintf(`SELECT 1 FROM %s.sqlite_master WHERE type = 'index' AND lower(name) = ?1 AND lower(tbl_name) = ?2`, sqliteSchema(schemaName)),
+			strings.ToLower(indexName), strings.ToLower(tableName)).Any()
+	}
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
 		`SELECT 1 FROM pg_catalog.pg_indexes where indexname = $1 and tablename = $2 AND schemaname = $3`,
 		strings.ToLower(indexName), strings.ToLower(tableName), strings.ToLower(schemaName)).Any()
 }
 
 // PredicateRoleExists returns if a role exists or not.
+//
+// For the mysql dialect, where roles are stored as users, it returns if a user or role exists.
+// It returns an `ErrUnsupportedDialect` for the sqlite dialect, which does not have roles.
 func PredicateRoleExists(ctx context.Context, c *db.Connection, tx *sql.Tx, roleName string) (bool, error) {
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return false, ex.New(db.ErrUnsupportedDialect, ex.OptMessage("roles"))
+	}
+	if c.Config.DialectOrDefault().Is(db.DialectMySQL) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(`SELECT 1 FROM mysql.user WHERE user = ?`, roleName).Any()
+	}
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(`SELECT 1 FROM pg_catalog.pg_roles WHERE rolname ilike $1`, roleName).Any()
 }
 
 // PredicateSchemaExists returns if a schema exists or not.
+//
+// For the sqlite dialect it returns if a database with the schema name is attached,
+// and for the mysql dialect if a database with the schema name exists.
 func PredicateSchemaExists(ctx context.Context, c *db.Connection, tx *sql.Tx, schemaName string) (bool, error) {
+	if c.Config.DialectOrDefault().Is(db.DialectMySQL) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM information_schema.schemata WHERE schema_name = ?`,
+			schemaName,
+		).Any()
+	}
+	if c.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+			`SELECT 1 FROM pragma_database_list WHERE name = ?1`,
+			schemaName,
+		).Any()
+	}
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
 		`SELECT 1 FROM information_schema.schemata WHERE schema_name = $1`,
 		schemaName,
@@ -102,6 +186,31 @@ func PredicateNone(ctx context.Context, c *db.Connection, tx *sql.Tx, selectStat
 	return c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(selectStatement, params...).None()
 }
 
+// sqliteSchema returns the quoted sqlite database name for a schema, where
+// the default (postgres) schema is the `main` database.
+func sqliteSchema(schemaName string) string {
+	return db.DialectSQLite.QuoteIdentifier(unquotedSQLiteSchema(schemaName))
+}
+
+func unquotedSQLiteSchema(schemaName string) string {
+	if schemaName == "" || schemaName == db.DefaultSchema {
+		return "main"
+	}
+	return schemaName
+}
+
+// mysqlSchemaExpression is the mysql expression for a schema argument, where an empty schema is the connection database.
+const mysqlSchemaExpression = `COALESCE(NULLIF(?, ''), DATABASE())`
+
+// unquotedMySQLSchema returns the mysql database name for a schema, where the default (postgres) schema
+// is empty, i.e. the connection database.
+func unquotedMySQLSchema(schemaName string) string {
+	if schemaName == db.DefaultSchema {
+		return ""
+	}
+	return schemaName
+}
+
 // Not inverts the output of a predicate.
 func Not(proceed bool, err error) (bool, error) {
 	return !proceed, err
@@ -0,0 +1,134 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
+	_ "github.com/zpkg/blend-go-sdk/db/sqlite"
+	"github.com/zpkg/blend-go-sdk/logger"
+)
+
+func openSQLiteConnection(its *assert.Assertions) *db.Connection {
+	conn, err := db.Open(db.New(db.OptSQLite(filepath.Join(its.T.TempDir(), "migration.db"))))
+	its.Nil(err)
+	return conn
+}
+
+func Test_Predicates_sqlite(t *testing.T) {
+	its := assert.New(t)
+	conn := openSQLiteConnection(its)
+	defer func() { its.Nil(conn.Close()) }()
+	ctx := context.Background()
+
+	its.Nil(db.IgnoreExecResult(conn.Exec(`CREATE TABLE widgets (
+		id INTEGER PRIMARY KEY
+		, name TEXT NOT NULL
+		, CONSTRAINT uk_widgets_name UNIQUE (name)
+	)`)))
+	its.Nil(db.IgnoreExecResult(conn.Exec(`CREATE INDEX idx_widgets_name ON widgets (name)`)))
+
+	exists, err := PredicateTableExists(ctx, conn, nil, "widgets")
+	its.Nil(err)
+	its.True(exists)
+	exists, err = PredicateTableExists(ctx, conn, nil, "not_widgets")
+	its.Nil(err)
+	its.False(exists)
+
+	exists, err = PredicateColumnExists(ctx, conn, nil, "widgets", "name")
+	its.Nil(err)
+	its.True(exists)
+	exists, err = PredicateColumnExists(ctx, conn, nil, "widgets", "color")
+	its.Nil(err)
+	its.False(exists)
+
+	exists, err = PredicateConstraintExists(ctx, conn, nil, "widgets", "uk_widgets_name")
+	its.Nil(err)
+	its.True(exists)
+	exists, err = PredicateConstraintExists(ctx, conn, nil, "widgets", "uk_widgets")
+	its.Nil(err)
+	its.False(exists)
+
+	exists, err = PredicateIndexExists(ctx, conn, nil, "widgets", "IDX_WIDGETS_NAME")
+	its.Nil(err)
+	its.True(exists)
+
+	exists, err = PredicateSchemaExists(ctx, conn, nil, "main")
+	its.Nil(err)
+	its.True(exists)
+	exists, err = PredicateSchemaExists(ctx, conn, nil, "not_main")
+	its.Nil(err)
+	its.False(exists)
+
+	_, err = PredicateRoleExists(ctx, conn, nil, "postgres")
+	its.True(db.IsUnsupportedDialect(err))
+}
+
+func TestSuite_Up_sqlite(t *testing.T) {
+	its := assert.New(t)
+	conn := openSQLiteConnection(its)
+	defer func() { its.Nil(conn.Close()) }()
+	ctx := context.Background()
+
+	migrations := []*Migration{
+		NewMigration(1, "create_widgets",
+			Statements("CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT NOT NULL)"),
+			OptMigrationDown(Statements("DROP TABLE widgets")),
+		),
+		NewMigration(2, "add_widget_color",
+			NewStep(ColumnNotExists("widgets", "color"), Statements("ALTER TABLE widgets ADD COLUMN color TEXT")),
+		),
+		NewMigration(3, "index_widget_names",
+			NewStep(IndexNotExists("widgets", "idx_widgets_name"), Statements("CREATE INDEX idx_widgets_name ON widgets (name)")),
+			OptMigrationDown(Statements("DROP INDEX idx_widgets_name")),
+		),
+	}
+
+	plan := NewPlan()
+	s := New(OptLog(logger.None()), OptMigrations(migrations...))
+	its.Nil(s.Up(WithPlan(ctx, plan), conn))
+	its.Equal(6, plan.WouldApply)
+	exists, err := PredicateTableExists(ctx, conn, nil, DefaultHistoryTable)
+	its.Nil(err)
+	its.False(exists)
+
+	s = New(OptLog(logger.None()), OptMigrations(migrations...))
+	its.Nil(s.Up(ctx, conn))
+	its.Equal(5, s.Applied)
+
+	exists, err = PredicateColumnExists(ctx, conn, nil, "widgets", "color")
+	its.Nil(err)
+	its.True(exists)
+
+	s = New(OptLog(logger.None()), OptMigrations(migrations...))
+	its.Nil(s.Up(ctx, conn))
+	its.Zero(s.Applied)
+
+	s = New(OptLog(logger.None()), OptMigrations(migrations...))
+	its.True(IsIrreversible(s.RollbackTo(ctx, conn, 0)))
+	its.Nil(s.Rollback(ctx, conn, 1))
+	its.Equal(1, s.RolledBack)
+
+	exists, err = PredicateIndexExists(ctx, conn, nil, "widgets", "idx_widgets_name")
+	its.Nil(err)
+	its.False(exists)
+
+	status, err := s.Status(ctx, conn)
+	its.Nil(err)
+	its.True(status[1].Applied)
+	its.False(status[2].Applied)
+
+	buffer := new(bytes.Buffer)
+	its.Nil(plan.WriteSQL(buffer))
+	its.Contains(buffer.String(), "CREATE TABLE widgets")
+}
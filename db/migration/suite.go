@@ -33,14 +33,21 @@ func NewWithActions(actions ...Action) *Suite {
 }
 
 // Suite is a migration suite.
+//
+// Groups are run on every `Apply`; versioned migrations are run once each by `Up`
+// and `UpTo`, and recorded in the history table.
 type Suite struct {
-	Log    logger.Log
-	Groups []*Group
+	Log          logger.Log
+	Groups       []*Group
+	Migrations   []*Migration
+	HistoryTable string
 
 	Applied int
 	Skipped int
 	Failed  int
 	Total   int
+	// RolledBack is the number of steps rolled back; these are also counted as applied.
+	RolledBack int
 }
 
 // Apply applies the suite.
@@ -67,6 +74,14 @@ func (s *Suite) Applyf(ctx context.Context, format string, args ...interface{})
 	s.Write(ctx, StatApplied, fmt.Sprintf(format, args...))
 }
 
+// RolledBackf writes a rolled back step message.
+func (s *Suite) RolledBackf(ctx context.Context, format string, args ...interface{}) {
+	s.RolledBack++
+	s.Applied++
+	s.Total++
+	s.Write(ctx, StatRolledBack, fmt.Sprintf(format, args...))
+}
+
 // Skipf skips a given step.
 func (s *Suite) Skipf(ctx context.Context, format string, args ...interface{}) {
 	s.Skipped++
@@ -89,7 +104,12 @@ func (s *Suite) Error(ctx context.Context, err error) error {
 	return err
 }
 
+// Write writes a step result; if the context has a plan, the step is also added to the plan.
 func (s *Suite) Write(ctx context.Context, result, body string) {
+	if plan := GetContextPlan(ctx); plan != nil {
+		result = planResult(result)
+		plan.addStep(result, body, GetContextLabels(ctx))
+	}
 	logger.MaybeTriggerContext(ctx, s.Log, NewEvent(result, body, GetContextLabels(ctx)...))
 }
 
@@ -0,0 +1,279 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// MigrationStatus is the status of a versioned migration.
+type MigrationStatus struct {
+	Version    int64
+	Name       string
+	Checksum   string
+	Applied    bool
+	AppliedUTC time.Time
+	// Modified is true if the migration has been edited since it was applied.
+	Modified bool
+	// Missing is true if the migration is recorded as applied but is not in the suite.
+	Missing bool
+}
+
+// HistoryTableOrDefault returns the history table name or a default.
+func (s *Suite) HistoryTableOrDefault() string {
+	if s.HistoryTable != "" {
+		return s.HistoryTable
+	}
+	return DefaultHistoryTable
+}
+
+// Status returns the status of each versioned migration by version, including
+// applied migrations that are no longer in the suite.
+func (s *Suite) Status(ctx context.Context, c *db.Connection) ([]MigrationStatus, error) {
+	migrations, err := s.sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	history, err := loadHistory(ctx, c, s.HistoryTableOrDefault())
+	if err != nil {
+		return nil, err
+	}
+
+	var output []MigrationStatus
+	for _, m := range migrations {
+		status := MigrationStatus{
+			Version:  m.Version,
+			Name:     m.Name,
+			Checksum: m.Checksum,
+		}
+		if entry, ok := history[m.Version]; ok {
+			status.Applied = true
+			status.AppliedUTC = entry.AppliedUTC
+			status.Modified = isModified(m, entry)
+			delete(history, m.Version)
+		}
+		output = append(output, status)
+	}
+	for _, entry := range history {
+		output = append(output, MigrationStatus{
+			Version:    entry.Version,
+			Name:       entry.Name,
+			Checksum:   entry.Checksum,
+			Applied:    true,
+			AppliedUTC: entry.AppliedUTC,
+			Missing:    true,
+		})
+	}
+	sort.Slice(output, func(i, j int) bool { return output[i].Version < output[j].Version })
+	return output, nil
+}
+
+// Up applies all pending versioned migrations in version order.
+func (s *Suite) Up(ctx context.Context, c *db.Connection) error {
+	return s.UpTo(ctx, c, math.MaxInt64)
+}
+
+// UpTo applies pending versioned migrations in version order up to and including a given version.
+//
+// It returns an `ErrMigrationModified` without applying anything if an applied migration has been edited.
+// Each migration is applied in its own transaction, along with its history entry, unless it skips transactions.
+func (s *Suite) UpTo(ctx context.Context, c *db.Connection, version int64) (err error) {
+	defer s.WriteStats(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+
+	var migrations []*Migration
+	if migrations, err = s.sortedMigrations(); err != nil {
+		return
+	}
+	var history map[int64]HistoryEntry
+	if history, err = loadHistory(ctx, c, s.HistoryTableOrDefault()); err != nil {
+		return
+	}
+	for _, m := range migrations {
+		if entry, ok := history[m.Version]; ok && isModified(m, entry) {
+			err = s.Error(WithLabel(ctx, m.Label()), ex.New(ErrMigrationModified, ex.OptMessagef("migration: %s", m.Label())))
+			return
+		}
+	}
+
+	suiteCtx := WithSuite(ctx, s)
+	for _, m := range migrations {
+		if m.Version > version {
+			break
+		}
+		if _, ok := history[m.Version]; ok {
+			s.Skipf(ctx, m.Label())
+			continue
+		}
+		if err = s.upGroup(m).Action(suiteCtx, c); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// upGroup returns a group that applies a migration and records it in the history table,
+// guarded on the migration not having been applied concurrently.
+//
+// When planning, the guard always passes as the history table may not exist yet.
+func (s *Suite) upGroup(m *Migration) *Group {
+	tableName := s.HistoryTableOrDefault()
+	guard := Guard(m.Label(), func(ctx context.Context, c *db.Connection, tx *sql.Tx) (bool, error) {
+		if GetContextPlan(ctx) != nil {
+			return true, nil
+		}
+		return PredicateVersionNotApplied(ctx, c, tx, tableName, m.Version)
+	})
+	body := ActionFunc(func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		started := time.Now()
+		if err := m.Up.Action(ctx, c, tx); err != nil {
+			return err
+		}
+		return recordApplied(ctx, c, tx, tableName, m, time.Since(started))
+	})
+	group := NewGroupWithAction(guard, body)
+	group.SkipTransaction = m.SkipTransaction
+	return group
+}
+
+// Rollback rolls back the last n applied versioned migrations in reverse version order.
+func (s *Suite) Rollback(ctx context.Context, c *db.Connection, n int) error {
+	return s.rollback(ctx, c, func(applied []int64) []int64 {
+		if n < 0 {
+			n = 0
+		}
+		if n < len(applied) {
+			return applied[:n]
+		}
+		return applied
+	})
+}
+
+// RollbackTo rolls back the applied versioned migrations after a given version in reverse version order,
+// leaving that version applied.
+func (s *Suite) RollbackTo(ctx context.Context, c *db.Connection, version int64) error {
+	return s.rollback(ctx, c, func(applied []int64) []int64 {
+		for index, appliedVersion := range applied {
+			if appliedVersion <= version {
+				return applied[:index]
+			}
+		}
+		return applied
+	})
+}
+
+// rollback rolls back the applied versions selected from those in the history table, newest first.
+//
+// It returns an error without rolling back anything if a selected migration is not in the suite,
+// has been modified since it was applied, or cannot be rolled back.
+// Each migration is rolled back in its own transaction, along with the removal of its history entry,
+// unless it skips transactions.
+func (s *Suite) rollback(ctx context.Context, c *db.Connection, selectVersions func([]int64) []int64) (err error) {
+	defer s.WriteStats(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+
+	var migrations []*Migration
+	if migrations, err = s.sortedMigrations(); err != nil {
+		return
+	}
+	var history map[int64]HistoryEntry
+	if history, err = loadHistory(ctx, c, s.HistoryTableOrDefault()); err != nil {
+		return
+	}
+	applied := make([]int64, 0, len(history))
+	for version := range history {
+		applied = append(applied, version)
+	}
+	sort.Slice(applied, func(i, j int) bool { return applied[i] > applied[j] })
+
+	byVersion := make(map[int64]*Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	targets := selectVersions(applied)
+	for _, version := range targets {
+		m, ok := byVersion[version]
+		if !ok {
+			entry := history[version]
+			err = s.Error(WithLabel(ctx, fmt.Sprintf("%04d_%s", entry.Version, entry.Name)), ex.New(ErrIrreversible, ex.OptMessagef("migration is not in the suite: %04d_%s", entry.Version, entry.Name)))
+			return
+		}
+		if isModified(m, history[version]) {
+			err = s.Error(WithLabel(ctx, m.Label()), ex.New(ErrMigrationModified, ex.OptMessagef("migration: %s", m.Label())))
+			return
+		}
+		if m.DownAction() == nil {
+			err = s.Error(WithLabel(ctx, m.Label()), ex.New(ErrIrreversible, ex.OptMessagef("migration: %s", m.Label())))
+			return
+		}
+	}
+
+	rollbackCtx := withRollback(WithSuite(ctx, s))
+	for _, version := range targets {
+		if err = s.downGroup(byVersion[version]).Action(rollbackCtx, c); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// downGroup returns a group that rolls back a migration and removes it from the history table,
+// guarded on the migration not having been rolled back concurrently.
+//
+// When planning, the guard always passes.
+func (s *Suite) downGroup(m *Migration) *Group {
+	tableName := s.HistoryTableOrDefault()
+	guard := Guard(m.Label(), func(ctx context.Context, c *db.Connection, tx *sql.Tx) (bool, error) {
+		if GetContextPlan(ctx) != nil {
+			return true, nil
+		}
+		return PredicateVersionApplied(ctx, c, tx, tableName, m.Version)
+	})
+	down := m.DownAction()
+	body := ActionFunc(func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		if err := down.Action(ctx, c, tx); err != nil {
+			return err
+		}
+		return removeApplied(ctx, c, tx, tableName, m)
+	})
+	group := NewGroupWithAction(guard, body)
+	group.SkipTransaction = m.SkipTransaction
+	return group
+}
+
+// sortedMigrations returns the migrations of the suite sorted by version.
+func (s *Suite) sortedMigrations() ([]*Migration, error) {
+	if err := validateMigrations(s.Migrations); err != nil {
+		return nil, err
+	}
+	migrations := make([]*Migration, len(s.Migrations))
+	copy(migrations, s.Migrations)
+	sortMigrations(migrations)
+	return migrations, nil
+}
+
+// isModified returns if a migration differs from its history entry.
+func isModified(m *Migration, entry HistoryEntry) bool {
+	return m.Checksum != "" && m.Checksum != entry.Checksum
+}
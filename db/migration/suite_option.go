@@ -32,3 +32,17 @@ func OptLog(log logger.Log) SuiteOption {
 		s.Log = log
 	}
 }
+
+// OptMigrations adds versioned migrations to the Suite. They are additive.
+func OptMigrations(migrations ...*Migration) SuiteOption {
+	return func(s *Suite) {
+		s.Migrations = append(s.Migrations, migrations...)
+	}
+}
+
+// OptHistoryTable sets the name of the table that records applied versioned migrations.
+func OptHistoryTable(tableName string) SuiteOption {
+	return func(s *Suite) {
+		s.HistoryTable = tableName
+	}
+}
@@ -117,3 +117,150 @@ func createTestMigrations(testSchemaName string) []*Group {
 		),
 	}
 }
+
+func createTestVersionedMigrations(testSchemaName string) []*Migration {
+	return []*Migration{
+		NewMigration(2, "create_widgets", Statements(fmt.Sprintf("CREATE TABLE %s.widgets (id int not null primary key, name text not null)", testSchemaName)), OptMigrationChecksum("create_widgets_v1")),
+		NewMigration(1, "create_schema", Statements(fmt.Sprintf("CREATE SCHEMA %s", testSchemaName))),
+		NewMigration(3, "seed_widgets", Statements(fmt.Sprintf("INSERT INTO %s.widgets (id, name) VALUES (1, 'foo')", testSchemaName))),
+	}
+}
+
+func TestSuite_Up(t *testing.T) {
+	its := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	historyTable := testSchemaName + "_history"
+	defer func() {
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s;", historyTable))))
+	}()
+
+	s := New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(createTestVersionedMigrations(testSchemaName)...))
+	its.Nil(s.UpTo(context.Background(), defaultDB(), 2))
+	ap, sk, fl, _ := s.Results()
+	its.Equal(2, ap)
+	its.Equal(0, sk)
+	its.Equal(0, fl)
+
+	status, err := s.Status(context.Background(), defaultDB())
+	its.Nil(err)
+	its.Len(status, 3)
+	its.True(status[0].Applied)
+	its.True(status[1].Applied)
+	its.False(status[2].Applied)
+
+	s = New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(createTestVersionedMigrations(testSchemaName)...))
+	its.Nil(s.Up(context.Background(), defaultDB()))
+	ap, sk, _, _ = s.Results()
+	its.Equal(1, ap)
+	its.Equal(2, sk)
+
+	var name string
+	_, err = defaultDB().Query(fmt.Sprintf("SELECT name FROM %s.widgets WHERE id = 1", testSchemaName)).Scan(&name)
+	its.Nil(err)
+	its.Equal("foo", name)
+}
+
+func TestSuite_Up_modified(t *testing.T) {
+	its := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	historyTable := testSchemaName + "_history"
+	defer func() {
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s;", historyTable))))
+	}()
+
+	migrations := createTestVersionedMigrations(testSchemaName)
+	s := New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(migrations...))
+	its.Nil(s.Up(context.Background(), defaultDB()))
+
+	migrations[0].Checksum = "create_widgets_v2"
+	migrations = append(migrations, NewMigration(4, "seed_more_widgets", Statements(fmt.Sprintf("INSERT INTO %s.widgets (id, name) VALUES (2, 'bar')", testSchemaName))))
+	s = New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(migrations...))
+	err := s.Up(context.Background(), defaultDB())
+	its.True(IsMigrationModified(err))
+
+	status, err := s.Status(context.Background(), defaultDB())
+	its.Nil(err)
+	its.True(status[1].Modified)
+	its.False(status[3].Applied)
+
+	s = New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(migrations[1:]...))
+	status, err = s.Status(context.Background(), defaultDB())
+	its.Nil(err)
+	its.Len(status, 4)
+	its.True(status[1].Missing)
+}
+
+func TestSuite_Rollback(t *testing.T) {
+	its := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	historyTable := testSchemaName + "_history"
+	defer func() {
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s;", historyTable))))
+	}()
+
+	migrations := []*Migration{
+		NewMigration(1, "create_schema", Statements(fmt.Sprintf("CREATE SCHEMA %s", testSchemaName))),
+		NewMigration(2, "create_widgets",
+			Statements(fmt.Sprintf("CREATE TABLE %s.widgets (id int not null primary key, name text not null)", testSchemaName)),
+			OptMigrationDown(Statements(fmt.Sprintf("DROP TABLE %s.widgets", testSchemaName))),
+		),
+		NewMigration(3, "seed_widgets",
+			Statements(fmt.Sprintf("INSERT INTO %s.widgets (id, name) VALUES (1, 'foo')", testSchemaName)),
+			OptMigrationDown(Statements(fmt.Sprintf("DELETE FROM %s.widgets WHERE id = 1", testSchemaName))),
+		),
+	}
+	s := New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(migrations...))
+	its.Nil(s.Up(context.Background(), defaultDB()))
+
+	s = New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(migrations...))
+	its.Nil(s.Rollback(context.Background(), defaultDB(), 1))
+	its.Equal(1, s.RolledBack)
+
+	status, err := s.Status(context.Background(), defaultDB())
+	its.Nil(err)
+	its.True(status[1].Applied)
+	its.False(status[2].Applied)
+
+	s = New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(migrations...))
+	its.True(IsIrreversible(s.RollbackTo(context.Background(), defaultDB(), 0)))
+	its.Zero(s.RolledBack)
+
+	its.Nil(s.RollbackTo(context.Background(), defaultDB(), 1))
+	its.Equal(1, s.RolledBack)
+
+	status, err = s.Status(context.Background(), defaultDB())
+	its.Nil(err)
+	its.True(status[0].Applied)
+	its.False(status[1].Applied)
+
+	exists, err := PredicateTableExistsInSchema(context.Background(), defaultDB(), nil, testSchemaName, "widgets")
+	its.Nil(err)
+	its.False(exists)
+}
+
+func TestSuite_Up_plan(t *testing.T) {
+	its := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	historyTable := testSchemaName + "_history"
+	defer func() {
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+		its.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s;", historyTable))))
+	}()
+
+	plan := NewPlan()
+	s := New(OptLog(logger.None()), OptHistoryTable(historyTable), OptMigrations(createTestVersionedMigrations(testSchemaName)...))
+	its.Nil(s.Up(WithPlan(context.Background(), plan), defaultDB()))
+	its.Equal(4, plan.WouldApply)
+	its.Len(plan.Steps, 4)
+	its.Len(plan.Steps[1].Statements, 2)
+
+	exists, err := PredicateSchemaExists(context.Background(), defaultDB(), nil, testSchemaName)
+	its.Nil(err)
+	its.False(exists)
+	exists, err = PredicateTableExists(context.Background(), defaultDB(), nil, historyTable)
+	its.Nil(err)
+	its.False(exists)
+}
@@ -0,0 +1,4 @@
+CREATE TABLE widgets (
+	id serial not null primary key
+	, name text not null
+);
@@ -0,0 +1,2 @@
+-- migration:no-transaction
+DROP INDEX CONCURRENTLY idx_widgets_name;
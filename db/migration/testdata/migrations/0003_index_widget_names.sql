@@ -0,0 +1,2 @@
+-- migration:no-transaction
+CREATE INDEX CONCURRENTLY idx_widgets_name ON widgets (name);
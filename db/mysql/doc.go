@@ -0,0 +1,21 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+/*
+Package mysql registers the mysql driver for the `db.DialectMySQL` dialect.
+
+Import it for its side effect, and create connections with `db.OptMySQL`, or with a config
+from a mysql DSN, e.g. from `DATABASE_URL` with `DB_DIALECT=mysql`:
+
+	import _ "github.com/zpkg/blend-go-sdk/db/mysql"
+
+	conn, err := db.Open(db.New(db.OptMySQL(), db.OptHost("localhost"), db.OptDatabase("blend")))
+
+Features specific to postgres, e.g. `COPY`, `LISTEN` / `NOTIFY` and read replicas, are not available; `CopyMany`
+falls back to inserts, and the others return an `ErrUnsupportedDialect`.
+*/
+package mysql // import "github.com/zpkg/blend-go-sdk/db/mysql"
@@ -0,0 +1,13 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package mysql
+
+import (
+	// the mysql driver is registered as `mysql`
+	_ "github.com/go-sql-driver/mysql"
+)
@@ -0,0 +1,65 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
+)
+
+func Test_CreateDSN(t *testing.T) {
+	its := assert.New(t)
+
+	cfg := db.Config{
+		Dialect:          string(db.DialectMySQL),
+		Host:             "bar",
+		Database:         "blend",
+		Username:         "example-string",
+		Password:         "d@g",
+		ConnectTimeout:   5 * time.Second,
+		LockTimeout:      4 * time.Second,
+		StatementTimeout: 5500 * time.Millisecond,
+	}
+	parsed, err := mysql.ParseDSN(cfg.CreateDSN())
+	its.Nil(err)
+	its.Equal("tcp", parsed.Net)
+	its.Equal("bar:3306", parsed.Addr)
+	its.Equal("blend", parsed.DBName)
+	its.Equal("example-string", parsed.User)
+	its.Equal("d@g", parsed.Passwd)
+	its.Equal(5*time.Second, parsed.Timeout)
+	its.True(parsed.ParseTime)
+	its.Equal("4", parsed.Params["innodb_lock_wait_timeout"])
+	its.Equal("5500", parsed.Params["max_execution_time"])
+}
+
+func Test_Open(t *testing.T) {
+	its := assert.New(t)
+
+	conn, err := db.Open(db.New(db.OptMySQL(), db.OptHost("localhost"), db.OptDatabase("blend"), db.OptUsername("root")))
+	its.Nil(err)
+	defer func() { its.Nil(conn.Close()) }()
+	its.Equal(db.EngineMySQL, conn.Config.EngineOrDefault())
+	its.True(db.IsUnsupportedDialect(conn.Notify(context.Background(), "widgets", "foo")))
+}
+
+func Test_Open_replicas(t *testing.T) {
+	its := assert.New(t)
+
+	conn, err := db.New(db.OptMySQL(), db.OptHost("localhost"), db.OptDatabase("blend"), db.OptUsername("root"))
+	its.Nil(err)
+	conn.Config.ReplicaDSNs = []string{"root@tcp(replica:3306)/blend"}
+	its.True(db.IsUnsupportedDialect(conn.Open()))
+	its.Nil(conn.Connection)
+}
@@ -0,0 +1,152 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/ansi"
+	"github.com/zpkg/blend-go-sdk/logger"
+	"github.com/zpkg/blend-go-sdk/timeutil"
+)
+
+// Logger flags
+const (
+	NotificationFlag = "db.notification"
+)
+
+// these are compile time assertions
+var (
+	_ logger.Event        = (*NotificationEvent)(nil)
+	_ logger.TextWritable = (*NotificationEvent)(nil)
+	_ logger.JSONWritable = (*NotificationEvent)(nil)
+)
+
+// NewNotificationEvent creates a new notification event.
+func NewNotificationEvent(channel, payload string, elapsed time.Duration, options ...NotificationEventOption) NotificationEvent {
+	ne := NotificationEvent{
+		Channel: channel,
+		Payload: payload,
+		Elapsed: elapsed,
+	}
+	for _, opt := range options {
+		opt(&ne)
+	}
+	return ne
+}
+
+// NewNotificationEventListener returns a new listener for notification events.
+func NewNotificationEventListener(listener func(context.Context, NotificationEvent)) logger.Listener {
+	return func(ctx context.Context, e logger.Event) {
+		if typed, isTyped := e.(NotificationEvent); isTyped {
+			listener(ctx, typed)
+		}
+	}
+}
+
+// NewNotificationEventFilter returns a new notification event filter.
+func NewNotificationEventFilter(filter func(context.Context, NotificationEvent) (NotificationEvent, bool)) logger.Filter {
+	return func(ctx context.Context, e logger.Event) (logger.Event, bool) {
+		if typed, isTyped := e.(NotificationEvent); isTyped {
+			return filter(ctx, typed)
+		}
+		return e, false
+	}
+}
+
+// NotificationEventOption mutates a notification event.
+type NotificationEventOption func(*NotificationEvent)
+
+// OptNotificationEventDatabase sets a field on the notification event.
+func OptNotificationEventDatabase(value string) NotificationEventOption {
+	return func(e *NotificationEvent) { e.Database = value }
+}
+
+// OptNotificationEventEngine sets a field on the notification event.
+func OptNotificationEventEngine(value string) NotificationEventOption {
+	return func(e *NotificationEvent) { e.Engine = value }
+}
+
+// OptNotificationEventUsername sets a field on the notification event.
+func OptNotificationEventUsername(value string) NotificationEventOption {
+	return func(e *NotificationEvent) { e.Username = value }
+}
+
+// OptNotificationEventPID sets a field on the notification event.
+func OptNotificationEventPID(value uint32) NotificationEventOption {
+	return func(e *NotificationEvent) { e.PID = value }
+}
+
+// OptNotificationEventErr sets a field on the notification event.
+func OptNotificationEventErr(value error) NotificationEventOption {
+	return func(e *NotificationEvent) { e.Err = value }
+}
+
+// NotificationEvent represents the delivery of a notification to the handlers of a channel.
+type NotificationEvent struct {
+	Database string
+	Engine   string
+	Username string
+	Channel  string
+	Payload  string
+	// PID is the process id of the server backend that sent the notification.
+	PID     uint32
+	Elapsed time.Duration
+	Err     error
+}
+
+// GetFlag implements Event.
+func (e NotificationEvent) GetFlag() string { return NotificationFlag }
+
+// WriteText writes the event text to the output.
+func (e NotificationEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	fmt.Fprint(wr, "[")
+	if len(e.Engine) > 0 {
+		fmt.Fprint(wr, tf.Colorize(e.Engine, ansi.ColorLightWhite))
+		fmt.Fprint(wr, logger.Space)
+	}
+	if len(e.Username) > 0 {
+		fmt.Fprint(wr, tf.Colorize(e.Username, ansi.ColorLightWhite))
+		fmt.Fprint(wr, "@")
+	}
+	fmt.Fprint(wr, tf.Colorize(e.Database, ansi.ColorLightWhite))
+	fmt.Fprint(wr, "]")
+
+	fmt.Fprint(wr, logger.Space)
+	fmt.Fprintf(wr, "[%s]", tf.Colorize(e.Channel, ansi.ColorLightWhite))
+
+	if len(e.Payload) > 0 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprint(wr, e.Payload)
+	}
+
+	fmt.Fprint(wr, logger.Space)
+	fmt.Fprint(wr, e.Elapsed.String())
+
+	if e.Err != nil {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprint(wr, tf.Colorize("failed", ansi.ColorRed))
+	}
+}
+
+// Decompose implements JSONWritable.
+func (e NotificationEvent) Decompose() map[string]interface{} {
+	return map[string]interface{}{
+		"engine":   e.Engine,
+		"database": e.Database,
+		"username": e.Username,
+		"channel":  e.Channel,
+		"payload":  e.Payload,
+		"pid":      e.PID,
+		"err":      e.Err,
+		"elapsed":  timeutil.Milliseconds(e.Elapsed),
+	}
+}
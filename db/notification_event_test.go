@@ -0,0 +1,83 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/logger"
+)
+
+func Test_NewNotificationEvent(t *testing.T) {
+	t.Parallel()
+	its := assert.New(t)
+
+	ne := NewNotificationEvent("event-channel", "event-payload", time.Millisecond,
+		OptNotificationEventDatabase("event-database"),
+		OptNotificationEventEngine("event-engine"),
+		OptNotificationEventUsername("event-username"),
+		OptNotificationEventPID(1234),
+	)
+
+	its.Equal("event-channel", ne.Channel)
+	its.Equal("event-payload", ne.Payload)
+	its.Equal("event-database", ne.Database)
+	its.Equal("event-engine", ne.Engine)
+	its.Equal("event-username", ne.Username)
+	its.Equal(1234, ne.PID)
+
+	buf := new(bytes.Buffer)
+	noColor := logger.TextOutputFormatter{
+		NoColor: true,
+	}
+
+	ne.WriteText(noColor, buf)
+	its.Equal("[event-engine event-username@event-database] [event-channel] event-payload 1ms", buf.String())
+
+	buf.Reset()
+	OptNotificationEventErr(fmt.Errorf("this is only a test"))(&ne)
+	ne.WriteText(noColor, buf)
+	its.Equal("[event-engine event-username@event-database] [event-channel] event-payload 1ms failed", buf.String())
+
+	contents, err := json.Marshal(ne.Decompose())
+	its.Nil(err)
+	its.Contains(string(contents), "event-channel")
+}
+
+func Test_NotificationEventListener(t *testing.T) {
+	its := assert.New(t)
+
+	ne := NewNotificationEvent("channel", "payload", time.Millisecond)
+
+	var didCall bool
+	ml := NewNotificationEventListener(func(ctx context.Context, ae NotificationEvent) {
+		didCall = true
+	})
+	ml(context.Background(), ne)
+	its.True(didCall)
+}
+
+func Test_NotificationEventFilter(t *testing.T) {
+	its := assert.New(t)
+
+	ne := NewNotificationEvent("channel", "payload", time.Millisecond)
+
+	filter := NewNotificationEventFilter(func(ctx context.Context, e NotificationEvent) (NotificationEvent, bool) {
+		e.Payload = "filtered"
+		return e, false
+	})
+	filtered, filterOut := filter(context.Background(), ne)
+	its.False(filterOut)
+	its.Equal("filtered", filtered.(NotificationEvent).Payload)
+}
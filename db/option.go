@@ -49,6 +49,15 @@ func OptStatementInterceptor(interceptor StatementInterceptor) Option {
 	}
 }
 
+// OptStatementCache enables a cache of prepared statements for labeled statements on the connection.
+func OptStatementCache(options ...StatementCacheOption) Option {
+	return func(c *Connection) error {
+		c.StatementCache = NewStatementCache(options...)
+		c.StatementCache.conn = c
+		return nil
+	}
+}
+
 // OptConfig sets the config on a connection.
 func OptConfig(cfg Config) Option {
 	return func(c *Connection) error {
@@ -129,6 +138,31 @@ func OptSSLMode(mode string) Option {
 	}
 }
 
+// OptSQLite sets the connection to use the sqlite engine and dialect, with a given database file path.
+//
+// The sqlite driver must be registered by importing `github.com/zpkg/blend-go-sdk/db/sqlite`.
+// Note that each driver connection to `:memory:` is a separate database.
+func OptSQLite(path string) Option {
+	return func(c *Connection) error {
+		c.Config.Engine = EngineSQLite
+		c.Config.Dialect = string(DialectSQLite)
+		c.Config.Database = path
+		c.Config.DSN = ""
+		return nil
+	}
+}
+
+// OptMySQL sets the connection to use the mysql engine and dialect.
+//
+// The mysql driver must be registered by importing `github.com/zpkg/blend-go-sdk/db/mysql`.
+func OptMySQL() Option {
+	return func(c *Connection) error {
+		c.Config.Engine = EngineMySQL
+		c.Config.Dialect = string(DialectMySQL)
+		return nil
+	}
+}
+
 // OptDialect sets the connection dialect.
 func OptDialect(dialect Dialect) Option {
 	return func(c *Connection) error {
@@ -0,0 +1,232 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// PageOption mutates page options.
+type PageOption func(*PageOptions)
+
+// OptPageSize sets the maximum number of rows in a page.
+func OptPageSize(size int) PageOption {
+	return func(po *PageOptions) {
+		po.Size = size
+	}
+}
+
+// OptPageOrderBy adds sort columns in ascending order.
+func OptPageOrderBy(columns ...string) PageOption {
+	return func(po *PageOptions) {
+		for _, column := range columns {
+			po.OrderBy = append(po.OrderBy, PageOrder{Column: column})
+		}
+	}
+}
+
+// OptPageOrderByDesc adds sort columns in descending order.
+func OptPageOrderByDesc(columns ...string) PageOption {
+	return func(po *PageOptions) {
+		for _, column := range columns {
+			po.OrderBy = append(po.OrderBy, PageOrder{Column: column, Descending: true})
+		}
+	}
+}
+
+// OptPageWhere adds predicates that filter the rows that are paged through.
+func OptPageWhere(predicates ...Predicate) PageOption {
+	return func(po *PageOptions) {
+		po.Where = append(po.Where, predicates...)
+	}
+}
+
+// PageOptions are options for keyset pagination.
+type PageOptions struct {
+	// Size is the maximum number of rows in a page.
+	Size int
+	// OrderBy are the sort columns; the primary key columns are appended
+	// if they are not included so that the order is unique.
+	OrderBy []PageOrder
+	// Where are predicates that filter the rows that are paged through.
+	Where []Predicate
+}
+
+// SizeOrDefault returns the page size or a default.
+func (po PageOptions) SizeOrDefault() int {
+	if po.Size > 0 {
+		return po.Size
+	}
+	return DefaultPageSize
+}
+
+// PageOrder is a sort column for keyset pagination.
+type PageOrder struct {
+	Column     string
+	Descending bool
+}
+
+// pageToken is the decoded form of an opaque page token.
+type pageToken struct {
+	Columns []string          `json:"c"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// Page reads a page of rows of a database mapped type into a given collection using keyset pagination,
+// and returns the token for the next page, which is empty if there are no more rows.
+//
+// Pass an empty token for the first page; the sort columns and predicates must be the same
+// for every page. Rows are read past the last row of the previous page by comparing the
+// sort columns, so sort columns should not be nullable.
+func (i *Invocation) Page(collection interface{}, token string, opts ...PageOption) (nextToken string, err error) {
+	if ReflectType(collection).Kind() != reflect.Slice {
+		err = Error(ErrCollectionNotSlice)
+		return
+	}
+
+	var options PageOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	sliceInnerType := ReflectSliceType(collection)
+	tableName := TableNameByType(sliceInnerType)
+	cols := ColumnsFromType(tableName, sliceInnerType)
+
+	var order []PageOrder
+	var orderColumns []*Column
+	if order, orderColumns, err = pageOrder(cols, options.OrderBy); err != nil {
+		return
+	}
+
+	sb := Select(cols.NotReadOnly().ColumnNames()...).
+		From(tableName).
+		WithLabel(tableName + "_page").
+		Where(options.Where...).
+		Limit(options.SizeOrDefault() + 1)
+	for _, po := range order {
+		if po.Descending {
+			sb.OrderByDesc(po.Column)
+		} else {
+			sb.OrderBy(po.Column)
+		}
+	}
+	if token != "" {
+		var values []interface{}
+		if values, err = decodePageToken(token, orderColumns); err != nil {
+			return
+		}
+		sb.Where(pageAfter(order, values))
+	}
+
+	if err = i.QueryStatement(sb).OutMany(collection); err != nil {
+		return
+	}
+
+	collectionValue := ReflectValue(collection)
+	if collectionValue.Len() <= options.SizeOrDefault() {
+		return
+	}
+	collectionValue.Set(collectionValue.Slice(0, options.SizeOrDefault()))
+	nextToken, err = encodePageToken(orderColumns, collectionValue.Index(collectionValue.Len()-1).Interface())
+	return
+}
+
+// pageOrder returns the sort order with the primary keys appended, and the column for each sort column.
+func pageOrder(cols *ColumnCollection, orderBy []PageOrder) (order []PageOrder, orderColumns []*Column, err error) {
+	lookup := cols.Lookup()
+	seen := map[string]bool{}
+	for _, po := range orderBy {
+		column, ok := lookup[po.Column]
+		if !ok {
+			err = ex.New(ErrInvalidPageOrder, ex.OptMessagef("column: %s", po.Column))
+			return
+		}
+		seen[po.Column] = true
+		order = append(order, po)
+		orderColumns = append(orderColumns, column)
+	}
+	for _, pk := range cols.PrimaryKeys().Columns() {
+		if seen[pk.ColumnName] {
+			continue
+		}
+		order = append(order, PageOrder{Column: pk.ColumnName})
+		orderColumns = append(orderColumns, lookup[pk.ColumnName])
+	}
+	if len(order) == 0 {
+		err = Error(ErrNoPrimaryKey)
+	}
+	return
+}
+
+// pageAfter returns a predicate that a row sorts after a row with the given sort column values,
+// e.g. `(a > $1) OR (a = $1 AND b > $2)`.
+func pageAfter(order []PageOrder, values []interface{}) Predicate {
+	var after []Predicate
+	for index, po := range order {
+		var terms []Predicate
+		for previous := 0; previous < index; previous++ {
+			terms = append(terms, Eq(order[previous].Column, values[previous]))
+		}
+		if po.Descending {
+			terms = append(terms, Lt(po.Column, values[index]))
+		} else {
+			terms = append(terms, Gt(po.Column, values[index]))
+		}
+		after = append(after, And(terms...))
+	}
+	return Or(after...)
+}
+
+func encodePageToken(orderColumns []*Column, last interface{}) (string, error) {
+	var token pageToken
+	for _, column := range orderColumns {
+		value, err := json.Marshal(column.GetValue(last))
+		if err != nil {
+			return "", Error(err)
+		}
+		token.Columns = append(token.Columns, column.ColumnName)
+		token.Values = append(token.Values, value)
+	}
+	contents, err := json.Marshal(token)
+	if err != nil {
+		return "", Error(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(contents), nil
+}
+
+// decodePageToken returns the sort column values of a token, typed as the fields of the mapped type.
+func decodePageToken(encoded string, orderColumns []*Column) ([]interface{}, error) {
+	contents, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ex.New(ErrInvalidPageToken, ex.OptInner(err))
+	}
+	var token pageToken
+	if err = json.Unmarshal(contents, &token); err != nil {
+		return nil, ex.New(ErrInvalidPageToken, ex.OptInner(err))
+	}
+	if len(token.Columns) != len(orderColumns) || len(token.Values) != len(orderColumns) {
+		return nil, ex.New(ErrInvalidPageToken, ex.OptMessage("sort columns do not match"))
+	}
+	values := make([]interface{}, len(orderColumns))
+	for index, column := range orderColumns {
+		if token.Columns[index] != column.ColumnName {
+			return nil, ex.New(ErrInvalidPageToken, ex.OptMessage("sort columns do not match"))
+		}
+		value := reflect.New(column.FieldType)
+		if err = json.Unmarshal(token.Values[index], value.Interface()); err != nil {
+			return nil, ex.New(ErrInvalidPageToken, ex.OptInner(err))
+		}
+		values[index] = value.Elem().Interface()
+	}
+	return values, nil
+}
@@ -0,0 +1,103 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+)
+
+func Test_pageAfter(t *testing.T) {
+	its := assert.New(t)
+
+	order := []PageOrder{{Column: "category", Descending: true}, {Column: "id"}}
+	statement, args := Select().From("bench_object").Where(pageAfter(order, []interface{}{"foo", 3})).Build(DialectPostgres)
+	its.Equal("SELECT * FROM bench_object WHERE (category < $1) OR ((category = $2) AND (id > $3))", statement)
+	its.Equal([]interface{}{"foo", "foo", 3}, args)
+}
+
+func Test_pageOrder(t *testing.T) {
+	its := assert.New(t)
+
+	cols := Columns(benchObj{})
+	order, orderColumns, err := pageOrder(cols, []PageOrder{{Column: "timestamp_utc", Descending: true}})
+	its.Nil(err)
+	its.Equal([]PageOrder{{Column: "timestamp_utc", Descending: true}, {Column: "id"}}, order)
+	its.Len(orderColumns, 2)
+
+	order, _, err = pageOrder(cols, []PageOrder{{Column: "id", Descending: true}})
+	its.Nil(err)
+	its.Equal([]PageOrder{{Column: "id", Descending: true}}, order)
+
+	_, _, err = pageOrder(cols, []PageOrder{{Column: "not_a_column"}})
+	its.True(IsInvalidPageOrder(err))
+}
+
+func Test_pageToken(t *testing.T) {
+	its := assert.New(t)
+
+	cols := Columns(benchObj{})
+	_, orderColumns, err := pageOrder(cols, []PageOrder{{Column: "timestamp_utc"}})
+	its.Nil(err)
+
+	ts := time.Date(2022, 03, 04, 05, 06, 07, 8000, time.UTC)
+	token, err := encodePageToken(orderColumns, &benchObj{ID: 12, Timestamp: ts})
+	its.Nil(err)
+	its.NotEmpty(token)
+
+	values, err := decodePageToken(token, orderColumns)
+	its.Nil(err)
+	its.Len(values, 2)
+	its.True(ts.Equal(values[0].(time.Time)))
+	its.Equal(reflect.Int, reflect.TypeOf(values[1]).Kind())
+	its.Equal(12, values[1])
+
+	_, idColumns, err := pageOrder(cols, nil)
+	its.Nil(err)
+	_, err = decodePageToken(token, idColumns)
+	its.True(IsInvalidPageToken(err))
+
+	_, err = decodePageToken("not a token!", orderColumns)
+	its.True(IsInvalidPageToken(err))
+}
+
+func Test_Invocation_Page(t *testing.T) {
+	its := assert.New(t)
+	tx, err := defaultDB().Begin()
+	its.Nil(err)
+	defer func() { _ = tx.Rollback() }()
+
+	its.Nil(seedObjects(10, tx))
+
+	var names []string
+	var pages int
+	var token string
+	for {
+		var page []benchObj
+		token, err = defaultDB().Invoke(OptTx(tx)).Page(&page, token,
+			OptPageSize(4),
+			OptPageOrderByDesc("amount"),
+			OptPageWhere(NotEq("name", "test_object_0")),
+		)
+		its.Nil(err)
+		pages++
+		for _, obj := range page {
+			names = append(names, obj.Name)
+		}
+		if token == "" {
+			break
+		}
+	}
+	its.Equal(3, pages)
+	its.Len(names, 9)
+	its.Equal("test_object_9", names[0])
+	its.Equal("test_object_1", names[8])
+}
@@ -0,0 +1,189 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import "reflect"
+
+// Predicate is a condition in a where clause, or the condition of a join, of a statement builder.
+type Predicate interface {
+	writePredicate(*statementWriter)
+}
+
+// predicateFunc is a function that implements predicate.
+type predicateFunc func(*statementWriter)
+
+func (pf predicateFunc) writePredicate(sw *statementWriter) { pf(sw) }
+
+// Eq returns a predicate that a column equals a value, or is null if the value is nil.
+func Eq(column string, value interface{}) Predicate {
+	if value == nil {
+		return IsNull(column)
+	}
+	return comparison(column, "=", value)
+}
+
+// NotEq returns a predicate that a column does not equal a value, or is not null if the value is nil.
+func NotEq(column string, value interface{}) Predicate {
+	if value == nil {
+		return IsNotNull(column)
+	}
+	return comparison(column, "<>", value)
+}
+
+// Lt returns a predicate that a column is less than a value.
+func Lt(column string, value interface{}) Predicate {
+	return comparison(column, "<", value)
+}
+
+// Lte returns a predicate that a column is less than or equal to a value.
+func Lte(column string, value interface{}) Predicate {
+	return comparison(column, "<=", value)
+}
+
+// Gt returns a predicate that a column is greater than a value.
+func Gt(column string, value interface{}) Predicate {
+	return comparison(column, ">", value)
+}
+
+// Gte returns a predicate that a column is greater than or equal to a value.
+func Gte(column string, value interface{}) Predicate {
+	return comparison(column, ">=", value)
+}
+
+// Like returns a predicate that a column matches a like pattern.
+func Like(column string, pattern string) Predicate {
+	return comparison(column, "LIKE", pattern)
+}
+
+// IsNull returns a predicate that a column is null.
+func IsNull(column string) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		sw.WriteIdentifier(column)
+		sw.WriteString(" IS NULL")
+	})
+}
+
+// IsNotNull returns a predicate that a column is not null.
+func IsNotNull(column string) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		sw.WriteIdentifier(column)
+		sw.WriteString(" IS NOT NULL")
+	})
+}
+
+// In returns a predicate that a column is one of a slice of values.
+//
+// An empty slice of values is always false.
+func In(column string, values interface{}) Predicate {
+	return membership(column, "IN", "1=0", values)
+}
+
+// NotIn returns a predicate that a column is not one of a slice of values.
+//
+// An empty slice of values is always true.
+func NotIn(column string, values interface{}) Predicate {
+	return membership(column, "NOT IN", "1=1", values)
+}
+
+// And returns a predicate that all of a given set of predicates are true.
+func And(predicates ...Predicate) Predicate {
+	return junction(" AND ", "1=1", predicates)
+}
+
+// Or returns a predicate that any of a given set of predicates are true.
+func Or(predicates ...Predicate) Predicate {
+	return junction(" OR ", "1=0", predicates)
+}
+
+// Not returns a predicate that negates a given predicate.
+func Not(predicate Predicate) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		sw.WriteString("NOT (")
+		predicate.writePredicate(sw)
+		sw.WriteString(")")
+	})
+}
+
+// Raw returns a predicate from a raw sql fragment, e.g. `u.id = o.user_id` or `age > ?`.
+//
+// Each `?` in the fragment is replaced with the dialect placeholder for the
+// corresponding argument; use `??` for a literal `?`.
+func Raw(sql string, args ...interface{}) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		sw.WriteRaw(sql, args)
+	})
+}
+
+func comparison(column, operator string, value interface{}) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		sw.WriteIdentifier(column)
+		sw.WriteString(" " + operator + " ")
+		sw.WriteArg(value)
+	})
+}
+
+func membership(column, operator, empty string, values interface{}) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		items := reflectSliceValues(values)
+		if len(items) == 0 {
+			sw.WriteString(empty)
+			return
+		}
+		sw.WriteIdentifier(column)
+		sw.WriteString(" " + operator + " (")
+		for index, item := range items {
+			if index > 0 {
+				sw.WriteString(",")
+			}
+			sw.WriteArg(item)
+		}
+		sw.WriteString(")")
+	})
+}
+
+func junction(separator, empty string, predicates []Predicate) Predicate {
+	return predicateFunc(func(sw *statementWriter) {
+		if len(predicates) == 0 {
+			sw.WriteString(empty)
+			return
+		}
+		if len(predicates) == 1 {
+			predicates[0].writePredicate(sw)
+			return
+		}
+		for index, predicate := range predicates {
+			if index > 0 {
+				sw.WriteString(separator)
+			}
+			sw.WriteString("(")
+			predicate.writePredicate(sw)
+			sw.WriteString(")")
+		}
+	})
+}
+
+// reflectSliceValues returns the elements of a slice or array, or the value itself if it is not one.
+//
+// Byte slices are treated as single values.
+func reflectSliceValues(values interface{}) []interface{} {
+	if values == nil {
+		return nil
+	}
+	if _, isBytes := values.([]byte); isBytes {
+		return []interface{}{values}
+	}
+	value := reflect.ValueOf(values)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []interface{}{values}
+	}
+	output := make([]interface{}, value.Len())
+	for index := 0; index < value.Len(); index++ {
+		output[index] = value.Index(index).Interface()
+	}
+	return output
+}
@@ -169,9 +169,12 @@ func (q *Query) query() (rows *sql.Rows, err error) {
 	}
 
 	var queryError error
-	db := q.Invocation.DB
+	db := q.Invocation.readDB()
 	ctx := q.Invocation.Context
 	rows, queryError = db.QueryContext(ctx, q.Statement, q.Args...)
+	if queryError != nil && q.Invocation.fallbackToPrimary(queryError) {
+		rows, queryError = q.Invocation.primaryDB().QueryContext(ctx, q.Statement, q.Args...)
+	}
 	if queryError != nil && !ex.Is(queryError, sql.ErrNoRows) {
 		err = Error(queryError)
 	}
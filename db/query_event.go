@@ -85,11 +85,21 @@ func OptQueryEventUsername(value string) QueryEventOption {
 	return func(e *QueryEvent) { e.Username = value }
 }
 
+// OptQueryEventPool sets a field on the query event.
+func OptQueryEventPool(pool string) QueryEventOption {
+	return func(e *QueryEvent) { e.Pool = pool }
+}
+
 // OptQueryEventLabel sets a field on the query event.
 func OptQueryEventLabel(label string) QueryEventOption {
 	return func(e *QueryEvent) { e.Label = label }
 }
 
+// OptQueryEventAttempt sets a field on the query event.
+func OptQueryEventAttempt(attempt int) QueryEventOption {
+	return func(e *QueryEvent) { e.Attempt = attempt }
+}
+
 // OptQueryEventElapsed sets a field on the query event.
 func OptQueryEventElapsed(value time.Duration) QueryEventOption {
 	return func(e *QueryEvent) { e.Elapsed = value }
@@ -105,8 +115,10 @@ type QueryEvent struct {
 	Database string
 	Engine   string
 	Username string
+	Pool     string
 	Label    string
 	Body     string
+	Attempt  int
 	Elapsed  time.Duration
 	Err      error
 }
@@ -126,6 +138,10 @@ func (e QueryEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
 		fmt.Fprint(wr, "@")
 	}
 	fmt.Fprint(wr, tf.Colorize(e.Database, ansi.ColorLightWhite))
+	if len(e.Pool) > 0 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprintf(wr, "(%s)", tf.Colorize(e.Pool, ansi.ColorLightWhite))
+	}
 	fmt.Fprint(wr, "]")
 
 	if len(e.Label) > 0 {
@@ -138,6 +154,11 @@ func (e QueryEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
 		fmt.Fprint(wr, stringutil.CompressSpace(e.Body))
 	}
 
+	if e.Attempt > 1 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprintf(wr, "attempt %d", e.Attempt)
+	}
+
 	fmt.Fprint(wr, logger.Space)
 	fmt.Fprint(wr, e.Elapsed.String())
 
@@ -149,13 +170,18 @@ func (e QueryEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
 
 // Decompose implements JSONWritable.
 func (e QueryEvent) Decompose() map[string]interface{} {
-	return map[string]interface{}{
+	output := map[string]interface{}{
 		"engine":   e.Engine,
 		"database": e.Database,
 		"username": e.Username,
+		"pool":     e.Pool,
 		"label":    e.Label,
 		"body":     e.Body,
 		"err":      e.Err,
 		"elapsed":  timeutil.Milliseconds(e.Elapsed),
 	}
+	if e.Attempt > 0 {
+		output["attempt"] = e.Attempt
+	}
+	return output
 }
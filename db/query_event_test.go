@@ -51,6 +51,19 @@ func TestQueryEvent(t *testing.T) {
 	contents, err := json.Marshal(qe)
 	assert.Nil(err)
 	assert.Contains(string(contents), "event-engine")
+
+	qe = NewQueryEvent("COMMIT", time.Millisecond, OptQueryEventLabel("in_tx"), OptQueryEventAttempt(2))
+	assert.Equal(2, qe.Attempt)
+	buf.Reset()
+	qe.WriteText(noColor, buf)
+	assert.Equal("[] [in_tx] COMMIT attempt 2 1ms", buf.String())
+	assert.Equal(2, qe.Decompose()["attempt"])
+
+	qe = NewQueryEvent("select 1", time.Millisecond, OptQueryEventDatabase("db"), OptQueryEventPool("replica-0"))
+	buf.Reset()
+	qe.WriteText(noColor, buf)
+	assert.Equal("[db (replica-0)] select 1 1ms", buf.String())
+	assert.Equal("replica-0", qe.Decompose()["pool"])
 }
 
 func TestQueryEventListener(t *testing.T) {
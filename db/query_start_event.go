@@ -89,6 +89,11 @@ func OptQueryStartEventUsername(value string) QueryStartEventOption {
 	return func(e *QueryStartEvent) { e.Username = value }
 }
 
+// OptQueryStartEventPool sets a field on the query event.
+func OptQueryStartEventPool(pool string) QueryStartEventOption {
+	return func(e *QueryStartEvent) { e.Pool = pool }
+}
+
 // OptQueryStartEventLabel sets a field on the query event.
 func OptQueryStartEventLabel(label string) QueryStartEventOption {
 	return func(e *QueryStartEvent) { e.Label = label }
@@ -99,6 +104,7 @@ type QueryStartEvent struct {
 	Database string
 	Engine   string
 	Username string
+	Pool     string
 	Label    string
 	Body     string
 }
@@ -118,6 +124,10 @@ func (e QueryStartEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
 		fmt.Fprint(wr, "@")
 	}
 	fmt.Fprint(wr, tf.Colorize(e.Database, ansi.ColorLightWhite))
+	if len(e.Pool) > 0 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprintf(wr, "(%s)", tf.Colorize(e.Pool, ansi.ColorLightWhite))
+	}
 	fmt.Fprint(wr, "]")
 
 	if len(e.Label) > 0 {
@@ -137,6 +147,7 @@ func (e QueryStartEvent) Decompose() map[string]interface{} {
 		"engine":   e.Engine,
 		"database": e.Database,
 		"username": e.Username,
+		"pool":     e.Pool,
 		"label":    e.Label,
 		"body":     e.Body,
 	}
@@ -0,0 +1,138 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// replicaLagStatement returns the replication lag of a postgres standby in seconds; it is zero
+// if the standby has replayed everything it has received, so an idle primary does not read as lag.
+const replicaLagStatement = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// ReplicaProvider returns a healthy read replica, or nil if there are none.
+type ReplicaProvider func() *Replica
+
+// NewReplica returns a new read replica for a given pool.
+func NewReplica(name string, conn *sql.DB, maxLag, checkInterval time.Duration) *Replica {
+	return &Replica{
+		Name:          name,
+		Connection:    conn,
+		MaxLag:        maxLag,
+		CheckInterval: checkInterval,
+		healthy:       true,
+	}
+}
+
+// Replica is a read replica connection pool.
+//
+// Replicas are considered healthy until a check fails; checks run in the background
+// when the status is read and the last check is older than the check interval.
+type Replica struct {
+	Name          string
+	Connection    *sql.DB
+	MaxLag        time.Duration
+	CheckInterval time.Duration
+
+	mu          sync.Mutex
+	healthy     bool
+	lag         time.Duration
+	lastErr     error
+	lastChecked time.Time
+	checking    bool
+}
+
+// Healthy returns if the replica can serve reads, starting a background check if the status is stale.
+func (r *Replica) Healthy() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.checking && time.Since(r.lastChecked) >= r.CheckInterval {
+		r.checking = true
+		go func() { _ = r.Check(context.Background()) }()
+	}
+	return r.healthy
+}
+
+// Lag returns the replication lag as of the last check.
+func (r *Replica) Lag() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lag
+}
+
+// Err returns the error of the last check, if any.
+func (r *Replica) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// Check pings the replica and reads its replication lag, marking it unhealthy
+// if it cannot be reached or its lag is greater than the max lag.
+func (r *Replica) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultReplicaCheckTimeout)
+	defer cancel()
+
+	var lagSeconds float64
+	err := r.Connection.QueryRowContext(ctx, replicaLagStatement).Scan(&lagSeconds)
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	if err == nil && r.MaxLag > 0 && lag > r.MaxLag {
+		err = ex.New(ErrReplicaLagging, ex.OptMessagef("replica: %s, lag: %v", r.Name, lag))
+	} else if err != nil {
+		err = Error(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checking = false
+	r.lastChecked = time.Now()
+	r.lag = lag
+	r.lastErr = err
+	r.healthy = err == nil
+	return err
+}
+
+// MarkUnhealthy marks the replica unhealthy until its next check.
+func (r *Replica) MarkUnhealthy(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.healthy = false
+	r.lastErr = err
+	r.lastChecked = time.Now()
+}
+
+// replicaName returns the pool name of the replica at a given index.
+func replicaName(index int) string {
+	return "replica-" + strconv.Itoa(index)
+}
+
+// isConnectionError returns if an error means the database could not be reached,
+// i.e. the statement can be retried against another pool.
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, ErrNetwork) {
+		return true
+	}
+	var netErr *net.OpError
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,129 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/logger"
+)
+
+// unreachableDSN is the dsn of a database that refuses connections.
+const unreachableDSN = "postgres://root@127.0.0.1:1/postgres?sslmode=disable&connect_timeout=2"
+
+func openUnreachable(its *assert.Assertions) *sql.DB {
+	namedValues, err := ParseURL(unreachableDSN)
+	its.Nil(err)
+	conn, err := sql.Open(DefaultEngine, namedValues)
+	its.Nil(err)
+	return conn
+}
+
+func checkedReplica(name string, conn *sql.DB, healthy bool) *Replica {
+	replica := NewReplica(name, conn, DefaultReplicaMaxLag, time.Hour)
+	replica.healthy = healthy
+	replica.lastChecked = time.Now()
+	return replica
+}
+
+func Test_Connection_Replica(t *testing.T) {
+	its := assert.New(t)
+
+	conn := &Connection{
+		Replicas: []*Replica{
+			checkedReplica("replica-0", nil, true),
+			checkedReplica("replica-1", nil, false),
+			checkedReplica("replica-2", nil, true),
+		},
+	}
+	seen := map[string]int{}
+	for x := 0; x < 10; x++ {
+		seen[conn.Replica().Name]++
+	}
+	its.NotZero(seen["replica-0"])
+	its.NotZero(seen["replica-2"])
+	its.Equal(10, seen["replica-0"]+seen["replica-2"])
+	its.Zero(seen["replica-1"])
+
+	conn.Replicas[0].MarkUnhealthy(fmt.Errorf("test"))
+	conn.Replicas[2].MarkUnhealthy(fmt.Errorf("test"))
+	its.Nil(conn.Replica())
+	its.Nil((&Connection{}).Replica())
+}
+
+func Test_Connection_Invoke_replicas(t *testing.T) {
+	its := assert.New(t)
+
+	replica := checkedReplica("replica-0", openUnreachable(its), true)
+	conn := &Connection{Replicas: []*Replica{replica}}
+
+	i := conn.Invoke()
+	its.Equal(PoolPrimary, i.Pool)
+	its.NotNil(i.ReplicaProvider)
+	i.routeRead()
+	its.Equal("replica-0", i.Pool)
+	its.Equal(replica.Connection, i.readDB())
+
+	its.Nil(conn.Invoke(OptPrimary()).ReplicaProvider)
+	its.Nil(conn.Invoke(OptTx(new(sql.Tx))).ReplicaProvider)
+	its.Nil(conn.Invoke(OptTx(nil)).ReplicaProvider)
+	its.Nil(conn.Invoke(OptInvocationDB(replica.Connection)).ReplicaProvider)
+	its.Empty((&Connection{}).Invoke().Pool)
+}
+
+func Test_Invocation_QueryStatement_replicas(t *testing.T) {
+	its := assert.New(t)
+
+	replica := checkedReplica("replica-0", openUnreachable(its), true)
+	conn := &Connection{Replicas: []*Replica{replica}}
+
+	q := conn.Invoke().QueryStatement(InsertInto("users").Columns("name").Values("foo").Returning("id"))
+	its.Equal(PoolPrimary, q.Invocation.Pool)
+	its.Nil(q.Invocation.replica)
+
+	q = conn.Invoke().QueryStatement(Select("id").From("users"))
+	its.Equal("replica-0", q.Invocation.Pool)
+}
+
+func Test_Replica_Check_unreachable(t *testing.T) {
+	its := assert.New(t)
+
+	replica := NewReplica("replica-0", openUnreachable(its), DefaultReplicaMaxLag, time.Hour)
+	its.True(replica.Healthy())
+	err := replica.Check(context.Background())
+	its.NotNil(err)
+	its.True(isConnectionError(err), fmt.Sprintf("%+v", err))
+	its.False(replica.Healthy())
+	its.Equal(err, replica.Err())
+}
+
+func Test_Invocation_Query_replicaFallback(t *testing.T) {
+	its := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	replica := checkedReplica("replica-0", openUnreachable(its), true)
+	conn := *defaultDB()
+	conn.Log = logger.Memory(buf)
+	conn.Replicas = []*Replica{replica}
+
+	var value int
+	found, err := conn.Invoke(OptLabel("fallback")).Query("select 1").Scan(&value)
+	its.Nil(err)
+	its.True(found)
+	its.Equal(1, value)
+	its.False(replica.Healthy())
+	its.True(strings.Contains(buf.String(), "(replica-0)] [fallback]"), buf.String())
+	its.True(strings.Contains(buf.String(), "(primary)] [fallback]"), buf.String())
+}
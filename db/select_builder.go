@@ -0,0 +1,200 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+// Interface assertions.
+var (
+	_ StatementBuilder = (*SelectBuilder)(nil)
+)
+
+// Select returns a select statement builder for a given set of columns.
+//
+// If no columns are given, all columns are selected.
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{
+		columns: columns,
+	}
+}
+
+// SelectFrom returns a select statement builder for the table and the
+// non-readonly columns of a database mapped type.
+//
+// The result can be read into the type with `Query.Out` or `Query.OutMany`.
+func SelectFrom(object DatabaseMapped) *SelectBuilder {
+	return &SelectBuilder{
+		columns:        Columns(object).NotReadOnly().ColumnNames(),
+		qualifyColumns: true,
+		table:          TableName(object),
+	}
+}
+
+// SelectBuilder builds select statements.
+type SelectBuilder struct {
+	label          string
+	columns        []string
+	qualifyColumns bool
+	table          string
+	alias          string
+	joins          []selectJoin
+	where          []Predicate
+	groupBy        []string
+	orderBy        []selectOrder
+	limit          int
+	offset         int
+}
+
+type selectJoin struct {
+	kind  string
+	table string
+	alias string
+	on    []Predicate
+}
+
+type selectOrder struct {
+	column     string
+	descending bool
+}
+
+// WithLabel sets the label for the statement.
+func (sb *SelectBuilder) WithLabel(label string) *SelectBuilder {
+	sb.label = label
+	return sb
+}
+
+// From sets the table to select from.
+func (sb *SelectBuilder) From(table string) *SelectBuilder {
+	sb.table = table
+	return sb
+}
+
+// As sets the alias of the table to select from.
+//
+// The columns of a builder returned by `SelectFrom` are qualified with the alias.
+func (sb *SelectBuilder) As(alias string) *SelectBuilder {
+	sb.alias = alias
+	return sb
+}
+
+// InnerJoin adds an inner join to a table with a given alias on a given set of predicates.
+func (sb *SelectBuilder) InnerJoin(table, alias string, on ...Predicate) *SelectBuilder {
+	sb.joins = append(sb.joins, selectJoin{kind: "INNER JOIN", table: table, alias: alias, on: on})
+	return sb
+}
+
+// LeftJoin adds a left outer join to a table with a given alias on a given set of predicates.
+func (sb *SelectBuilder) LeftJoin(table, alias string, on ...Predicate) *SelectBuilder {
+	sb.joins = append(sb.joins, selectJoin{kind: "LEFT JOIN", table: table, alias: alias, on: on})
+	return sb
+}
+
+// Where adds predicates to the where clause; all predicates must be true.
+func (sb *SelectBuilder) Where(predicates ...Predicate) *SelectBuilder {
+	sb.where = append(sb.where, predicates...)
+	return sb
+}
+
+// GroupBy adds columns to the group by clause.
+func (sb *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	sb.groupBy = append(sb.groupBy, columns...)
+	return sb
+}
+
+// OrderBy adds columns to the order by clause in ascending order.
+func (sb *SelectBuilder) OrderBy(columns ...string) *SelectBuilder {
+	for _, column := range columns {
+		sb.orderBy = append(sb.orderBy, selectOrder{column: column})
+	}
+	return sb
+}
+
+// OrderByDesc adds columns to the order by clause in descending order.
+func (sb *SelectBuilder) OrderByDesc(columns ...string) *SelectBuilder {
+	for _, column := range columns {
+		sb.orderBy = append(sb.orderBy, selectOrder{column: column, descending: true})
+	}
+	return sb
+}
+
+// Limit sets the maximum number of rows to return; zero means no limit.
+func (sb *SelectBuilder) Limit(limit int) *SelectBuilder {
+	sb.limit = limit
+	return sb
+}
+
+// Offset sets the number of rows to skip.
+func (sb *SelectBuilder) Offset(offset int) *SelectBuilder {
+	sb.offset = offset
+	return sb
+}
+
+// Label implements StatementBuilder.
+func (sb *SelectBuilder) Label() string {
+	if sb.label != "" {
+		return sb.label
+	}
+	return sb.table + "_select"
+}
+
+// Build implements StatementBuilder.
+func (sb *SelectBuilder) Build(dialect Dialect) (string, []interface{}) {
+	sw := newStatementWriter(dialect)
+	sw.WriteString("SELECT ")
+	if len(sb.columns) == 0 {
+		sw.WriteString("*")
+	} else {
+		for index, column := range sb.columns {
+			if index > 0 {
+				sw.WriteString(",")
+			}
+			if sb.qualifyColumns && sb.alias != "" {
+				sw.WriteIdentifier(sb.alias + "." + column)
+				continue
+			}
+			sw.WriteIdentifier(column)
+		}
+	}
+	if sb.table != "" {
+		sw.WriteString(" FROM ")
+		sw.WriteIdentifier(sb.table)
+		if sb.alias != "" {
+			sw.WriteString(" ")
+			sw.WriteIdentifier(sb.alias)
+		}
+	}
+	for _, join := range sb.joins {
+		sw.WriteString(" " + join.kind + " ")
+		sw.WriteIdentifier(join.table)
+		if join.alias != "" {
+			sw.WriteString(" ")
+			sw.WriteIdentifier(join.alias)
+		}
+		sw.WriteString(" ON ")
+		And(join.on...).writePredicate(sw)
+	}
+	sw.WriteWhere(sb.where)
+	if len(sb.groupBy) > 0 {
+		sw.WriteString(" GROUP BY ")
+		sw.WriteIdentifiers(sb.groupBy)
+	}
+	if len(sb.orderBy) > 0 {
+		sw.WriteString(" ORDER BY ")
+		for index, order := range sb.orderBy {
+			if index > 0 {
+				sw.WriteString(",")
+			}
+			sw.WriteIdentifier(order.column)
+			if order.descending {
+				sw.WriteString(" DESC")
+			} else {
+				sw.WriteString(" ASC")
+			}
+		}
+	}
+	sw.WriteLimitOffset(sb.limit, sb.offset)
+	return sw.Result()
+}
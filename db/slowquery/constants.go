@@ -0,0 +1,27 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import "time"
+
+// Logger flags
+const (
+	Flag = "db.slow_query"
+)
+
+// Defaults
+const (
+	// DefaultThreshold is the default elapsed time after which a statement is considered slow.
+	DefaultThreshold = 500 * time.Millisecond
+	// DefaultSampleRate is the default fraction of slow statements that are explained.
+	DefaultSampleRate = 0.1
+	// DefaultWindowSize is the default number of recent elapsed times per label the percentiles are computed from.
+	DefaultWindowSize = 1024
+	// DefaultExplainTimeout is the default timeout for capturing a query plan.
+	DefaultExplainTimeout = 5 * time.Second
+)
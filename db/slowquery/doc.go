@@ -0,0 +1,23 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+/*
+Package slowquery provides a db tracer that detects slow statements.
+
+The `Monitor` measures every statement a connection runs and keeps a latency summary per statement label.
+Statements that take longer than a threshold trigger a `db.slow_query` logger event, and for a sample of
+them the event includes the postgres query plan from `EXPLAIN (FORMAT JSON)`.
+
+	monitor := slowquery.New(slowquery.OptLog(log), slowquery.OptThreshold(250*time.Millisecond))
+	conn, err := db.Open(db.New(db.OptConfig(cfg), db.OptTracer(monitor)))
+	...
+	monitor.Conn = conn
+	app.GET("/debug/db/latency", monitor.Endpoint())
+
+The plans are captured without running the statement; statements with parameters are explained using a generic plan.
+*/
+package slowquery // import "github.com/zpkg/blend-go-sdk/db/slowquery"
@@ -0,0 +1,173 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/ansi"
+	"github.com/zpkg/blend-go-sdk/logger"
+	"github.com/zpkg/blend-go-sdk/stringutil"
+	"github.com/zpkg/blend-go-sdk/timeutil"
+)
+
+// these are compile time assertions
+var (
+	_ logger.Event        = (*Event)(nil)
+	_ logger.TextWritable = (*Event)(nil)
+	_ logger.JSONWritable = (*Event)(nil)
+)
+
+// NewEvent creates a new slow query event.
+func NewEvent(label, body string, elapsed time.Duration, options ...EventOption) Event {
+	e := Event{
+		Label:   label,
+		Body:    body,
+		Elapsed: elapsed,
+	}
+	for _, opt := range options {
+		opt(&e)
+	}
+	return e
+}
+
+// NewEventListener returns a new listener for slow query events.
+func NewEventListener(listener func(context.Context, Event)) logger.Listener {
+	return func(ctx context.Context, e logger.Event) {
+		if typed, isTyped := e.(Event); isTyped {
+			listener(ctx, typed)
+		}
+	}
+}
+
+// NewEventFilter returns a new slow query event filter.
+func NewEventFilter(filter func(context.Context, Event) (Event, bool)) logger.Filter {
+	return func(ctx context.Context, e logger.Event) (logger.Event, bool) {
+		if typed, isTyped := e.(Event); isTyped {
+			return filter(ctx, typed)
+		}
+		return e, false
+	}
+}
+
+// EventOption mutates a slow query event.
+type EventOption func(*Event)
+
+// OptEventDatabase sets a field on the slow query event.
+func OptEventDatabase(value string) EventOption {
+	return func(e *Event) { e.Database = value }
+}
+
+// OptEventEngine sets a field on the slow query event.
+func OptEventEngine(value string) EventOption {
+	return func(e *Event) { e.Engine = value }
+}
+
+// OptEventUsername sets a field on the slow query event.
+func OptEventUsername(value string) EventOption {
+	return func(e *Event) { e.Username = value }
+}
+
+// OptEventThreshold sets a field on the slow query event.
+func OptEventThreshold(value time.Duration) EventOption {
+	return func(e *Event) { e.Threshold = value }
+}
+
+// OptEventPlan sets a field on the slow query event.
+func OptEventPlan(value string) EventOption {
+	return func(e *Event) { e.Plan = value }
+}
+
+// OptEventErr sets a field on the slow query event.
+func OptEventErr(value error) EventOption {
+	return func(e *Event) { e.Err = value }
+}
+
+// Event represents a statement that took longer than the slow query threshold.
+type Event struct {
+	Database  string
+	Engine    string
+	Username  string
+	Label     string
+	Body      string
+	Elapsed   time.Duration
+	Threshold time.Duration
+	// Plan is the query plan of the statement as json, if it was explained.
+	Plan string
+	Err  error
+}
+
+// GetFlag implements Event.
+func (e Event) GetFlag() string { return Flag }
+
+// WriteText writes the event text to the output.
+func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	fmt.Fprint(wr, "[")
+	if len(e.Engine) > 0 {
+		fmt.Fprint(wr, tf.Colorize(e.Engine, ansi.ColorLightWhite))
+		fmt.Fprint(wr, logger.Space)
+	}
+	if len(e.Username) > 0 {
+		fmt.Fprint(wr, tf.Colorize(e.Username, ansi.ColorLightWhite))
+		fmt.Fprint(wr, "@")
+	}
+	fmt.Fprint(wr, tf.Colorize(e.Database, ansi.ColorLightWhite))
+	fmt.Fprint(wr, "]")
+
+	if len(e.Label) > 0 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprintf(wr, "[%s]", tf.Colorize(e.Label, ansi.ColorLightWhite))
+	}
+
+	if len(e.Body) > 0 {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprint(wr, stringutil.CompressSpace(e.Body))
+	}
+
+	fmt.Fprint(wr, logger.Space)
+	fmt.Fprint(wr, tf.Colorize(e.Elapsed.String(), ansi.ColorYellow))
+	if e.Threshold > 0 {
+		fmt.Fprintf(wr, " (threshold %v)", e.Threshold)
+	}
+
+	if e.Err != nil {
+		fmt.Fprint(wr, logger.Space)
+		fmt.Fprint(wr, tf.Colorize("failed", ansi.ColorRed))
+	}
+
+	if len(e.Plan) > 0 {
+		fmt.Fprint(wr, logger.Newline)
+		fmt.Fprint(wr, e.Plan)
+	}
+}
+
+// Decompose implements JSONWritable.
+func (e Event) Decompose() map[string]interface{} {
+	output := map[string]interface{}{
+		"engine":    e.Engine,
+		"database":  e.Database,
+		"username":  e.Username,
+		"label":     e.Label,
+		"body":      e.Body,
+		"err":       e.Err,
+		"elapsed":   timeutil.Milliseconds(e.Elapsed),
+		"threshold": timeutil.Milliseconds(e.Threshold),
+	}
+	if len(e.Plan) > 0 {
+		if json.Valid([]byte(e.Plan)) {
+			output["plan"] = json.RawMessage(e.Plan)
+		} else {
+			output["plan"] = e.Plan
+		}
+	}
+	return output
+}
@@ -0,0 +1,82 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/logger"
+)
+
+func Test_Event(t *testing.T) {
+	its := assert.New(t)
+
+	e := NewEvent("event-label", "SELECT 1", 2*time.Second,
+		OptEventDatabase("event-database"),
+		OptEventEngine("event-engine"),
+		OptEventUsername("event-username"),
+		OptEventThreshold(time.Second),
+	)
+	its.Equal(Flag, e.GetFlag())
+	its.Equal("event-label", e.Label)
+	its.Equal("SELECT 1", e.Body)
+	its.Equal(2*time.Second, e.Elapsed)
+	its.Equal(time.Second, e.Threshold)
+
+	buf := new(bytes.Buffer)
+	noColor := logger.TextOutputFormatter{
+		NoColor: true,
+	}
+
+	e.WriteText(noColor, buf)
+	its.Equal("[event-engine event-username@event-database] [event-label] SELECT 1 2s (threshold 1s)", buf.String())
+
+	buf.Reset()
+	OptEventErr(fmt.Errorf("this is only a test"))(&e)
+	OptEventPlan(`[{"Plan": {"Node Type": "Result"}}]`)(&e)
+	e.WriteText(noColor, buf)
+	its.Equal("[event-engine event-username@event-database] [event-label] SELECT 1 2s (threshold 1s) failed\n[{\"Plan\": {\"Node Type\": \"Result\"}}]", buf.String())
+
+	contents, err := json.Marshal(e.Decompose())
+	its.Nil(err)
+	its.Contains(string(contents), `"plan":[{"Plan":{"Node Type":"Result"}}]`)
+	its.Contains(string(contents), `"elapsed":2000`)
+}
+
+func Test_EventListener(t *testing.T) {
+	its := assert.New(t)
+
+	e := NewEvent("label", "SELECT 1", time.Second)
+
+	var didCall bool
+	listener := NewEventListener(func(ctx context.Context, e Event) {
+		didCall = true
+	})
+	listener(context.Background(), e)
+	its.True(didCall)
+}
+
+func Test_EventFilter(t *testing.T) {
+	its := assert.New(t)
+
+	e := NewEvent("label", "SELECT 1", time.Second)
+
+	filter := NewEventFilter(func(ctx context.Context, e Event) (Event, bool) {
+		e.Body = "filtered"
+		return e, false
+	})
+	filtered, filterOut := filter(context.Background(), e)
+	its.False(filterOut)
+	its.Equal("filtered", filtered.(Event).Body)
+}
@@ -0,0 +1,196 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+// explainStatementName is the name of the prepared statement parameterized statements are explained with.
+const explainStatementName = "slowquery_explain"
+
+// explain returns the json query plan of a statement, without running the statement.
+//
+// The tracer does not see the arguments of a statement, so a statement with parameters is prepared
+// and explained with a generic plan, i.e. a plan that does not depend on the parameter values.
+// The prepared statement is deallocated before the connection is returned to the pool.
+func explain(ctx context.Context, pool *sql.DB, statement string) (plan string, err error) {
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	defer conn.Close()
+
+	parameters := parameterCount(statement)
+	if parameters == 0 {
+		err = conn.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+statement).Scan(&plan)
+		return plan, ex.New(err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, "SET LOCAL plan_cache_mode = force_generic_plan"); err != nil {
+		return "", ex.New(err)
+	}
+	if _, err = tx.ExecContext(ctx, "PREPARE "+explainStatementName+" AS "+statement); err != nil {
+		return "", ex.New(err)
+	}
+	// prepared statements outlive the transaction, and have to be deallocated once it is rolled back.
+	defer func() {
+		_ = tx.Rollback()
+		if _, deallocateErr := conn.ExecContext(context.Background(), "DEALLOCATE "+explainStatementName); deallocateErr != nil {
+			err = ex.Nest(err, ex.New(deallocateErr))
+			// a connection with a dangling prepared statement must not be reused.
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	arguments := strings.TrimSuffix(strings.Repeat("NULL, ", parameters), ", ")
+	err = tx.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) EXECUTE "+explainStatementName+"("+arguments+")").Scan(&plan)
+	return plan, ex.New(err)
+}
+
+// isExplainable returns if a statement can be explained, i.e. it is a query or a data modifying statement.
+func isExplainable(statement string) bool {
+	switch strings.ToLower(firstKeyword(statement)) {
+	case "select", "insert", "update", "delete", "with", "values", "table":
+		return true
+	default:
+		return false
+	}
+}
+
+// firstKeyword returns the first word of a statement, skipping leading comments and parentheses.
+func firstKeyword(statement string) string {
+	for index := 0; index < len(statement); {
+		switch {
+		case strings.HasPrefix(statement[index:], "--"):
+			end := strings.IndexByte(statement[index:], '\n')
+			if end < 0 {
+				return ""
+			}
+			index += end + 1
+		case strings.HasPrefix(statement[index:], "/*"):
+			end := strings.Index(statement[index+2:], "*/")
+			if end < 0 {
+				return ""
+			}
+			index += end + 4
+		case isSpace(statement[index]) || statement[index] == '(':
+			index++
+		default:
+			end := index
+			for end < len(statement) && isWordByte(statement[end]) {
+				end++
+			}
+			return statement[index:end]
+		}
+	}
+	return ""
+}
+
+// parameterCount returns the number of parameters of a postgres statement, i.e. the highest `$n` placeholder.
+//
+// Placeholders within string literals, quoted identifiers, dollar quoted strings and comments are ignored.
+func parameterCount(statement string) (count int) {
+	for index := 0; index < len(statement); {
+		switch {
+		case statement[index] == '\'' || statement[index] == '"':
+			index = skipQuoted(statement, index, statement[index])
+		case strings.HasPrefix(statement[index:], "--"):
+			end := strings.IndexByte(statement[index:], '\n')
+			if end < 0 {
+				return
+			}
+			index += end + 1
+		case strings.HasPrefix(statement[index:], "/*"):
+			end := strings.Index(statement[index+2:], "*/")
+			if end < 0 {
+				return
+			}
+			index += end + 4
+		case statement[index] == '$':
+			end := index + 1
+			for end < len(statement) && isDigit(statement[end]) {
+				end++
+			}
+			if end > index+1 {
+				if value, err := strconv.Atoi(statement[index+1 : end]); err == nil && value > count {
+					count = value
+				}
+				index = end
+				continue
+			}
+			index = skipDollarQuoted(statement, index)
+		case isWordByte(statement[index]):
+			// a `$` within an identifier, e.g. `foo$1`, is not a placeholder.
+			for index < len(statement) && (isWordByte(statement[index]) || statement[index] == '$') {
+				index++
+			}
+		default:
+			index++
+		}
+	}
+	return
+}
+
+// skipQuoted returns the index after the quoted string or identifier that starts at an index.
+func skipQuoted(statement string, index int, quote byte) int {
+	for index = index + 1; index < len(statement); index++ {
+		if statement[index] == quote {
+			// a doubled quote is an escaped quote.
+			if index+1 < len(statement) && statement[index+1] == quote {
+				index++
+				continue
+			}
+			return index + 1
+		}
+	}
+	return index
+}
+
+// skipDollarQuoted returns the index after the dollar quoted string that starts at an index, e.g. `$tag$...$tag$`.
+//
+// If there is no dollar quoted string at the index, it returns the next index.
+func skipDollarQuoted(statement string, index int) int {
+	end := index + 1
+	for end < len(statement) && isWordByte(statement[end]) {
+		end++
+	}
+	if end >= len(statement) || statement[end] != '$' {
+		return index + 1
+	}
+	tag := statement[index : end+1]
+	closing := strings.Index(statement[end+1:], tag)
+	if closing < 0 {
+		return len(statement)
+	}
+	return end + 1 + closing + len(tag)
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || isDigit(b) || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b >= 0x80
+}
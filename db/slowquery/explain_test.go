@@ -0,0 +1,41 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+)
+
+func Test_parameterCount(t *testing.T) {
+	its := assert.New(t)
+
+	its.Equal(0, parameterCount("SELECT 1"))
+	its.Equal(2, parameterCount("SELECT * FROM users WHERE id = $2 AND email = $1"))
+	its.Equal(12, parameterCount("INSERT INTO t (a) VALUES ($12)"))
+	its.Equal(1, parameterCount("SELECT '$2', \"$3\", $1 -- $4\n/* $5 */"))
+	its.Equal(1, parameterCount("SELECT 'it''s $2', $1"))
+	its.Equal(1, parameterCount("SELECT $body$ $2 $body$, $$ $3 $$, $1"))
+	its.Equal(0, parameterCount("SELECT foo$1 FROM bar"))
+}
+
+func Test_isExplainable(t *testing.T) {
+	its := assert.New(t)
+
+	its.True(isExplainable("SELECT 1"))
+	its.True(isExplainable("  -- comment\n/* block */ (select 1) UNION (select 2)"))
+	its.True(isExplainable("WITH x AS (SELECT 1) SELECT * FROM x"))
+	its.True(isExplainable("insert into t values (1)"))
+	its.True(isExplainable("UPDATE t SET a = 1"))
+	its.True(isExplainable("DELETE FROM t"))
+	its.False(isExplainable("CREATE TABLE t (a int)"))
+	its.False(isExplainable("EXPLAIN SELECT 1"))
+	its.False(isExplainable("-- only a comment"))
+	its.False(isExplainable(""))
+}
@@ -0,0 +1,302 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/logger"
+	"github.com/zpkg/blend-go-sdk/web"
+)
+
+var (
+	_ db.Tracer             = (*Monitor)(nil)
+	_ db.NotificationTracer = (*Monitor)(nil)
+)
+
+// New returns a new monitor.
+func New(options ...Option) *Monitor {
+	var m Monitor
+	for _, opt := range options {
+		opt(&m)
+	}
+	return &m
+}
+
+// Option mutates a monitor.
+type Option func(*Monitor)
+
+// OptLog sets the logger slow query events are triggered on.
+func OptLog(log logger.Log) Option {
+	return func(m *Monitor) { m.Log = log }
+}
+
+// OptConn sets the connection slow statements are explained with.
+func OptConn(conn *db.Connection) Option {
+	return func(m *Monitor) { m.Conn = conn }
+}
+
+// OptNext sets a tracer the monitor passes traces on to, e.g. a `dbtrace` tracer.
+func OptNext(next db.Tracer) Option {
+	return func(m *Monitor) { m.Next = next }
+}
+
+// OptThreshold sets the elapsed time after which a statement is considered slow.
+func OptThreshold(threshold time.Duration) Option {
+	return func(m *Monitor) { m.Threshold = threshold }
+}
+
+// OptSampleRate sets the fraction of slow statements that are explained, on the interval (0, 1].
+func OptSampleRate(sampleRate float64) Option {
+	return func(m *Monitor) { m.SampleRate = sampleRate }
+}
+
+// OptWindowSize sets the number of recent elapsed times per label the percentiles are computed from.
+func OptWindowSize(windowSize int) Option {
+	return func(m *Monitor) { m.WindowSize = windowSize }
+}
+
+// OptExplainTimeout sets the timeout for capturing a query plan.
+func OptExplainTimeout(timeout time.Duration) Option {
+	return func(m *Monitor) { m.ExplainTimeout = timeout }
+}
+
+// Monitor is a db tracer that keeps a latency summary per statement label and reports slow statements.
+//
+// A statement that takes longer than the threshold triggers a slow query event on the logger.
+// If the monitor has a postgres connection, a sample of the slow statements are explained and the
+// event includes the query plan; the plans are captured in the background, one at a time.
+//
+// The connection is typically set after it is opened with the monitor as its tracer.
+type Monitor struct {
+	Log  logger.Log
+	Conn *db.Connection
+	// Next is an optional tracer the monitor passes traces on to.
+	Next           db.Tracer
+	Threshold      time.Duration
+	SampleRate     float64
+	WindowSize     int
+	ExplainTimeout time.Duration
+
+	latenciesMu sync.Mutex
+	latencies   map[string]*latencyWindow
+	explaining  int32
+	// random returns a number on the interval [0, 1) slow statements are sampled with.
+	random func() float64
+}
+
+// ThresholdOrDefault returns the slow query threshold or a default.
+func (m *Monitor) ThresholdOrDefault() time.Duration {
+	if m.Threshold > 0 {
+		return m.Threshold
+	}
+	return DefaultThreshold
+}
+
+// SampleRateOrDefault returns the sample rate or a default.
+func (m *Monitor) SampleRateOrDefault() float64 {
+	if m.SampleRate > 0 && m.SampleRate <= 1 {
+		return m.SampleRate
+	}
+	return DefaultSampleRate
+}
+
+// WindowSizeOrDefault returns the window size or a default.
+func (m *Monitor) WindowSizeOrDefault() int {
+	if m.WindowSize > 0 {
+		return m.WindowSize
+	}
+	return DefaultWindowSize
+}
+
+// ExplainTimeoutOrDefault returns the explain timeout or a default.
+func (m *Monitor) ExplainTimeoutOrDefault() time.Duration {
+	if m.ExplainTimeout > 0 {
+		return m.ExplainTimeout
+	}
+	return DefaultExplainTimeout
+}
+
+// Prepare implements db.Tracer.
+func (m *Monitor) Prepare(ctx context.Context, cfg db.Config, statement string) db.TraceFinisher {
+	if m.Next != nil {
+		return m.Next.Prepare(ctx, cfg, statement)
+	}
+	return nil
+}
+
+// Query implements db.Tracer.
+func (m *Monitor) Query(ctx context.Context, cfg db.Config, label, statement string) db.TraceFinisher {
+	finisher := monitorTraceFinisher{
+		monitor:   m,
+		config:    cfg,
+		label:     label,
+		statement: statement,
+		start:     time.Now(),
+	}
+	if m.Next != nil {
+		finisher.next = m.Next.Query(ctx, cfg, label, statement)
+	}
+	return finisher
+}
+
+// Notification implements db.NotificationTracer, and passes the trace on to the next tracer.
+func (m *Monitor) Notification(ctx context.Context, cfg db.Config, n db.Notification) db.NotificationTraceFinisher {
+	if tracer, ok := m.Next.(db.NotificationTracer); ok {
+		return tracer.Notification(ctx, cfg, n)
+	}
+	return nil
+}
+
+// Summary returns the latency summary for a label, and if any statements were run with the label.
+func (m *Monitor) Summary(label string) (LatencySummary, bool) {
+	m.latenciesMu.Lock()
+	defer m.latenciesMu.Unlock()
+	window, ok := m.latencies[label]
+	if !ok {
+		return LatencySummary{}, false
+	}
+	return window.summary(label), true
+}
+
+// Summaries returns the latency summaries for every label, sorted by label.
+//
+// Statements run without a label are summarized under the empty label.
+func (m *Monitor) Summaries() []LatencySummary {
+	m.latenciesMu.Lock()
+	defer m.latenciesMu.Unlock()
+	output := make([]LatencySummary, 0, len(m.latencies))
+	for label, window := range m.latencies {
+		output = append(output, window.summary(label))
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Label < output[j].Label
+	})
+	return output
+}
+
+// Reset clears the latency summaries.
+func (m *Monitor) Reset() {
+	m.latenciesMu.Lock()
+	defer m.latenciesMu.Unlock()
+	m.latencies = nil
+}
+
+// Endpoint returns a web action that renders the latency summaries as json.
+func (m *Monitor) Endpoint() web.Action {
+	return func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(m.Summaries())
+	}
+}
+
+//
+// Private / Internal
+//
+
+// record adds an elapsed time to the latency summary of a label.
+func (m *Monitor) record(label string, elapsed time.Duration, slow bool) {
+	m.latenciesMu.Lock()
+	defer m.latenciesMu.Unlock()
+	if m.latencies == nil {
+		m.latencies = make(map[string]*latencyWindow)
+	}
+	window, ok := m.latencies[label]
+	if !ok {
+		window = newLatencyWindow(m.WindowSizeOrDefault())
+		m.latencies[label] = window
+	}
+	window.add(elapsed, slow)
+}
+
+// report triggers a slow query event, explaining the statement if it is sampled.
+func (m *Monitor) report(ctx context.Context, cfg db.Config, label, statement string, elapsed time.Duration, err error) {
+	if m.Log == nil {
+		return
+	}
+	options := []EventOption{
+		OptEventDatabase(cfg.DatabaseOrDefault()),
+		OptEventEngine(cfg.EngineOrDefault()),
+		OptEventUsername(cfg.Username),
+		OptEventThreshold(m.ThresholdOrDefault()),
+		OptEventErr(err),
+	}
+	pool := m.explainPool(cfg, statement)
+	// only one plan is captured at a time; slow statements that arrive meanwhile are reported without a plan.
+	if pool == nil || !atomic.CompareAndSwapInt32(&m.explaining, 0, 1) {
+		m.Log.TriggerContext(ctx, NewEvent(label, statement, elapsed, options...))
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&m.explaining, 0)
+		explainCtx, cancel := context.WithTimeout(context.Background(), m.ExplainTimeoutOrDefault())
+		defer cancel()
+		plan, explainErr := explain(explainCtx, pool, statement)
+		if explainErr != nil {
+			logger.MaybeWarningContext(ctx, m.Log, explainErr)
+		} else {
+			options = append(options, OptEventPlan(plan))
+		}
+		m.Log.TriggerContext(ctx, NewEvent(label, statement, elapsed, options...))
+	}()
+}
+
+// explainPool returns the connection pool a slow statement should be explained with, or nil
+// if the statement is not sampled or cannot be explained.
+func (m *Monitor) explainPool(cfg db.Config, statement string) *sql.DB {
+	if m.Conn == nil || m.Conn.Connection == nil {
+		return nil
+	}
+	if !cfg.DialectOrDefault().Is(db.DialectPostgres) || !isExplainable(statement) {
+		return nil
+	}
+	if m.sample() >= m.SampleRateOrDefault() {
+		return nil
+	}
+	return m.Conn.Connection
+}
+
+func (m *Monitor) sample() float64 {
+	if m.random != nil {
+		return m.random()
+	}
+	return rand.Float64()
+}
+
+type monitorTraceFinisher struct {
+	monitor   *Monitor
+	next      db.TraceFinisher
+	config    db.Config
+	label     string
+	statement string
+	start     time.Time
+}
+
+func (mtf monitorTraceFinisher) FinishPrepare(ctx context.Context, err error) {
+	if mtf.next != nil {
+		mtf.next.FinishPrepare(ctx, err)
+	}
+}
+
+func (mtf monitorTraceFinisher) FinishQuery(ctx context.Context, res sql.Result, err error) {
+	elapsed := time.Since(mtf.start)
+	slow := elapsed >= mtf.monitor.ThresholdOrDefault()
+	mtf.monitor.record(mtf.label, elapsed, slow)
+	if mtf.next != nil {
+		mtf.next.FinishQuery(ctx, res, err)
+	}
+	if slow {
+		mtf.monitor.report(ctx, mtf.config, mtf.label, mtf.statement, elapsed, err)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
+	"github.com/zpkg/blend-go-sdk/logger"
+	"github.com/zpkg/blend-go-sdk/web"
+)
+
+func Test_Monitor_defaults(t *testing.T) {
+	its := assert.New(t)
+
+	m := New()
+	its.Equal(DefaultThreshold, m.ThresholdOrDefault())
+	its.Equal(DefaultSampleRate, m.SampleRateOrDefault())
+	its.Equal(DefaultWindowSize, m.WindowSizeOrDefault())
+	its.Equal(DefaultExplainTimeout, m.ExplainTimeoutOrDefault())
+
+	m = New(
+		OptThreshold(time.Second),
+		OptSampleRate(0.5),
+		OptWindowSize(10),
+		OptExplainTimeout(time.Millisecond),
+	)
+	its.Equal(time.Second, m.ThresholdOrDefault())
+	its.Equal(0.5, m.SampleRateOrDefault())
+	its.Equal(10, m.WindowSizeOrDefault())
+	its.Equal(time.Millisecond, m.ExplainTimeoutOrDefault())
+}
+
+func Test_Monitor_Query(t *testing.T) {
+	its := assert.New(t)
+
+	log := logger.Memory(new(bytes.Buffer))
+	defer log.Close()
+	events := make(chan Event, 1)
+	log.Listen(Flag, "test", NewEventListener(func(_ context.Context, e Event) {
+		events <- e
+	}))
+
+	next := new(mockTracer)
+	m := New(OptLog(log), OptNext(next), OptThreshold(time.Hour))
+	cfg := db.Config{Database: "test-database", Username: "test-user"}
+
+	m.Query(context.Background(), cfg, "fast", "SELECT 1").FinishQuery(context.Background(), nil, nil)
+	m.Query(context.Background(), cfg, "fast", "SELECT 1").FinishQuery(context.Background(), nil, nil)
+	its.Equal(2, next.queries)
+	its.Equal(2, next.finished)
+
+	summary, ok := m.Summary("fast")
+	its.True(ok)
+	its.Equal(int64(2), summary.Count)
+	its.Zero(summary.SlowCount)
+	_, ok = m.Summary("missing")
+	its.False(ok)
+
+	// the monitor is not connected, so slow statements are reported without a plan.
+	m.Threshold = time.Nanosecond
+	m.Query(context.Background(), cfg, "slow", "SELECT pg_sleep(1)").FinishQuery(context.Background(), nil, nil)
+
+	select {
+	case e := <-events:
+		its.Equal("slow", e.Label)
+		its.Equal("SELECT pg_sleep(1)", e.Body)
+		its.Equal("test-database", e.Database)
+		its.Equal("test-user", e.Username)
+		its.Equal(time.Nanosecond, e.Threshold)
+		its.NotZero(e.Elapsed)
+		its.Empty(e.Plan)
+	case <-time.After(5 * time.Second):
+		its.FailNow("slow query event was not triggered")
+	}
+
+	summaries := m.Summaries()
+	its.Len(summaries, 2)
+	its.Equal("fast", summaries[0].Label)
+	its.Equal("slow", summaries[1].Label)
+	its.Equal(int64(1), summaries[1].SlowCount)
+
+	m.Reset()
+	its.Empty(m.Summaries())
+}
+
+func Test_Monitor_explainPool(t *testing.T) {
+	its := assert.New(t)
+
+	conn := &db.Connection{Connection: new(sql.DB)}
+	m := New(OptConn(conn), OptSampleRate(0.5))
+	m.random = func() float64 { return 0.25 }
+	its.NotNil(m.explainPool(db.Config{}, "SELECT 1"))
+	its.Nil(m.explainPool(db.Config{}, "CREATE TABLE t (a int)"))
+	its.Nil(m.explainPool(db.Config{Dialect: string(db.DialectSQLite)}, "SELECT 1"))
+
+	m.random = func() float64 { return 0.75 }
+	its.Nil(m.explainPool(db.Config{}, "SELECT 1"))
+
+	m.random = func() float64 { return 0.25 }
+	m.Conn = nil
+	its.Nil(m.explainPool(db.Config{}, "SELECT 1"))
+}
+
+func Test_Monitor_Endpoint(t *testing.T) {
+	its := assert.New(t)
+
+	m := New()
+	m.Query(context.Background(), db.Config{}, "label", "SELECT 1").FinishQuery(context.Background(), nil, nil)
+
+	app := web.MustNew()
+	app.GET("/latency", m.Endpoint())
+
+	var summaries []map[string]interface{}
+	meta, err := web.MockGet(app, "/latency").JSON(&summaries)
+	its.Nil(err)
+	its.Equal(http.StatusOK, meta.StatusCode)
+	its.Len(summaries, 1)
+	its.Equal("label", summaries[0]["label"])
+	its.Equal(float64(1), summaries[0]["count"])
+}
+
+type mockTracer struct {
+	queries  int
+	finished int
+}
+
+func (mt *mockTracer) Prepare(context.Context, db.Config, string) db.TraceFinisher { return nil }
+
+func (mt *mockTracer) Query(context.Context, db.Config, string, string) db.TraceFinisher {
+	mt.queries++
+	return mockTraceFinisher{tracer: mt}
+}
+
+type mockTraceFinisher struct {
+	tracer *mockTracer
+}
+
+func (mtf mockTraceFinisher) FinishPrepare(context.Context, error) {}
+
+func (mtf mockTraceFinisher) FinishQuery(context.Context, sql.Result, error) {
+	mtf.tracer.finished++
+}
@@ -0,0 +1,92 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/mathutil"
+	"github.com/zpkg/blend-go-sdk/timeutil"
+)
+
+// LatencySummary summarizes the elapsed times of the statements run with a label.
+//
+// The percentiles are computed from the most recent elapsed times, the counts cover every statement.
+type LatencySummary struct {
+	Label     string
+	Count     int64
+	SlowCount int64
+	P50       time.Duration
+	P99       time.Duration
+}
+
+// MarshalJSON implements json.Marshaler, where the elapsed times are given in milliseconds.
+func (ls LatencySummary) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"label":     ls.Label,
+		"count":     ls.Count,
+		"slowCount": ls.SlowCount,
+		"p50":       timeutil.Milliseconds(ls.P50),
+		"p99":       timeutil.Milliseconds(ls.P99),
+	})
+}
+
+// newLatencyWindow returns a new latency window that keeps the given number of elapsed times.
+func newLatencyWindow(size int) *latencyWindow {
+	return &latencyWindow{
+		elapsed: make([]time.Duration, 0, size),
+	}
+}
+
+// latencyWindow counts the statements run with a label and keeps their most recent elapsed times.
+//
+// It is not safe for concurrent use; the monitor guards it.
+type latencyWindow struct {
+	count     int64
+	slowCount int64
+	elapsed   []time.Duration
+	cursor    int
+}
+
+// add records an elapsed time, replacing the oldest one if the window is full.
+func (lw *latencyWindow) add(elapsed time.Duration, slow bool) {
+	lw.count++
+	if slow {
+		lw.slowCount++
+	}
+	if len(lw.elapsed) < cap(lw.elapsed) {
+		lw.elapsed = append(lw.elapsed, elapsed)
+		return
+	}
+	lw.elapsed[lw.cursor] = elapsed
+	lw.cursor = (lw.cursor + 1) % len(lw.elapsed)
+}
+
+// summary returns the latency summary of the window.
+func (lw *latencyWindow) summary(label string) LatencySummary {
+	sorted := mathutil.CopySortDurations(lw.elapsed)
+	return LatencySummary{
+		Label:     label,
+		Count:     lw.count,
+		SlowCount: lw.slowCount,
+		P50:       percentile(sorted, 50),
+		P99:       percentile(sorted, 99),
+	}
+}
+
+// percentile returns a percentile of sorted elapsed times.
+func percentile(sorted []time.Duration, percent float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	return mathutil.PercentileSortedDurations(sorted, percent)
+}
@@ -0,0 +1,56 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package slowquery
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+)
+
+func Test_latencyWindow(t *testing.T) {
+	its := assert.New(t)
+
+	window := newLatencyWindow(100)
+	its.Equal(LatencySummary{Label: "empty"}, window.summary("empty"))
+
+	window.add(time.Millisecond, false)
+	summary := window.summary("single")
+	its.Equal(int64(1), summary.Count)
+	its.Equal(time.Millisecond, summary.P50)
+	its.Equal(time.Millisecond, summary.P99)
+
+	window = newLatencyWindow(100)
+	for x := 1; x <= 200; x++ {
+		window.add(time.Duration(x)*time.Millisecond, x > 190)
+	}
+	summary = window.summary("label")
+	its.Equal("label", summary.Label)
+	its.Equal(int64(200), summary.Count)
+	its.Equal(int64(10), summary.SlowCount)
+	// only the most recent 100 elapsed times, i.e. 101ms through 200ms, are kept.
+	its.Len(window.elapsed, 100)
+	its.Equal(150500*time.Microsecond, summary.P50)
+	its.Equal(199500*time.Microsecond, summary.P99)
+}
+
+func Test_LatencySummary_MarshalJSON(t *testing.T) {
+	its := assert.New(t)
+
+	contents, err := json.Marshal(LatencySummary{
+		Label:     "label",
+		Count:     3,
+		SlowCount: 1,
+		P50:       1500 * time.Microsecond,
+		P99:       time.Second,
+	})
+	its.Nil(err)
+	its.Equal(`{"count":3,"label":"label","p50":1.5,"p99":1000,"slowCount":1}`, string(contents))
+}
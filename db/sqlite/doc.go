@@ -0,0 +1,21 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+/*
+Package sqlite registers an embedded, pure go sqlite driver for the `db.DialectSQLite` dialect.
+
+It lets db backed code run in process against a database file, without a postgres server, which is mostly
+useful for tests. Import it for its side effect, and create connections with `db.OptSQLite`:
+
+	import _ "github.com/zpkg/blend-go-sdk/db/sqlite"
+
+	conn, err := db.Open(db.New(db.OptSQLite("test.db")))
+
+Features specific to postgres, e.g. `COPY`, roles and `LISTEN` / `NOTIFY`, are not available; `CopyMany`
+falls back to inserts, and the others return an `ErrUnsupportedDialect`.
+*/
+package sqlite // import "github.com/zpkg/blend-go-sdk/db/sqlite"
@@ -0,0 +1,13 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package sqlite
+
+import (
+	// the sqlite driver is a pure go translation of sqlite, registered as `sqlite`
+	_ "modernc.org/sqlite"
+)
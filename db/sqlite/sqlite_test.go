@@ -0,0 +1,166 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
+)
+
+type widget struct {
+	ID        int        `db:"id,pk,auto"`
+	Name      string     `db:"name,uk"`
+	Amount    float64    `db:"amount"`
+	CreatedAt time.Time  `db:"created_at"`
+	Version   int        `db:"version,version"`
+	DeletedAt *time.Time `db:"deleted_at,deleted_at"`
+}
+
+func (widget) TableName() string { return "widget" }
+
+func openTestConnection(its *assert.Assertions) *db.Connection {
+	conn, err := db.Open(db.New(db.OptSQLite(filepath.Join(its.T.TempDir(), "test.db"))))
+	its.Nil(err)
+	its.Nil(db.IgnoreExecResult(conn.Exec(`CREATE TABLE widget (
+		id INTEGER PRIMARY KEY
+		, name TEXT NOT NULL UNIQUE
+		, amount REAL NOT NULL
+		, created_at TIMESTAMP NOT NULL
+		, version INTEGER NOT NULL DEFAULT 1
+		, deleted_at TIMESTAMP
+	)`)))
+	return conn
+}
+
+func Test_SQLite_CRUD(t *testing.T) {
+	its := assert.New(t)
+	conn := openTestConnection(its)
+	defer func() { its.Nil(conn.Close()) }()
+
+	w := widget{Name: "foo", Amount: 1.5, CreatedAt: time.Now().UTC().Truncate(time.Millisecond), Version: 1}
+	its.Nil(conn.Invoke().Create(&w))
+	its.NotZero(w.ID)
+
+	var verify widget
+	found, err := conn.Invoke().Get(&verify, w.ID)
+	its.Nil(err)
+	its.True(found)
+	its.Equal("foo", verify.Name)
+	its.Equal(w.CreatedAt, verify.CreatedAt.UTC())
+
+	exists, err := conn.Invoke().Exists(&w)
+	its.Nil(err)
+	its.True(exists)
+
+	w.Amount = 2.5
+	updated, err := conn.Invoke().Update(&w)
+	its.Nil(err)
+	its.True(updated)
+	its.Equal(2, w.Version)
+
+	stale := verify
+	_, err = conn.Invoke().Update(&stale)
+	its.True(db.IsVersionConflict(err))
+
+	w.Amount = 3.5
+	its.Nil(conn.Invoke().Upsert(&w))
+	its.Equal(3, w.Version)
+
+	its.Nil(conn.Invoke().CreateIfNotExists(&w))
+
+	its.Nil(conn.Invoke().CreateMany([]widget{
+		{Name: "bar", Amount: 1, CreatedAt: time.Now().UTC(), Version: 1},
+		{Name: "baz", Amount: 2, CreatedAt: time.Now().UTC(), Version: 1},
+	}))
+	its.Nil(conn.Invoke().UpsertMany([]widget{
+		{Name: "baz", Amount: 3, CreatedAt: time.Now().UTC(), Version: 1},
+	}))
+
+	var all []widget
+	its.Nil(conn.Invoke().All(&all))
+	its.Len(all, 3)
+
+	var selected []widget
+	its.Nil(conn.Invoke().QueryStatement(db.SelectFrom(widget{}).Where(db.Eq("name", "baz"), db.Gt("amount", 2))).OutMany(&selected))
+	its.Len(selected, 1)
+	its.Equal(3, selected[0].Amount)
+
+	deleted, err := conn.Invoke().Delete(&w)
+	its.Nil(err)
+	its.True(deleted)
+	its.NotNil(w.DeletedAt)
+
+	found, err = conn.Invoke().Get(&verify, w.ID)
+	its.Nil(err)
+	its.False(found)
+	found, err = conn.Invoke(db.OptIncludeDeleted()).Get(&verify, w.ID)
+	its.Nil(err)
+	its.True(found)
+}
+
+func Test_SQLite_Page(t *testing.T) {
+	its := assert.New(t)
+	conn := openTestConnection(its)
+	defer func() { its.Nil(conn.Close()) }()
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		its.Nil(conn.Invoke().Create(&widget{Name: name, CreatedAt: time.Now().UTC(), Version: 1}))
+	}
+
+	var names []string
+	var token string
+	for {
+		var page []widget
+		var err error
+		token, err = conn.Invoke().Page(&page, token, db.OptPageSize(2), db.OptPageOrderByDesc("name"))
+		its.Nil(err)
+		for _, w := range page {
+			names = append(names, w.Name)
+		}
+		if token == "" {
+			break
+		}
+	}
+	its.Equal([]string{"e", "d", "c", "b", "a"}, names)
+}
+
+func Test_SQLite_InTx(t *testing.T) {
+	its := assert.New(t)
+	conn := openTestConnection(its)
+	defer func() { its.Nil(conn.Close()) }()
+
+	err := conn.InTx(context.Background(), func(i *db.Invocation) error {
+		return i.Create(&widget{Name: "foo", CreatedAt: time.Now().UTC(), Version: 1})
+	})
+	its.Nil(err)
+
+	count, err := conn.Invoke().CopyMany([]widget{
+		{Name: "bar", CreatedAt: time.Now().UTC(), Version: 1},
+		{Name: "baz", CreatedAt: time.Now().UTC(), Version: 1},
+	})
+	its.Nil(err)
+	its.Equal(2, count)
+
+	var all []widget
+	its.Nil(conn.Invoke().All(&all))
+	its.Len(all, 3)
+}
+
+func Test_SQLite_Notify(t *testing.T) {
+	its := assert.New(t)
+	conn := openTestConnection(its)
+	defer func() { its.Nil(conn.Close()) }()
+
+	its.True(db.IsUnsupportedDialect(conn.Notify(context.Background(), "widgets", "foo")))
+	its.True(db.IsUnsupportedDialect(db.NewListener(conn).Start()))
+}
@@ -0,0 +1,201 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StatementBuilder is a type that builds a sql statement and its arguments for a given dialect.
+//
+// Statement builders are run with `Invocation.QueryStatement` or `Invocation.ExecStatement`,
+// so that labels, statement interceptors, loggers and tracers apply as with any other statement.
+type StatementBuilder interface {
+	// Label returns a default label for the statement, e.g. `users_select`.
+	Label() string
+	// Build returns the statement and its arguments.
+	Build(Dialect) (statement string, args []interface{})
+}
+
+// validatingStatementBuilder is a statement builder that can be in a state that does not build a valid statement.
+type validatingStatementBuilder interface {
+	validate() error
+}
+
+// returningStatementBuilder is a statement builder that can have a returning clause.
+type returningStatementBuilder interface {
+	hasReturning() bool
+}
+
+// statementWriter accumulates a statement and its arguments for a dialect.
+type statementWriter struct {
+	dialect Dialect
+	buffer  strings.Builder
+	args    []interface{}
+}
+
+func newStatementWriter(dialect Dialect) *statementWriter {
+	return &statementWriter{dialect: dialect}
+}
+
+// WriteString writes a raw sql fragment.
+func (sw *statementWriter) WriteString(sql string) {
+	sw.buffer.WriteString(sql)
+}
+
+// WriteIdentifier writes a table or column name, quoting it if required.
+//
+// Names that are not plain (optionally dotted) identifiers, e.g. `count(*)`
+// or `name AS display_name`, are written as is.
+func (sw *statementWriter) WriteIdentifier(name string) {
+	sw.buffer.WriteString(quoteIdentifierIfRequired(sw.dialect, name))
+}
+
+// WriteIdentifiers writes a comma separated list of identifiers.
+func (sw *statementWriter) WriteIdentifiers(names []string) {
+	for index, name := range names {
+		if index > 0 {
+			sw.buffer.WriteRune(',')
+		}
+		sw.WriteIdentifier(name)
+	}
+}
+
+// WriteArg adds an argument and writes its placeholder.
+func (sw *statementWriter) WriteArg(value interface{}) {
+	sw.args = append(sw.args, value)
+	sw.buffer.WriteString(sw.dialect.Placeholder(len(sw.args)))
+}
+
+// WriteRaw writes a raw sql fragment, replacing each `?` with the placeholder of
+// the corresponding argument; `??` is written as a literal `?`.
+func (sw *statementWriter) WriteRaw(sql string, args []interface{}) {
+	var argIndex int
+	for index := 0; index < len(sql); index++ {
+		if sql[index] != '?' {
+			sw.buffer.WriteByte(sql[index])
+			continue
+		}
+		if index+1 < len(sql) && sql[index+1] == '?' {
+			sw.buffer.WriteByte('?')
+			index++
+			continue
+		}
+		if argIndex < len(args) {
+			sw.WriteArg(args[argIndex])
+			argIndex++
+			continue
+		}
+		sw.buffer.WriteByte('?')
+	}
+}
+
+// WriteLimitOffset writes the limit and offset clauses if they are set.
+//
+// Sqlite and mysql do not allow an offset without a limit, so for those dialects an
+// offset without a limit is written with the largest limit the dialect allows.
+func (sw *statementWriter) WriteLimitOffset(limit, offset int) {
+	if limit > 0 {
+		sw.buffer.WriteString(" LIMIT ")
+		sw.buffer.WriteString(strconv.Itoa(limit))
+	} else if offset > 0 {
+		switch {
+		case sw.dialect.Is(DialectSQLite):
+			sw.buffer.WriteString(" LIMIT -1")
+		case sw.dialect.Is(DialectMySQL):
+			sw.buffer.WriteString(" LIMIT 18446744073709551615")
+		}
+	}
+	if offset > 0 {
+		sw.buffer.WriteString(" OFFSET ")
+		sw.buffer.WriteString(strconv.Itoa(offset))
+	}
+}
+
+// WriteWhere writes the where clause if there are predicates.
+func (sw *statementWriter) WriteWhere(predicates []Predicate) {
+	if len(predicates) == 0 {
+		return
+	}
+	sw.buffer.WriteString(" WHERE ")
+	And(predicates...).writePredicate(sw)
+}
+
+// WriteReturning writes the returning clause if there are columns.
+//
+// Mysql does not support returning clauses, so none is written for the mysql dialect.
+func (sw *statementWriter) WriteReturning(columns []string) {
+	if len(columns) == 0 || sw.dialect.Is(DialectMySQL) {
+		return
+	}
+	sw.buffer.WriteString(" RETURNING ")
+	sw.WriteIdentifiers(columns)
+}
+
+// Result returns the statement and its arguments.
+func (sw *statementWriter) Result() (string, []interface{}) {
+	return sw.buffer.String(), sw.args
+}
+
+// quoteIdentifierIfRequired quotes each part of a (dotted) identifier that is a reserved word.
+//
+// Other plain identifiers are left unquoted so that they fold to lower case as they do
+// in the statements generated for `DatabaseMapped` types; names that are not identifiers are returned as is.
+func quoteIdentifierIfRequired(dialect Dialect, name string) string {
+	parts := strings.Split(name, ".")
+	for index, part := range parts {
+		if part == "*" && index == len(parts)-1 && index > 0 {
+			continue
+		}
+		if !isPlainIdentifier(part) {
+			return name
+		}
+	}
+	for index, part := range parts {
+		if _, reserved := reservedWords[strings.ToLower(part)]; reserved {
+			parts[index] = dialect.QuoteIdentifier(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// isPlainIdentifier returns if a value is a letter or underscore followed by letters, digits or underscores.
+func isPlainIdentifier(value string) bool {
+	if value == "" {
+		return false
+	}
+	for index, r := range value {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case index > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// reservedWords are the keywords that must be quoted to be used as identifiers
+// in at least one of the supported dialects.
+var reservedWords = map[string]struct{}{
+	"all": {}, "analyse": {}, "analyze": {}, "and": {}, "any": {}, "array": {}, "as": {}, "asc": {},
+	"asymmetric": {}, "between": {}, "both": {}, "by": {}, "case": {}, "cast": {}, "check": {}, "collate": {},
+	"column": {}, "constraint": {}, "create": {}, "cross": {}, "current_catalog": {}, "current_date": {},
+	"current_role": {}, "current_time": {}, "current_timestamp": {}, "current_user": {}, "default": {},
+	"deferrable": {}, "delete": {}, "desc": {}, "distinct": {}, "do": {}, "else": {}, "end": {}, "except": {},
+	"exists": {}, "false": {}, "fetch": {}, "for": {}, "foreign": {}, "from": {}, "full": {}, "grant": {},
+	"group": {}, "having": {}, "in": {}, "index": {}, "initially": {}, "inner": {}, "insert": {}, "intersect": {},
+	"interval": {}, "into": {}, "is": {}, "join": {}, "key": {}, "lateral": {}, "leading": {}, "left": {},
+	"like": {}, "limit": {}, "localtime": {}, "localtimestamp": {}, "natural": {}, "not": {}, "null": {},
+	"offset": {}, "on": {}, "only": {}, "or": {}, "order": {}, "outer": {}, "placing": {}, "primary": {},
+	"range": {}, "references": {}, "returning": {}, "right": {}, "row": {}, "rows": {}, "select": {},
+	"session_user": {}, "set": {}, "some": {}, "symmetric": {}, "table": {}, "then": {}, "to": {},
+	"trailing": {}, "true": {}, "union": {}, "unique": {}, "update": {}, "user": {}, "using": {},
+	"values": {}, "variadic": {}, "when": {}, "where": {}, "window": {}, "with": {},
+}
@@ -0,0 +1,151 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+)
+
+func Test_SelectBuilder(t *testing.T) {
+	its := assert.New(t)
+
+	statement, args := Select("id", "name", "order").
+		From("users").
+		Where(Eq("name", "foo"), Gt("age", 21), In("status", []string{"active", "pending"})).
+		OrderByDesc("created_utc").
+		OrderBy("id").
+		Limit(10).
+		Offset(20).
+		Build(DialectPostgres)
+	its.Equal(`SELECT id,name,"order" FROM users WHERE (name = $1) AND (age > $2) AND (status IN ($3,$4)) ORDER BY created_utc DESC,id ASC LIMIT 10 OFFSET 20`, statement)
+	its.Equal([]interface{}{"foo", 21, "active", "pending"}, args)
+}
+
+func Test_SelectBuilder_offsetWithoutLimit(t *testing.T) {
+	its := assert.New(t)
+
+	sb := Select("id").From("users").Offset(20)
+	statement, _ := sb.Build(DialectPostgres)
+	its.Equal(`SELECT id FROM users OFFSET 20`, statement)
+	statement, _ = sb.Build(DialectSQLite)
+	its.Equal(`SELECT id FROM users LIMIT -1 OFFSET 20`, statement)
+	statement, _ = sb.Build(DialectMySQL)
+	its.Equal(`SELECT id FROM users LIMIT 18446744073709551615 OFFSET 20`, statement)
+}
+
+func Test_SelectBuilder_joins(t *testing.T) {
+	its := assert.New(t)
+
+	sb := SelectFrom(benchObj{}).As("b").
+		LeftJoin("user", "u", Raw("u.id = b.id"), Eq("u.deleted", false)).
+		Where(Or(IsNull("b.category"), Like("b.category", "cat%")))
+	statement, args := sb.Build(DialectPostgres)
+	its.Equal(`SELECT b.id,b.uuid,b.name,b.timestamp_utc,b.amount,b.pending,b.category FROM bench_object b LEFT JOIN "user" u ON (u.id = b.id) AND (u.deleted = $1) WHERE (b.category IS NULL) OR (b.category LIKE $2)`, statement)
+	its.Equal([]interface{}{false, "cat%"}, args)
+	its.Equal("bench_object_select", sb.Label())
+	its.Equal("custom", sb.WithLabel("custom").Label())
+}
+
+func Test_SelectBuilder_expressions(t *testing.T) {
+	its := assert.New(t)
+
+	statement, args := Select("count(*)", "category").
+		From("bench_object").
+		Where(NotIn("id", []int{}), Raw("amount > ? AND name ?? 'x'", 10)).
+		GroupBy("category").
+		Build(DialectPostgres)
+	its.Equal(`SELECT count(*),category FROM bench_object WHERE (1=1) AND (amount > $1 AND name ? 'x') GROUP BY category`, statement)
+	its.Equal([]interface{}{10}, args)
+}
+
+func Test_InsertBuilder(t *testing.T) {
+	its := assert.New(t)
+
+	ib := InsertInto("users").Columns("name", "group").Values("foo", 1).Values("bar", 2).Returning("id")
+	statement, args := ib.Build(DialectPostgres)
+	its.Equal(`INSERT INTO users (name,"group") VALUES ($1,$2),($3,$4) RETURNING id`, statement)
+	its.Equal([]interface{}{"foo", 1, "bar", 2}, args)
+	its.Equal("users_insert", ib.Label())
+}
+
+func Test_InsertObjects(t *testing.T) {
+	its := assert.New(t)
+
+	statement, args := InsertObjects(&benchObj{Name: "one"}, &benchObj{Name: "two"}).Returning("id").Build(DialectPostgres)
+	its.Equal(`INSERT INTO bench_object (uuid,name,timestamp_utc,amount,pending,category) VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id`, statement)
+	its.Len(args, 12)
+	its.Equal("one", args[1])
+	its.Equal("two", args[7])
+}
+
+func Test_InsertBuilder_invalid(t *testing.T) {
+	its := assert.New(t)
+
+	invocation := &Invocation{DB: new(sql.DB)}
+	_, err := invocation.ExecStatement(InsertObjects())
+	its.True(IsInvalidStatement(err))
+	_, err = invocation.ExecStatement(InsertInto("users").Values("foo"))
+	its.True(IsInvalidStatement(err))
+	_, err = invocation.ExecStatement(InsertInto("users").Columns("name"))
+	its.True(IsInvalidStatement(err))
+	_, err = invocation.ExecStatement(InsertInto("users").Columns("name", "group").Values("foo", 1).Values("bar"))
+	its.True(IsInvalidStatement(err))
+	its.Contains(ex.ErrMessage(err), "row 1 has 1 values for 2 columns")
+
+	_, err = invocation.ExecStatement(InsertObjects(&benchObj{Name: "one"}, &versionedObj{ID: 1}))
+	its.True(IsInvalidStatement(err))
+	its.Contains(ex.ErrMessage(err), "object 1 is a db.versionedObj, not a db.benchObj")
+
+	invocation = &Invocation{DB: new(sql.DB)}
+	its.True(IsInvalidStatement(invocation.QueryStatement(InsertObjects().Returning("id")).Err))
+}
+
+func Test_UpdateBuilder(t *testing.T) {
+	its := assert.New(t)
+
+	ub := Update("users").Set("name", "foo").Set("user", nil).Where(Eq("id", 1), NotEq("deleted_utc", nil))
+	statement, args := ub.Build(DialectPostgres)
+	its.Equal(`UPDATE users SET name=$1,"user"=$2 WHERE (id = $3) AND (deleted_utc IS NOT NULL)`, statement)
+	its.Equal([]interface{}{"foo", nil, 1}, args)
+	its.Equal("users_update", ub.Label())
+}
+
+func Test_DeleteBuilder(t *testing.T) {
+	its := assert.New(t)
+
+	db := DeleteFrom("users").Where(Not(Lte("age", 18))).Returning("id", "name")
+	statement, args := db.Build(DialectPostgres)
+	its.Equal(`DELETE FROM users WHERE NOT (age <= $1) RETURNING id,name`, statement)
+	its.Equal([]interface{}{18}, args)
+	its.Equal("users_delete", db.Label())
+
+	statement, args = DeleteFrom("users").Build(DialectPostgres)
+	its.Equal(`DELETE FROM users`, statement)
+	its.Empty(args)
+}
+
+func Test_StatementBuilder_returningMySQL(t *testing.T) {
+	its := assert.New(t)
+
+	statement, args := InsertInto("users").Columns("name").Values("foo").Returning("id").Build(DialectMySQL)
+	its.Equal("INSERT INTO users (name) VALUES (?)", statement)
+	its.Equal([]interface{}{"foo"}, args)
+	statement, _ = Update("users").Set("name", "foo").Returning("id").Build(DialectMySQL)
+	its.Equal("UPDATE users SET name=?", statement)
+	statement, _ = DeleteFrom("users").Returning("id").Build(DialectMySQL)
+	its.Equal("DELETE FROM users", statement)
+
+	invocation := &Invocation{DB: new(sql.DB), Config: Config{Dialect: string(DialectMySQL)}}
+	its.True(ex.Is(invocation.QueryStatement(DeleteFrom("users").Returning("id")).Err, ErrUnsupportedDialect))
+	invocation = &Invocation{DB: new(sql.DB), Config: Config{Dialect: string(DialectMySQL)}}
+	its.Nil(invocation.QueryStatement(Select("id").From("users")).Err)
+}
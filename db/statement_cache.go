@@ -0,0 +1,342 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+	"github.com/zpkg/blend-go-sdk/stats"
+)
+
+// Statement cache metric and tag names.
+const (
+	MetricNameStatementCacheHit  = "db.statement_cache.hit"
+	MetricNameStatementCacheMiss = "db.statement_cache.miss"
+
+	TagStatementCacheQuery    = "query"
+	TagStatementCacheDatabase = "database"
+)
+
+// SQLStates of errors that mean a prepared statement is no longer valid.
+const (
+	SQLStateInvalidSQLStatementName = "26000"
+	SQLStateFeatureNotSupported     = "0A000"
+)
+
+// NewStatementCache returns a new statement cache.
+func NewStatementCache(options ...StatementCacheOption) *StatementCache {
+	sc := StatementCache{
+		statements: make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	for _, opt := range options {
+		opt(&sc)
+	}
+	return &sc
+}
+
+// StatementCacheOption mutates a statement cache.
+type StatementCacheOption func(*StatementCache)
+
+// OptStatementCacheSize sets the number of prepared statements the cache keeps.
+func OptStatementCacheSize(size int) StatementCacheOption {
+	return func(sc *StatementCache) { sc.Size = size }
+}
+
+// OptStatementCacheCollector sets the stats collector cache hits and misses are counted with.
+func OptStatementCacheCollector(collector stats.Collector) StatementCacheOption {
+	return func(sc *StatementCache) { sc.Collector = collector }
+}
+
+// StatementCache is a least recently used cache of prepared statements, keyed by statement label and text.
+//
+// A connection with a statement cache runs labeled statements that are not part of a transaction with
+// a cached prepared statement, so hot statements are parsed and planned once per driver connection.
+// The cache is cleared when the connection is closed, when a statement fails with a connection error,
+// and when a statement fails because the schema changed under it, in which case the statement is
+// prepared again and retried once.
+//
+// A statement cache belongs to a single connection.
+type StatementCache struct {
+	Size      int
+	Collector stats.Collector
+
+	conn       *Connection
+	mu         sync.Mutex
+	statements map[string]*list.Element
+	// lru is ordered from the most to the least recently used statement.
+	lru *list.List
+}
+
+// cachedStatement is a prepared statement in the cache.
+//
+// The statement is closed once it has been evicted and is no longer in use.
+type cachedStatement struct {
+	key     string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// SizeOrDefault returns the cache size or a default.
+func (sc *StatementCache) SizeOrDefault() int {
+	if sc.Size > 0 {
+		return sc.Size
+	}
+	return DefaultStatementCacheSize
+}
+
+// Len returns the number of prepared statements in the cache.
+func (sc *StatementCache) Len() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.lru.Len()
+}
+
+// Has returns if the cache has a prepared statement for a given label and statement.
+func (sc *StatementCache) Has(label, statement string) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	_, ok := sc.statements[statementCacheKey(label, statement)]
+	return ok
+}
+
+// Invalidate removes the prepared statement for a given label and statement from the cache.
+func (sc *StatementCache) Invalidate(label, statement string) error {
+	sc.mu.Lock()
+	var stmt *sql.Stmt
+	if element, ok := sc.statements[statementCacheKey(label, statement)]; ok {
+		stmt = sc.removeLocked(element)
+	}
+	sc.mu.Unlock()
+	return closeStatements(stmt)
+}
+
+// Clear removes every prepared statement from the cache.
+func (sc *StatementCache) Clear() error {
+	sc.mu.Lock()
+	var stmts []*sql.Stmt
+	for sc.lru.Len() > 0 {
+		stmts = append(stmts, sc.removeLocked(sc.lru.Back()))
+	}
+	sc.mu.Unlock()
+	return closeStatements(stmts...)
+}
+
+//
+// Private / Internal
+//
+
+// serves returns if the cache runs the statements of a given db, i.e. it is the connection pool of the cache.
+func (sc *StatementCache) serves(db DB) bool {
+	if sc.conn == nil || sc.conn.Connection == nil {
+		return false
+	}
+	pool, ok := db.(*sql.DB)
+	return ok && pool == sc.conn.Connection
+}
+
+// run calls an action with the cached prepared statement for a label and statement.
+func (sc *StatementCache) run(ctx context.Context, label, statement string, action func(*sql.Stmt) error) error {
+	err := sc.runOnce(ctx, label, statement, action)
+	if isStatementInvalidatedError(err) {
+		if clearErr := sc.Clear(); clearErr != nil {
+			return ex.Nest(err, clearErr)
+		}
+		return sc.runOnce(ctx, label, statement, action)
+	}
+	if isConnectionError(err) {
+		if clearErr := sc.Clear(); clearErr != nil {
+			return ex.Nest(err, clearErr)
+		}
+	}
+	return err
+}
+
+func (sc *StatementCache) runOnce(ctx context.Context, label, statement string, action func(*sql.Stmt) error) (err error) {
+	cs, err := sc.acquire(ctx, label, statement)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := sc.release(cs); releaseErr != nil {
+			err = ex.Nest(err, releaseErr)
+		}
+	}()
+	return action(cs.stmt)
+}
+
+// acquire returns the prepared statement for a label and statement, preparing it on a cache miss.
+//
+// The statement must be released once it has been run.
+func (sc *StatementCache) acquire(ctx context.Context, label, statement string) (*cachedStatement, error) {
+	if label == "" {
+		return nil, ex.New(ErrPlanCacheKeyUnset)
+	}
+	if sc.conn == nil || sc.conn.Connection == nil {
+		return nil, ex.New(ErrConnectionClosed)
+	}
+	key := statementCacheKey(label, statement)
+
+	sc.mu.Lock()
+	if element, ok := sc.statements[key]; ok {
+		cs := sc.useLocked(element)
+		sc.mu.Unlock()
+		sc.count(MetricNameStatementCacheHit, label)
+		return cs, nil
+	}
+	sc.mu.Unlock()
+
+	sc.count(MetricNameStatementCacheMiss, label)
+	stmt, err := sc.conn.PrepareContext(ctx, statement, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	// the statement may have been prepared concurrently.
+	if element, ok := sc.statements[key]; ok {
+		cs := sc.useLocked(element)
+		sc.mu.Unlock()
+		return cs, closeStatements(stmt)
+	}
+	cs := &cachedStatement{key: key, stmt: stmt, refs: 1}
+	sc.statements[key] = sc.lru.PushFront(cs)
+	var evicted []*sql.Stmt
+	for sc.lru.Len() > sc.SizeOrDefault() {
+		evicted = append(evicted, sc.removeLocked(sc.lru.Back()))
+	}
+	sc.mu.Unlock()
+	return cs, closeStatements(evicted...)
+}
+
+// release marks a prepared statement as no longer in use, closing it if it was evicted.
+func (sc *StatementCache) release(cs *cachedStatement) error {
+	sc.mu.Lock()
+	cs.refs--
+	closeStmt := cs.evicted && cs.refs == 0
+	sc.mu.Unlock()
+	if closeStmt {
+		return closeStatements(cs.stmt)
+	}
+	return nil
+}
+
+// useLocked marks a cached statement as the most recently used and in use.
+func (sc *StatementCache) useLocked(element *list.Element) *cachedStatement {
+	sc.lru.MoveToFront(element)
+	cs := element.Value.(*cachedStatement)
+	cs.refs++
+	return cs
+}
+
+// removeLocked evicts a cached statement, returning its prepared statement if it should be closed,
+// i.e. it is not in use.
+func (sc *StatementCache) removeLocked(element *list.Element) *sql.Stmt {
+	cs := sc.lru.Remove(element).(*cachedStatement)
+	delete(sc.statements, cs.key)
+	cs.evicted = true
+	if cs.refs > 0 {
+		return nil
+	}
+	return cs.stmt
+}
+
+// count increments a cache metric, if the cache has a stats collector.
+func (sc *StatementCache) count(metricName, label string) {
+	if sc.Collector == nil {
+		return
+	}
+	_ = sc.Collector.Increment(metricName,
+		stats.Tag(TagStatementCacheDatabase, sc.conn.Config.DatabaseOrDefault()),
+		stats.Tag(TagStatementCacheQuery, label),
+	)
+}
+
+func statementCacheKey(label, statement string) string {
+	return label + "\x00" + statement
+}
+
+func closeStatements(stmts ...*sql.Stmt) (err error) {
+	for _, stmt := range stmts {
+		if stmt != nil {
+			err = ex.Nest(err, stmt.Close())
+		}
+	}
+	if err != nil {
+		return Error(err)
+	}
+	return nil
+}
+
+// isStatementInvalidatedError returns if an error means a prepared statement is no longer valid, i.e.
+// the schema changed under the statement, or the server no longer has the statement after a session reset.
+func isStatementInvalidatedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case SQLStateInvalidSQLStatementName:
+			return true
+		case SQLStateFeatureNotSupported:
+			return strings.Contains(pgErr.Message, "cached plan must not change result type")
+		}
+		return false
+	}
+	// mysql error 1615, ER_NEED_REPREPARE.
+	return strings.Contains(err.Error(), "needs to be re-prepared")
+}
+
+// cachedStatementDB is a DB that runs statements with the prepared statements of a statement cache.
+type cachedStatementDB struct {
+	cache *StatementCache
+	label string
+}
+
+// ExecContext implements DB.
+func (csdb cachedStatementDB) ExecContext(ctx context.Context, statement string, args ...interface{}) (res sql.Result, err error) {
+	err = csdb.cache.run(ctx, csdb.label, statement, func(stmt *sql.Stmt) (runErr error) {
+		res, runErr = stmt.ExecContext(ctx, args...)
+		return
+	})
+	return
+}
+
+// QueryContext implements DB.
+func (csdb cachedStatementDB) QueryContext(ctx context.Context, statement string, args ...interface{}) (rows *sql.Rows, err error) {
+	err = csdb.cache.run(ctx, csdb.label, statement, func(stmt *sql.Stmt) (runErr error) {
+		rows, runErr = stmt.QueryContext(ctx, args...)
+		return
+	})
+	return
+}
+
+// QueryRowContext implements DB.
+//
+// If the statement cannot be run with a prepared statement, the statement is run without one so that
+// the error is returned by `Scan`.
+func (csdb cachedStatementDB) QueryRowContext(ctx context.Context, statement string, args ...interface{}) (row *sql.Row) {
+	err := csdb.cache.run(ctx, csdb.label, statement, func(stmt *sql.Stmt) error {
+		row = stmt.QueryRowContext(ctx, args...)
+		return row.Err()
+	})
+	if err != nil && row == nil {
+		return csdb.cache.conn.Connection.QueryRowContext(ctx, statement, args...)
+	}
+	return row
+}
@@ -0,0 +1,107 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	_ "modernc.org/sqlite"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/stats"
+)
+
+func Test_StatementCache(t *testing.T) {
+	its := assert.New(t)
+
+	collector := stats.NewMockCollector(32)
+	conn, err := Open(New(
+		OptSQLite(filepath.Join(its.T.TempDir(), "test.db")),
+		OptStatementCache(OptStatementCacheSize(2), OptStatementCacheCollector(collector)),
+	))
+	its.Nil(err)
+	defer func() { _ = conn.Close() }()
+
+	// unlabeled statements are not cached.
+	its.Nil(IgnoreExecResult(conn.Exec("CREATE TABLE statement_cache_test (id INTEGER PRIMARY KEY, name TEXT)")))
+	its.Zero(conn.StatementCache.Len())
+
+	insert := "INSERT INTO statement_cache_test (id, name) VALUES (?1, ?2)"
+	its.Nil(IgnoreExecResult(conn.Invoke(OptLabel("insert")).Exec(insert, 1, "one")))
+	its.Nil(IgnoreExecResult(conn.Invoke(OptLabel("insert")).Exec(insert, 2, "two")))
+	its.Equal(1, conn.StatementCache.Len())
+	its.True(conn.StatementCache.Has("insert", insert))
+	its.Equal(1, collector.GetCount(MetricNameStatementCacheMiss))
+	its.Equal(1, collector.GetCount(MetricNameStatementCacheHit))
+
+	var name string
+	found, err := conn.Invoke(OptLabel("get")).Query("SELECT name FROM statement_cache_test WHERE id = ?1", 2).Scan(&name)
+	its.Nil(err)
+	its.True(found)
+	its.Equal("two", name)
+	its.Equal(2, conn.StatementCache.Len())
+
+	// the least recently used statement is evicted.
+	var count int
+	_, err = conn.Invoke(OptLabel("count")).Query("SELECT count(*) FROM statement_cache_test").Scan(&count)
+	its.Nil(err)
+	its.Equal(2, count)
+	its.Equal(2, conn.StatementCache.Len())
+	its.False(conn.StatementCache.Has("insert", insert))
+
+	// statements in a transaction are not cached.
+	tx, err := conn.Begin()
+	its.Nil(err)
+	its.Nil(IgnoreExecResult(conn.Invoke(OptTx(tx), OptLabel("insert")).Exec(insert, 3, "three")))
+	its.Nil(tx.Commit())
+	its.False(conn.StatementCache.Has("insert", insert))
+
+	its.Nil(conn.StatementCache.Invalidate("count", "SELECT count(*) FROM statement_cache_test"))
+	its.Equal(1, conn.StatementCache.Len())
+	its.Nil(conn.StatementCache.Clear())
+	its.Zero(conn.StatementCache.Len())
+}
+
+func Test_StatementCache_inUse(t *testing.T) {
+	its := assert.New(t)
+
+	conn, err := Open(New(
+		OptSQLite(filepath.Join(its.T.TempDir(), "test.db")),
+		OptStatementCache(),
+	))
+	its.Nil(err)
+	defer func() { _ = conn.Close() }()
+
+	cs, err := conn.StatementCache.acquire(context.Background(), "select", "SELECT 1")
+	its.Nil(err)
+	its.Nil(conn.StatementCache.Clear())
+
+	// an evicted statement is closed once it is no longer in use.
+	its.Nil(IgnoreExecResult(cs.stmt.Exec()))
+	its.Nil(conn.StatementCache.release(cs))
+	its.NotNil(IgnoreExecResult(cs.stmt.Exec()))
+
+	_, err = conn.StatementCache.acquire(context.Background(), "", "SELECT 1")
+	its.True(IsPlanCacheKeyUnset(err))
+}
+
+func Test_isStatementInvalidatedError(t *testing.T) {
+	its := assert.New(t)
+
+	its.False(isStatementInvalidatedError(nil))
+	its.False(isStatementInvalidatedError(fmt.Errorf("this is only a test")))
+	its.True(isStatementInvalidatedError(&pgconn.PgError{Code: SQLStateInvalidSQLStatementName}))
+	its.True(isStatementInvalidatedError(Error(&pgconn.PgError{Code: SQLStateFeatureNotSupported, Message: "cached plan must not change result type"})))
+	its.False(isStatementInvalidatedError(&pgconn.PgError{Code: SQLStateFeatureNotSupported}))
+	its.False(isStatementInvalidatedError(&pgconn.PgError{Code: SQLStateSerializationFailure}))
+	its.True(isStatementInvalidatedError(fmt.Errorf("Error 1615: Prepared statement needs to be re-prepared")))
+}
@@ -24,3 +24,14 @@ type TraceFinisher interface {
 	FinishPrepare(context.Context, error)
 	FinishQuery(context.Context, sql.Result, error)
 }
+
+// NotificationTracer is a tracer that can trace the delivery of notifications by a `Listener`.
+// It is optional; a `Tracer` that also implements it will be used to trace notifications.
+type NotificationTracer interface {
+	Notification(context.Context, Config, Notification) NotificationTraceFinisher
+}
+
+// NotificationTraceFinisher is a type that can finish notification traces.
+type NotificationTraceFinisher interface {
+	FinishNotification(context.Context, error)
+}
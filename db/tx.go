@@ -0,0 +1,252 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/zpkg/blend-go-sdk/ex"
+	"github.com/zpkg/blend-go-sdk/retry"
+)
+
+// SQLSTATE codes for errors that are resolved by retrying a transaction.
+const (
+	SQLStateSerializationFailure = "40001"
+	SQLStateDeadlockDetected     = "40P01"
+)
+
+// cockroachRestartSavepoint is the savepoint used by the cockroachdb client side retry protocol.
+const cockroachRestartSavepoint = "cockroach_restart"
+
+// TxOption mutates transaction options.
+type TxOption func(*TxOptions)
+
+// OptTxIsolation sets the transaction isolation level.
+func OptTxIsolation(isolation sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) { o.Isolation = isolation }
+}
+
+// OptTxReadOnly sets if the transaction is read only.
+func OptTxReadOnly(readOnly bool) TxOption {
+	return func(o *TxOptions) { o.ReadOnly = readOnly }
+}
+
+// OptTxMaxAttempts sets the maximum number of attempts to run the transaction.
+func OptTxMaxAttempts(maxAttempts int) TxOption {
+	return func(o *TxOptions) { o.MaxAttempts = maxAttempts }
+}
+
+// OptTxDelayProvider sets the delay provider between transaction attempts.
+func OptTxDelayProvider(delayProvider retry.DelayProvider) TxOption {
+	return func(o *TxOptions) { o.DelayProvider = delayProvider }
+}
+
+// OptTxLabel sets the label of the query events triggered for each attempt.
+func OptTxLabel(label string) TxOption {
+	return func(o *TxOptions) { o.Label = label }
+}
+
+// OptTxInvocationOptions sets options applied to the invocation passed to the transaction action.
+func OptTxInvocationOptions(opts ...InvocationOption) TxOption {
+	return func(o *TxOptions) { o.InvocationOptions = append(o.InvocationOptions, opts...) }
+}
+
+// TxOptions are options for `Connection.InTx`.
+type TxOptions struct {
+	Isolation         sql.IsolationLevel
+	ReadOnly          bool
+	MaxAttempts       int
+	DelayProvider     retry.DelayProvider
+	Label             string
+	InvocationOptions []InvocationOption
+}
+
+// MaxAttemptsOrDefault returns the max attempts or a default.
+func (o TxOptions) MaxAttemptsOrDefault() int {
+	if o.MaxAttempts > 0 {
+		return o.MaxAttempts
+	}
+	return DefaultTxMaxAttempts
+}
+
+// DelayProviderOrDefault returns the delay provider or a default exponential backoff.
+func (o TxOptions) DelayProviderOrDefault() retry.DelayProvider {
+	if o.DelayProvider != nil {
+		return o.DelayProvider
+	}
+	return retry.ExponentialBackoff(DefaultTxRetryDelay)
+}
+
+// LabelOrDefault returns the label or a default.
+func (o TxOptions) LabelOrDefault() string {
+	if o.Label != "" {
+		return o.Label
+	}
+	return DefaultTxLabel
+}
+
+// IsRetryableTxError returns if an error is a serialization failure or a deadlock,
+// i.e. the transaction can be retried from the beginning.
+func IsRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == SQLStateSerializationFailure || pgErr.Code == SQLStateDeadlockDetected
+	}
+	return false
+}
+
+// InTx runs an action in a transaction, committing if it returns nil, and rolling back if it
+// returns an error or panics.
+//
+// If the action or the commit fails with a serialization failure or deadlock, the transaction is
+// retried with a backoff, up to the max attempts. For the cockroachdb dialect, retries use the
+// client side retry protocol, i.e. a `cockroach_restart` savepoint within a single transaction.
+//
+// The action may be called more than once, so it should not have side effects outside the transaction.
+// A query event, with the attempt number, is triggered for the commit or rollback of each attempt.
+func (dbc *Connection) InTx(ctx context.Context, action func(*Invocation) error, opts ...TxOption) error {
+	if dbc.Connection == nil {
+		return ex.New(ErrConnectionClosed)
+	}
+	var options TxOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if dbc.Config.DialectOrDefault().Is(DialectCockroachDB) {
+		return dbc.inTxCockroachDB(ctx, action, options)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = dbc.inTxAttempt(ctx, action, options, attempt)
+		if err == nil || !IsRetryableTxError(err) || attempt >= options.MaxAttemptsOrDefault() {
+			return err
+		}
+		if err = dbc.inTxWait(ctx, options, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// inTxAttempt runs a single attempt of a transaction.
+func (dbc *Connection) inTxAttempt(ctx context.Context, action func(*Invocation) error, options TxOptions, attempt int) (err error) {
+	started := time.Now()
+	var tx *sql.Tx
+	if tx, err = dbc.Connection.BeginTx(ctx, &sql.TxOptions{Isolation: options.Isolation, ReadOnly: options.ReadOnly}); err != nil {
+		err = Error(err)
+		return
+	}
+	if err = dbc.inTxAction(ctx, tx, action, options); err != nil {
+		err = ex.Nest(err, dbc.inTxRollback(ctx, tx, options, attempt, started, err))
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		err = Error(err)
+	}
+	dbc.inTxTrigger(ctx, options, attempt, "COMMIT", started, err)
+	return
+}
+
+// inTxCockroachDB runs a transaction with the cockroachdb client side retry protocol.
+//
+// See: https://www.cockroachlabs.com/docs/stable/advanced-client-side-transaction-retries.html
+func (dbc *Connection) inTxCockroachDB(ctx context.Context, action func(*Invocation) error, options TxOptions) (err error) {
+	started := time.Now()
+	var tx *sql.Tx
+	if tx, err = dbc.Connection.BeginTx(ctx, &sql.TxOptions{Isolation: options.Isolation, ReadOnly: options.ReadOnly}); err != nil {
+		err = Error(err)
+		return
+	}
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+cockroachRestartSavepoint); err != nil {
+		err = ex.Nest(Error(err), dbc.inTxRollback(ctx, tx, options, 1, started, err))
+		return
+	}
+	for attempt := 1; ; attempt++ {
+		if err = dbc.inTxAction(ctx, tx, action, options); err == nil {
+			if _, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+cockroachRestartSavepoint); err != nil {
+				err = Error(err)
+			}
+		}
+		if err == nil {
+			if err = tx.Commit(); err != nil {
+				err = Error(err)
+			}
+			dbc.inTxTrigger(ctx, options, attempt, "COMMIT", started, err)
+			return
+		}
+		if !IsRetryableTxError(err) || attempt >= options.MaxAttemptsOrDefault() {
+			err = ex.Nest(err, dbc.inTxRollback(ctx, tx, options, attempt, started, err))
+			return
+		}
+		if _, restartErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+cockroachRestartSavepoint); restartErr != nil {
+			err = ex.Nest(err, Error(restartErr), dbc.inTxRollback(ctx, tx, options, attempt, started, err))
+			return
+		}
+		dbc.inTxTrigger(ctx, options, attempt, "ROLLBACK TO SAVEPOINT "+cockroachRestartSavepoint, started, err)
+		if waitErr := dbc.inTxWait(ctx, options, attempt); waitErr != nil {
+			err = ex.Nest(waitErr, dbc.inTxRollback(ctx, tx, options, attempt, started, waitErr))
+			return
+		}
+		started = time.Now()
+	}
+}
+
+// inTxAction calls the transaction action with an invocation bound to the transaction,
+// returning a panic as an error.
+func (dbc *Connection) inTxAction(ctx context.Context, tx *sql.Tx, action func(*Invocation) error, options TxOptions) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+	invocationOptions := append([]InvocationOption{OptContext(ctx), OptTx(tx)}, options.InvocationOptions...)
+	err = action(dbc.Invoke(invocationOptions...))
+	return
+}
+
+// inTxRollback rolls back a transaction that failed with a given error.
+func (dbc *Connection) inTxRollback(ctx context.Context, tx *sql.Tx, options TxOptions, attempt int, started time.Time, cause error) error {
+	rollbackErr := Error(tx.Rollback())
+	dbc.inTxTrigger(ctx, options, attempt, "ROLLBACK", started, ex.Nest(cause, rollbackErr))
+	return rollbackErr
+}
+
+// inTxWait waits for the retry delay of an attempt, or for the context to be done.
+func (dbc *Connection) inTxWait(ctx context.Context, options TxOptions, attempt int) error {
+	alarm := time.NewTimer(options.DelayProviderOrDefault()(ctx, uint(attempt-1)))
+	defer alarm.Stop()
+	select {
+	case <-ctx.Done():
+		return Error(ctx.Err())
+	case <-alarm.C:
+		return nil
+	}
+}
+
+// inTxTrigger triggers a query event for the outcome of a transaction attempt.
+func (dbc *Connection) inTxTrigger(ctx context.Context, options TxOptions, attempt int, outcome string, started time.Time, err error) {
+	if dbc.Log == nil || IsSkipQueryLogging(ctx) {
+		return
+	}
+	dbc.Log.TriggerContext(ctx, NewQueryEvent(outcome, time.Since(started),
+		OptQueryEventDatabase(dbc.Config.DatabaseOrDefault()),
+		OptQueryEventEngine(dbc.Config.EngineOrDefault()),
+		OptQueryEventUsername(dbc.Config.Username),
+		OptQueryEventLabel(options.LabelOrDefault()),
+		OptQueryEventAttempt(attempt),
+		OptQueryEventErr(err),
+	))
+}
@@ -0,0 +1,117 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/ex"
+	"github.com/zpkg/blend-go-sdk/logger"
+	"github.com/zpkg/blend-go-sdk/retry"
+)
+
+func Test_IsRetryableTxError(t *testing.T) {
+	its := assert.New(t)
+
+	its.False(IsRetryableTxError(nil))
+	its.False(IsRetryableTxError(fmt.Errorf("not a pg error")))
+	its.False(IsRetryableTxError(&pgconn.PgError{Code: "23505"}))
+	its.True(IsRetryableTxError(&pgconn.PgError{Code: SQLStateSerializationFailure}))
+	its.True(IsRetryableTxError(Error(&pgconn.PgError{Code: SQLStateDeadlockDetected})))
+	its.True(IsRetryableTxError(ex.New("outer", ex.OptInner(&pgconn.PgError{Code: SQLStateSerializationFailure}))))
+}
+
+func Test_Connection_InTx_useBeforeOpen(t *testing.T) {
+	its := assert.New(t)
+
+	conn, err := New()
+	its.Nil(err)
+	err = conn.InTx(context.Background(), func(_ *Invocation) error { return nil })
+	its.True(IsConnectionClosed(err))
+}
+
+func createInTxTable(its *assert.Assertions) {
+	its.Nil(IgnoreExecResult(defaultDB().Exec("DROP TABLE IF EXISTS in_tx_test")))
+	its.Nil(IgnoreExecResult(defaultDB().Exec("CREATE TABLE in_tx_test (id int not null primary key)")))
+}
+
+func countInTxRows(its *assert.Assertions) (count int) {
+	_, err := defaultDB().Query("SELECT count(*) FROM in_tx_test").Scan(&count)
+	its.Nil(err)
+	return
+}
+
+func Test_Connection_InTx(t *testing.T) {
+	its := assert.New(t)
+
+	createInTxTable(its)
+	defer func() { _ = IgnoreExecResult(defaultDB().Exec("DROP TABLE IF EXISTS in_tx_test")) }()
+
+	err := defaultDB().InTx(context.Background(), func(i *Invocation) error {
+		return IgnoreExecResult(i.Exec("INSERT INTO in_tx_test (id) VALUES (1)"))
+	})
+	its.Nil(err)
+	its.Equal(1, countInTxRows(its))
+
+	err = defaultDB().InTx(context.Background(), func(i *Invocation) error {
+		its.Nil(IgnoreExecResult(i.Exec("INSERT INTO in_tx_test (id) VALUES (2)")))
+		return fmt.Errorf("rollback")
+	})
+	its.Equal("rollback", ex.ErrClass(err).Error())
+	its.Equal(1, countInTxRows(its))
+
+	err = defaultDB().InTx(context.Background(), func(i *Invocation) error {
+		its.Nil(IgnoreExecResult(i.Exec("INSERT INTO in_tx_test (id) VALUES (3)")))
+		panic("at the disco")
+	})
+	its.NotNil(err)
+	its.Equal(1, countInTxRows(its))
+}
+
+func Test_Connection_InTx_retry(t *testing.T) {
+	its := assert.New(t)
+
+	createInTxTable(its)
+	defer func() { _ = IgnoreExecResult(defaultDB().Exec("DROP TABLE IF EXISTS in_tx_test")) }()
+
+	buf := new(bytes.Buffer)
+	conn := *defaultDB()
+	conn.Log = logger.Memory(buf)
+
+	var attempts int
+	err := conn.InTx(context.Background(), func(i *Invocation) error {
+		attempts++
+		if err := IgnoreExecResult(i.Exec("INSERT INTO in_tx_test (id) VALUES (1)")); err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return Error(&pgconn.PgError{Code: SQLStateSerializationFailure})
+		}
+		return nil
+	}, OptTxDelayProvider(retry.ConstantDelay(0)), OptTxLabel("test_in_tx"))
+	its.Nil(err)
+	its.Equal(3, attempts)
+	its.Equal(1, countInTxRows(its))
+	its.Contains(buf.String(), "[test_in_tx] COMMIT attempt 3")
+	its.Equal(3, strings.Count(buf.String(), "[test_in_tx]"))
+
+	attempts = 0
+	err = conn.InTx(context.Background(), func(i *Invocation) error {
+		attempts++
+		return Error(&pgconn.PgError{Code: SQLStateDeadlockDetected})
+	}, OptTxDelayProvider(retry.ConstantDelay(0)), OptTxMaxAttempts(2))
+	its.True(IsRetryableTxError(err))
+	its.Equal(2, attempts)
+}
@@ -0,0 +1,92 @@
+/*
+
+Copyright (c) 2022 - Present. Blend Labs, Inc. All rights reserved
+Use of this source code is governed by a MIT license that can be found in the LICENSE file.
+
+*/
+
+package db
+
+// Interface assertions.
+var (
+	_ StatementBuilder = (*UpdateBuilder)(nil)
+)
+
+// Update returns an update statement builder for a given table.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{
+		table: table,
+	}
+}
+
+// UpdateBuilder builds update statements.
+type UpdateBuilder struct {
+	label     string
+	table     string
+	set       []updateAssignment
+	where     []Predicate
+	returning []string
+}
+
+type updateAssignment struct {
+	column string
+	value  interface{}
+}
+
+// WithLabel sets the label for the statement.
+func (ub *UpdateBuilder) WithLabel(label string) *UpdateBuilder {
+	ub.label = label
+	return ub
+}
+
+// Set adds a column assignment.
+func (ub *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	ub.set = append(ub.set, updateAssignment{column: column, value: value})
+	return ub
+}
+
+// Where adds predicates to the where clause; all predicates must be true.
+func (ub *UpdateBuilder) Where(predicates ...Predicate) *UpdateBuilder {
+	ub.where = append(ub.where, predicates...)
+	return ub
+}
+
+// Returning sets the columns to return from the updated rows.
+//
+// Mysql does not support returning clauses; the clause is not built for the mysql dialect,
+// and the statement cannot be run with `Invocation.QueryStatement`.
+func (ub *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	ub.returning = columns
+	return ub
+}
+
+func (ub *UpdateBuilder) hasReturning() bool {
+	return len(ub.returning) > 0
+}
+
+// Label implements StatementBuilder.
+func (ub *UpdateBuilder) Label() string {
+	if ub.label != "" {
+		return ub.label
+	}
+	return ub.table + "_update"
+}
+
+// Build implements StatementBuilder.
+func (ub *UpdateBuilder) Build(dialect Dialect) (string, []interface{}) {
+	sw := newStatementWriter(dialect)
+	sw.WriteString("UPDATE ")
+	sw.WriteIdentifier(ub.table)
+	sw.WriteString(" SET ")
+	for index, assignment := range ub.set {
+		if index > 0 {
+			sw.WriteString(",")
+		}
+		sw.WriteIdentifier(assignment.column)
+		sw.WriteString("=")
+		sw.WriteArg(assignment.value)
+	}
+	sw.WriteWhere(ub.where)
+	sw.WriteReturning(ub.returning)
+	return sw.Result()
+}
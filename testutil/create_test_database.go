@@ -10,6 +10,8 @@ package testutil
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/zpkg/blend-go-sdk/db"
 	"github.com/zpkg/blend-go-sdk/db/dbutil"
@@ -17,11 +19,11 @@ import (
 )
 
 // CreateTestDatabase creates a randomized test database.
+//
+// For the sqlite dialect, e.g. with `db.OptSQLite("")`, the test database is a file in the temp directory,
+// and the `db/sqlite` package must be imported to register the driver.
 func CreateTestDatabase(ctx context.Context, opts ...db.Option) (*db.Connection, error) {
 	databaseName := fmt.Sprintf("testdb_%s", uuid.V4().String())
-	if err := dbutil.CreateDatabase(ctx, databaseName, opts...); err != nil {
-		return nil, err
-	}
 
 	defaults := []db.Option{
 		db.OptHost("localhost"),
@@ -36,6 +38,14 @@ func CreateTestDatabase(ctx context.Context, opts ...db.Option) (*db.Connection,
 	if err != nil {
 		return nil, err
 	}
+	if conn.Config.DialectOrDefault().Is(db.DialectSQLite) {
+		databaseName = filepath.Join(os.TempDir(), databaseName+".db")
+		conn.Config.Database = databaseName
+	}
+
+	if err = dbutil.CreateDatabase(ctx, databaseName, opts...); err != nil {
+		return nil, err
+	}
 	err = conn.Open()
 	if err != nil {
 		return nil, err
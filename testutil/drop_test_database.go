@@ -27,6 +27,9 @@ func DropTestDatabase(ctx context.Context, conn *db.Connection, opts ...db.Optio
 	if err != nil {
 		return
 	}
+	if config.DialectOrDefault().Is(db.DialectSQLite) {
+		return dbutil.DropDatabase(ctx, config.Database, append([]db.Option{db.OptDialect(db.DialectSQLite)}, opts...)...)
+	}
 
 	mgmt, err = dbutil.OpenManagementConnection(opts...)
 	if err != nil {
@@ -9,14 +9,19 @@ package testutil
 
 import (
 	"context"
+
+	"github.com/zpkg/blend-go-sdk/db"
 )
 
 // OptWithDefaultDB runs a test suite with a dedicated database connection.
-func OptWithDefaultDB() Option {
+//
+// The given options are applied to the test database connection, e.g. `db.OptSQLite("")`
+// to run the suite against an embedded sqlite database instead of postgres.
+func OptWithDefaultDB(opts ...db.Option) Option {
 	return func(s *Suite) {
 		var err error
 		s.Before = append(s.Before, func(ctx context.Context) error {
-			_defaultDB, err = CreateTestDatabase(ctx)
+			_defaultDB, err = CreateTestDatabase(ctx, opts...)
 			if err != nil {
 				return err
 			}
@@ -26,7 +31,7 @@ func OptWithDefaultDB() Option {
 			if err := _defaultDB.Close(); err != nil {
 				return err
 			}
-			return DropTestDatabase(ctx, _defaultDB)
+			return DropTestDatabase(ctx, _defaultDB, opts...)
 		})
 	}
 }
@@ -16,12 +16,14 @@ import (
 // OptWithDefaultDBs runs a test suite with a count of database connections.
 // Note: this type of connection pool is used in rare circumstances for
 // performance reasons; you probably want to use `OptWithDefaultDB` for your tests.
-func OptWithDefaultDBs(count int) Option {
+//
+// The given options are applied to each test database connection.
+func OptWithDefaultDBs(count int, opts ...db.Option) Option {
 	return func(s *Suite) {
 		s.Before = append(s.Before, func(ctx context.Context) error {
 			_defaultDBs = make([]*db.Connection, count)
 			for index := 0; index < count; index++ {
-				conn, err := CreateTestDatabase(ctx)
+				conn, err := CreateTestDatabase(ctx, opts...)
 				if err != nil {
 					return err
 				}
@@ -34,7 +36,7 @@ func OptWithDefaultDBs(count int) Option {
 				if err := _defaultDBs[index].Close(); err != nil {
 					return err
 				}
-				if err := DropTestDatabase(ctx, _defaultDBs[index]); err != nil {
+				if err := DropTestDatabase(ctx, _defaultDBs[index], opts...); err != nil {
 					return err
 				}
 			}
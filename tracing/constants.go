@@ -95,6 +95,8 @@ const (
 	OperationSQLPrepare = "sql.prepare"
 	// OperationDBQuery is the db query tracing operation.
 	OperationSQLQuery = "sql.query"
+	// OperationSQLNotification is the db notification delivery tracing operation.
+	OperationSQLNotification = "sql.notification"
 	// OperationJob is a job operation.
 	OperationJob = "job"
 	// OperationDial is a network jdial operation.
@@ -11,4 +11,6 @@ package dbtrace
 const (
 	TagKeyQuery      = "db.query"
 	TagKeySQLCommand = "sql.command"
+	// TagKeyNotificationChannel is the channel of a delivered notification.
+	TagKeyNotificationChannel = "db.notification.channel"
 )
@@ -20,7 +20,8 @@ import (
 )
 
 var (
-	_ db.Tracer = (*dbTracer)(nil)
+	_ db.Tracer             = (*dbTracer)(nil)
+	_ db.NotificationTracer = (*dbTracer)(nil)
 )
 
 // Tracer returns a db tracer.
@@ -61,6 +62,20 @@ func (dbt dbTracer) Query(ctx context.Context, cfg db.Config, label, statement s
 	return dbTraceFinisher{span: span}
 }
 
+func (dbt dbTracer) Notification(ctx context.Context, cfg db.Config, n db.Notification) db.NotificationTraceFinisher {
+	startOptions := []opentracing.StartSpanOption{
+		opentracing.Tag{Key: tracing.TagKeyResourceName, Value: n.Channel},
+		opentracing.Tag{Key: tracing.TagKeySpanType, Value: tracing.SpanTypeSQL},
+		opentracing.Tag{Key: tracing.TagKeyDBName, Value: cfg.DatabaseOrDefault()},
+		opentracing.Tag{Key: tracing.TagKeyDBUser, Value: cfg.Username},
+		opentracing.Tag{Key: TagKeyNotificationChannel, Value: n.Channel},
+		tracing.TagMeasured(),
+		opentracing.StartTime(time.Now().UTC()),
+	}
+	span, _ := tracing.StartSpanFromContext(ctx, dbt.tracer, tracing.OperationSQLNotification, startOptions...)
+	return dbTraceFinisher{span: span}
+}
+
 type dbTraceFinisher struct {
 	span opentracing.Span
 }
@@ -90,3 +105,11 @@ func (dbtf dbTraceFinisher) FinishQuery(ctx context.Context, res sql.Result, err
 	tracing.SpanError(dbtf.span, err)
 	dbtf.span.Finish()
 }
+
+func (dbtf dbTraceFinisher) FinishNotification(ctx context.Context, err error) {
+	if dbtf.span == nil {
+		return
+	}
+	tracing.SpanError(dbtf.span, err)
+	dbtf.span.Finish()
+}
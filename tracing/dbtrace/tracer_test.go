@@ -17,6 +17,7 @@ import (
 	"github.com/opentracing/opentracing-go/mocktracer"
 
 	"github.com/zpkg/blend-go-sdk/assert"
+	"github.com/zpkg/blend-go-sdk/db"
 	"github.com/zpkg/blend-go-sdk/tracing"
 )
 
@@ -205,3 +206,24 @@ func TestFinishQueryNil(t *testing.T) {
 	dbtf.FinishQuery(context.TODO(), nil, nil)
 	assert.Nil(dbtf.span)
 }
+
+func TestNotification(t *testing.T) {
+	assert := assert.New(t)
+	mockTracer := mocktracer.New()
+	dbTracer := Tracer(mockTracer).(db.NotificationTracer)
+
+	dbCfg, err := defaultDB().Config.Reparse()
+	assert.Nil(err)
+
+	dbtf := dbTracer.Notification(context.Background(), dbCfg, db.Notification{Channel: "test_channel", Payload: "test_payload"})
+	span := dbtf.(dbTraceFinisher).span
+	mockSpan := span.(*mocktracer.MockSpan)
+	assert.Equal(tracing.OperationSQLNotification, mockSpan.OperationName)
+	assert.Equal("test_channel", mockSpan.Tags()[tracing.TagKeyResourceName])
+	assert.Equal("test_channel", mockSpan.Tags()[TagKeyNotificationChannel])
+	assert.True(mockSpan.FinishTime.IsZero())
+
+	dbtf.FinishNotification(context.Background(), fmt.Errorf("error"))
+	assert.Equal("error", mockSpan.Tags()[tracing.TagKeyError])
+	assert.False(mockSpan.FinishTime.IsZero())
+}